@@ -3,13 +3,15 @@ package cassandra
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/core-go/video"
 	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
 	"github.com/gocql/gocql"
 )
 
@@ -21,36 +23,48 @@ type CassandraVideoService struct {
 	videoFieldsIndex         map[string]int
 	playlistVideoFieldsIndex map[string]int
 	categoryFieldsIndex      map[string]int
+	statisticsFieldsIndex    map[string]int
+	subscriptionFieldsIndex  map[string]int
 }
 
-func NewCassandraVideoService(session *gocql.Session, tubeCategory category.CategorySyncClient) (*CassandraVideoService,error) {
+func NewCassandraVideoService(session *gocql.Session, tubeCategory category.CategorySyncClient) (*CassandraVideoService, error) {
 	var channel video.Channel
 	channelReflect := reflect.TypeOf(channel)
-	channelFieldsIndex,err := GetColumnIndexes(channelReflect)
+	channelFieldsIndex, err := GetColumnIndexes(channelReflect)
 	if err != nil {
 		return nil, err
 	}
 	var playlist video.Playlist
 	playlistReflect := reflect.TypeOf(playlist)
-	playlistFieldsIndex,err := GetColumnIndexes(playlistReflect)
+	playlistFieldsIndex, err := GetColumnIndexes(playlistReflect)
 	if err != nil {
 		return nil, err
 	}
 	var videoV video.Video
 	videoReflect := reflect.TypeOf(videoV)
-	videoFieldsIndex,err := GetColumnIndexes(videoReflect)
+	videoFieldsIndex, err := GetColumnIndexes(videoReflect)
 	if err != nil {
 		return nil, err
 	}
 	var playlistVideo video.PlaylistVideoIdVideos
 	playlistVideoReflect := reflect.TypeOf(playlistVideo)
-	playlistVideoFieldsIndex,err := GetColumnIndexes(playlistVideoReflect)
+	playlistVideoFieldsIndex, err := GetColumnIndexes(playlistVideoReflect)
 	if err != nil {
 		return nil, err
 	}
 	var category video.Categories
 	categoryReflect := reflect.TypeOf(category)
-	categoryFieldsIndex,err := GetColumnIndexes(categoryReflect)
+	categoryFieldsIndex, err := GetColumnIndexes(categoryReflect)
+	if err != nil {
+		return nil, err
+	}
+	var statistics video.VideoStatistics
+	statisticsFieldsIndex, err := GetColumnIndexes(reflect.TypeOf(statistics))
+	if err != nil {
+		return nil, err
+	}
+	var subscription video.Subscription
+	subscriptionFieldsIndex, err := GetColumnIndexes(reflect.TypeOf(subscription))
 	if err != nil {
 		return nil, err
 	}
@@ -62,10 +76,15 @@ func NewCassandraVideoService(session *gocql.Session, tubeCategory category.Cate
 		videoFieldsIndex:         videoFieldsIndex,
 		playlistVideoFieldsIndex: playlistVideoFieldsIndex,
 		categoryFieldsIndex:      categoryFieldsIndex,
-	},nil
+		statisticsFieldsIndex:    statisticsFieldsIndex,
+		subscriptionFieldsIndex:  subscriptionFieldsIndex,
+	}, nil
 }
 
 func (c *CassandraVideoService) GetChannel(ctx context.Context, channelId string, fields []string) (*video.Channel, error) {
+	if err := validateFields(fields, c.channelFieldsIndex); err != nil {
+		return nil, err
+	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
@@ -75,13 +94,19 @@ func (c *CassandraVideoService) GetChannel(ctx context.Context, channelId string
 	if err != nil {
 		return nil, err
 	}
-	if len(channel) <= 0{
+	if len(channel) <= 0 {
 		return nil, nil
 	}
 	return &channel[0], nil
 }
 
 func (c *CassandraVideoService) GetChannels(ctx context.Context, ids []string, fields []string) (*[]video.Channel, error) {
+	if err := validateFields(fields, c.channelFieldsIndex); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Channel{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -93,7 +118,7 @@ func (c *CassandraVideoService) GetChannels(ctx context.Context, ids []string, f
 	}
 	query := fmt.Sprintf(`select %s from channel where id in (%s)`, strings.Join(fields, ","), strings.Join(question, ","))
 	var channel []video.Channel
-	err := Query(c.session, c.channelFieldsIndex,&channel, query, cc...)
+	err := Query(c.session, c.channelFieldsIndex, &channel, query, cc...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,12 +126,15 @@ func (c *CassandraVideoService) GetChannels(ctx context.Context, ids []string, f
 }
 
 func (c *CassandraVideoService) GetPlaylist(ctx context.Context, id string, fields []string) (*video.Playlist, error) {
+	if err := validateFields(fields, c.playlistFieldsIndex); err != nil {
+		return nil, err
+	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
 	query := fmt.Sprintf(`select %s from playlist where id = ?`, strings.Join(fields, ","))
 	var playlist []video.Playlist
-	err := Query(c.session, c.playlistFieldsIndex,&playlist, query, id)
+	err := Query(c.session, c.playlistFieldsIndex, &playlist, query, id)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +145,12 @@ func (c *CassandraVideoService) GetPlaylist(ctx context.Context, id string, fiel
 }
 
 func (c *CassandraVideoService) GetPlaylists(ctx context.Context, ids []string, fields []string) (*[]video.Playlist, error) {
+	if err := validateFields(fields, c.playlistFieldsIndex); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Playlist{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -136,6 +170,9 @@ func (c *CassandraVideoService) GetPlaylists(ctx context.Context, ids []string,
 }
 
 func (c *CassandraVideoService) GetVideo(ctx context.Context, id string, fields []string) (*video.Video, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
@@ -146,12 +183,18 @@ func (c *CassandraVideoService) GetVideo(ctx context.Context, id string, fields
 		return nil, err
 	}
 	if len(video) <= 0 {
-		return nil,nil
+		return nil, nil
 	}
 	return &video[0], nil
 }
 
 func (c *CassandraVideoService) GetVideos(ctx context.Context, ids []string, fields []string) (*[]video.Video, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Video{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -171,6 +214,9 @@ func (c *CassandraVideoService) GetVideos(ctx context.Context, ids []string, fie
 }
 
 func (c *CassandraVideoService) GetChannelPlaylists(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
+	if err := validateFields(fields, c.playlistFieldsIndex); err != nil {
+		return nil, err
+	}
 	sort := map[string]interface{}{"field": `publishedat`, "reverse": true}
 	must := map[string]interface{}{"type": "match", "field": "channelid", "value": fmt.Sprintf(`%s`, channelId)}
 	a := map[string]interface{}{
@@ -186,10 +232,10 @@ func (c *CassandraVideoService) GetChannelPlaylists(ctx context.Context, channel
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
-	sql := fmt.Sprintf(`select %s from playlist where expr(playlist_index, '%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from playlist where expr(playlist_index, '%s')`, strings.Join(fields, ","), quote(queryObj))
 	var listResultPlaylist video.ListResultPlaylist
 	var value []interface{}
-	listResultPlaylist.NextPageToken,err = QueryWithPage(c.session, c.playlistFieldsIndex, &listResultPlaylist.List, sql, value, max, nextPageToken)
+	listResultPlaylist.NextPageToken, err = QueryWithCursor(c.session, c.playlistFieldsIndex, &listResultPlaylist.List, sql, value, max, "date", nextPageToken)
 	if err != nil {
 		return nil, err
 	}
@@ -197,14 +243,24 @@ func (c *CassandraVideoService) GetChannelPlaylists(ctx context.Context, channel
 	return &listResultPlaylist, nil
 }
 
-func (c *CassandraVideoService) GetChannelVideos(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+func (c *CassandraVideoService) GetChannelVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
 	sort := map[string]interface{}{"field": `publishedat`, "reverse": true}
-	must := map[string]interface{}{"type": "match", "field": "channelid", "value": fmt.Sprintf(`%s`, channelId)}
+	must := []interface{}{map[string]interface{}{"type": "match", "field": "channelid", "value": fmt.Sprintf(`%s`, channelId)}}
+	if len(regionCode) > 0 {
+		must = append(must, available(regionCode))
+	}
+	filter := map[string]interface{}{
+		"must": must,
+	}
+	if !video.IncludeUnavailable(ctx) {
+		filter["not"] = []interface{}{tombstoned()}
+	}
 	a := map[string]interface{}{
-		"filter": map[string]interface{}{
-			"must": must,
-		},
-		"sort": sort,
+		"filter": filter,
+		"sort":   sort,
 	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
@@ -213,10 +269,10 @@ func (c *CassandraVideoService) GetChannelVideos(ctx context.Context, channelId
 	if err != nil {
 		return nil, err
 	}
-	sql := fmt.Sprintf(`select %s from video where expr(video_index, '%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from video where expr(video_index, '%s')`, strings.Join(fields, ","), quote(queryObj))
 	var resList video.ListResultVideos
 	var value []interface{}
-	resList.NextPageToken,err = QueryWithPage(c.session, c.videoFieldsIndex, &resList.List, sql, value, max, nextPageToken)
+	resList.NextPageToken, err = QueryWithCursor(c.session, c.videoFieldsIndex, &resList.List, sql, value, max, "date", nextPageToken)
 	if err != nil {
 		return nil, err
 	}
@@ -224,44 +280,83 @@ func (c *CassandraVideoService) GetChannelVideos(ctx context.Context, channelId
 	return &resList, nil
 }
 
-func (c *CassandraVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	var sql = `select * from playlistVideo where id = ?`
-	var playlistVideo []video.PlaylistVideoIdVideos
-	er1 := Query(c.session, c.playlistVideoFieldsIndex,&playlistVideo, sql, playlistId)
-	if er1 != nil {
+// GetPlaylistVideos orders and filters the items in Go, as playlistItem has no position to sort by.
+func (c *CassandraVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	items, er1 := c.getPlaylistItems(playlistId)
+	if er1 != nil || items == nil {
 		return nil, er1
 	}
-	if len(playlistVideo) == 0 {
-		return nil, nil
+	if max <= 0 {
+		max = 12
 	}
-	question := make([]string, len(playlistVideo[0].Videos))
-	cc := make([]interface{}, len(playlistVideo[0].Videos))
-	for i, v := range playlistVideo[0].Videos {
-		question[i] = "?"
-		cc[i] = v
+	if len(fields) > 0 {
+		fields = withColumns(fields, "id")
+		if len(regionCode) > 0 {
+			fields = withColumns(fields, "allowedRegions", "blockedRegions")
+		}
+		if !video.IncludeUnavailable(ctx) {
+			fields = withColumns(fields, "status")
+		}
 	}
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
+	return video.PagePlaylist(video.PlaylistOrder(items), max, nextPageToken, func(ids []string) ([]video.Video, error) {
+		videos, er2 := c.GetVideos(ctx, ids, fields)
+		if er2 != nil {
+			return nil, er2
+		}
+		var res []video.Video
+		for _, v := range *videos {
+			if video.Available(v, regionCode) && video.Listed(ctx, v) {
+				res = append(res, v)
+			}
+		}
+		return res, nil
+	})
+}
+
+// getPlaylistItems falls back to the video ids of a playlist synced before items were stored.
+func (c *CassandraVideoService) getPlaylistItems(playlistId string) ([]video.PlaylistItem, error) {
+	var items []video.PlaylistItem
+	er1 := Query(c.session, nil, &items, `select * from playlistItem where playlistId = ?`, playlistId)
+	if er1 != nil || len(items) > 0 {
+		return items, er1
 	}
-	queryV := fmt.Sprintf(`select %s from video where id in (%s) limit %d`, strings.Join(fields, ","), strings.Join(question, ","), max)
-	var res video.ListResultVideos
-	res.NextPageToken,er1 = QueryWithPage(c.session, c.videoFieldsIndex, &res.List, queryV, cc, max, nextPageToken)
-	if er1 != nil {
-		return nil, er1
+	var playlistVideo []video.PlaylistVideoIdVideos
+	er2 := Query(c.session, c.playlistVideoFieldsIndex, &playlistVideo, `select * from playlistVideo where id = ?`, playlistId)
+	if er2 != nil || len(playlistVideo) == 0 {
+		return nil, er2
 	}
-	res.Limit = max
-	return &res, nil
+	return video.NewPlaylistItems(playlistId, playlistVideo[0].Videos, nil), nil
+}
+
+func withColumns(fields []string, columns ...string) []string {
+	res := append([]string{}, fields...)
+	for _, column := range columns {
+		found := false
+		for _, field := range fields {
+			if strings.EqualFold(field, column) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, column)
+		}
+	}
+	return res
 }
 
 func (c *CassandraVideoService) GetCategories(ctx context.Context, regionCode string) (*video.Categories, error) {
 	sql := `select * from category where id = ?`
 	var categories []video.Categories
-	err := Query(c.session, c.categoryFieldsIndex,&categories, sql, regionCode)
+	err := Query(c.session, c.categoryFieldsIndex, &categories, sql, regionCode)
 	if err != nil {
 		return nil, err
 	}
 	if len(categories) == 0 {
-		res, er1 := c.tubeCategory.GetCagetories(regionCode)
+		res, er1 := c.tubeCategory.GetCagetories(ctx, regionCode)
 		if er1 != nil {
 			return nil, er1
 		}
@@ -280,14 +375,20 @@ func (c *CassandraVideoService) GetCategories(ctx context.Context, regionCode st
 }
 
 func (c *CassandraVideoService) SearchChannel(ctx context.Context, channelSM video.ChannelSM, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
-	sql, err := buildChannelSearch(channelSM, fields)
+	if err := validateFields(fields, c.channelFieldsIndex); err != nil {
+		return nil, err
+	}
+	keys, err := video.ParseSort(channelSM.Sort, video.ChannelSortable, channelSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	sql, err := buildChannelSearch(channelSM, keys, fields)
 	if err != nil {
 		return nil, err
 	}
-	sql = sql + fmt.Sprintf(` limit %d`, max)
 	var res video.ListResultChannel
 	var value []interface{}
-	res.NextPageToken, err = QueryWithPage(c.session, c.channelFieldsIndex, &res.List, sql, value, max, nextPageToken)
+	res.NextPageToken, err = QueryWithCursor(c.session, c.channelFieldsIndex, &res.List, sql, value, max, video.FormatSort(keys), nextPageToken)
 	res.Limit = max
 	if err != nil {
 		return nil, err
@@ -296,14 +397,20 @@ func (c *CassandraVideoService) SearchChannel(ctx context.Context, channelSM vid
 }
 
 func (c *CassandraVideoService) SearchPlaylists(ctx context.Context, playlistSM video.PlaylistSM, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
-	sql, err := buildPlaylistSearch(playlistSM, fields)
+	if err := validateFields(fields, c.playlistFieldsIndex); err != nil {
+		return nil, err
+	}
+	keys, err := video.ParseSort(playlistSM.Sort, video.PlaylistSortable, playlistSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	sql, err := buildPlaylistSearch(playlistSM, keys, fields)
 	if err != nil {
 		return nil, err
 	}
-	sql = sql + fmt.Sprintf(` limit %d`, max)
 	var res video.ListResultPlaylist
 	var value []interface{}
-	res.NextPageToken, err = QueryWithPage(c.session, c.playlistFieldsIndex, &res.List, sql, value, max, nextPageToken)
+	res.NextPageToken, err = QueryWithCursor(c.session, c.playlistFieldsIndex, &res.List, sql, value, max, video.FormatSort(keys), nextPageToken)
 	res.Limit = max
 	if err != nil {
 		return nil, err
@@ -312,14 +419,31 @@ func (c *CassandraVideoService) SearchPlaylists(ctx context.Context, playlistSM
 }
 
 func (c *CassandraVideoService) SearchVideos(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	sql, err := buildVideosSearch(itemSM, fields)
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	keys, err := video.ParseSort(itemSM.Sort, video.VideoSortable, itemSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	var related []string
+	if len(itemSM.RelatedToVideoId) > 0 {
+		v, err := c.GetVideo(ctx, itemSM.RelatedToVideoId, []string{"tags"})
+		if err != nil {
+			return nil, err
+		}
+		if v == nil || len(v.Tags) == 0 {
+			return &video.ListResultVideos{Limit: max}, nil
+		}
+		related = v.Tags
+	}
+	sql, err := buildVideosSearch(itemSM, related, video.IncludeUnavailable(ctx), keys, fields)
 	if err != nil {
 		return nil, err
 	}
-	sql = sql + fmt.Sprintf(` limit %d`, max)
 	var res video.ListResultVideos
 	var value []interface{}
-	res.NextPageToken, err = QueryWithPage(c.session, c.videoFieldsIndex, &res.List, sql, value, max, nextPageToken)
+	res.NextPageToken, err = QueryWithCursor(c.session, c.videoFieldsIndex, &res.List, sql, value, max, video.FormatSort(keys), nextPageToken)
 	if err != nil {
 		return nil, err
 	}
@@ -327,80 +451,247 @@ func (c *CassandraVideoService) SearchVideos(ctx context.Context, itemSM video.I
 	return &res, nil
 }
 
-func (c *CassandraVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	sql, err := buildVideosSearch(itemSM, fields)
+// Search merges the tables; a page token holds the page state and offset of each.
+func (c *CassandraVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultSearch, error) {
+	kinds, err := video.SearchKinds(itemSM)
 	if err != nil {
 		return nil, err
 	}
-	var res video.ListResultVideos
-	var value []interface{}
-	res.NextPageToken, err = QueryWithPage(c.session, c.videoFieldsIndex, &res.List, sql, value, max, nextPageToken)
+	for _, field := range fields {
+		if validateFields([]string{field}, c.channelFieldsIndex) != nil && validateFields([]string{field}, c.playlistFieldsIndex) != nil && validateFields([]string{field}, c.videoFieldsIndex) != nil {
+			return nil, fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
+	}
+	keys, err := video.ParseSort(itemSM.Sort, video.SearchSortable, itemSM.Q)
 	if err != nil {
 		return nil, err
 	}
-	res.Limit = max
-	return &res, nil
-}
-
-func (c *CassandraVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	var a []string
-	resVd, err := c.GetVideo(ctx, videoId, a)
+	name := video.FormatSort(keys)
+	last, err := cursor.Decode(nextPageToken, name)
 	if err != nil {
 		return nil, err
 	}
-	if resVd == nil {
-		return nil, errors.New("video don't exist")
-	} else {
-		var should []interface{}
-		for _, v := range resVd.Tags {
-			should = append(should, map[string]interface{}{"type": "contains", "field": "tags", "values": v})
+	if max <= 0 {
+		max = 12
+	}
+	var sources []*searchSource
+	for _, kind := range kinds {
+		source := &searchSource{kind: kind}
+		switch kind {
+		case video.KindChannel:
+			source.fieldsIndex, source.modelType = c.channelFieldsIndex, reflect.TypeOf(video.Channel{})
+			source.sql, err = buildChannelSearch(video.ToChannelSM(itemSM), keys, searchFields(fields, source.fieldsIndex, keys))
+		case video.KindPlaylist:
+			source.fieldsIndex, source.modelType = c.playlistFieldsIndex, reflect.TypeOf(video.Playlist{})
+			source.sql, err = buildPlaylistSearch(video.ToPlaylistSM(itemSM), keys, searchFields(fields, source.fieldsIndex, keys))
+		default:
+			var related []string
+			if len(itemSM.RelatedToVideoId) > 0 {
+				v, err := c.GetVideo(ctx, itemSM.RelatedToVideoId, []string{"tags"})
+				if err != nil {
+					return nil, err
+				}
+				if v == nil || len(v.Tags) == 0 {
+					continue
+				}
+				related = v.Tags
+			}
+			source.fieldsIndex, source.modelType = c.videoFieldsIndex, reflect.TypeOf(video.Video{})
+			source.sql, err = buildVideosSearch(itemSM, related, video.IncludeUnavailable(ctx), keys, searchFields(fields, source.fieldsIndex, keys))
 		}
-		not := map[string]interface{}{"type": "match", "field": "id", "value": videoId}
-		sort := map[string]interface{}{"field": "publishedat", "reverse": true}
-		fields = checkFields("publishedAt", fields)
-		a := map[string]interface{}{
-			"filter": map[string]interface{}{
-				"should": should,
-				"not":    not,
-			},
-			"sort": sort,
+		if err != nil {
+			return nil, err
 		}
-		queryObj, err := json.Marshal(a)
+		if last != nil {
+			position, ok := last.Sources[kind]
+			if !ok {
+				return nil, cursor.ErrInvalid
+			}
+			source.position = position
+		}
+		sources = append(sources, source)
+	}
+	res := video.ListResultSearch{Limit: max}
+	for len(res.List) < max {
+		var first *searchSource
+		for _, source := range sources {
+			if err := source.fill(c.session, max); err != nil {
+				return nil, err
+			}
+			if len(source.buffer) > 0 && (first == nil || before(keys, source.buffer[0], first.buffer[0])) {
+				first = source
+			}
+		}
+		if first == nil {
+			break
+		}
+		res.List = append(res.List, first.pop().result)
+	}
+	next := cursor.Cursor{Sort: name, Sources: make(map[string]cursor.Source)}
+	more := false
+	for _, source := range sources {
+		if err := source.fill(c.session, max); err != nil {
+			return nil, err
+		}
+		more = more || len(source.buffer) > 0
+		next.Sources[source.kind] = source.position
+	}
+	if more {
+		token, err := cursor.Encode(next)
 		if err != nil {
 			return nil, err
 		}
-		if len(fields) <= 0 {
-			fields = append(fields, "*")
+		res.NextPageToken = token
+	}
+	res.Total = len(res.List)
+	return &res, nil
+}
+
+// GetRelatedVideos keeps the time of the ranking in the page token, so every page ranks alike.
+func (c *CassandraVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	last, er0 := cursor.Decode(nextPageToken, "related")
+	if er0 != nil {
+		return nil, er0
+	}
+	now := time.Now().UTC()
+	var lastScore float64
+	if last != nil {
+		if last.Since == nil || len(last.Values) != 1 {
+			return nil, cursor.ErrInvalid
+		}
+		v, err := cursor.Parse(last.Values[0], reflect.TypeOf(lastScore))
+		if v == nil || err != nil {
+			return nil, cursor.ErrInvalid
 		}
-		sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(fields, ","), queryObj)
-		var res video.ListResultVideos
-		var value []interface{}
-		res.NextPageToken, err = QueryWithPage(c.session, c.videoFieldsIndex, &res.List, sql, value, max, nextPageToken)
+		now = *last.Since
+		lastScore = v.(float64)
+	}
+	seed, err := c.GetVideo(ctx, videoId, video.RelatedFields)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return nil, nil
+	}
+	var should []interface{}
+	for _, v := range seed.Tags {
+		should = append(should, map[string]interface{}{"type": "contains", "field": "tags", "values": v})
+	}
+	if len(seed.ChannelId) > 0 {
+		should = append(should, map[string]interface{}{"type": "match", "field": "channelid", "value": seed.ChannelId})
+	}
+	if len(seed.CategoryId) > 0 {
+		should = append(should, map[string]interface{}{"type": "match", "field": "categoryid", "value": seed.CategoryId})
+	}
+	for _, term := range video.TitleTerms(seed.Title) {
+		should = append(should, map[string]interface{}{"type": "wildcard", "field": "sorttitle", "value": "*" + term + "*"})
+	}
+	res := video.ListResultVideos{Limit: max}
+	if len(should) == 0 {
+		return &res, nil
+	}
+	not := []interface{}{map[string]interface{}{"type": "match", "field": "id", "value": videoId}}
+	if !video.IncludeUnavailable(ctx) {
+		not = append(not, tombstoned())
+	}
+	a := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"should": should,
+			"not":    not,
+		},
+	}
+	queryObj, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []video.Video
+	sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(video.RelatedFields, ","), quote(queryObj))
+	if err = Query(c.session, c.videoFieldsIndex, &candidates, sql); err != nil {
+		return nil, err
+	}
+	ranked := video.RankRelated(*seed, candidates, now, video.DefaultRelatedWeights)
+	if max <= 0 {
+		max = 12
 		res.Limit = max
+	}
+	skip := 0
+	if last != nil {
+		skip = video.RelatedAfter(ranked, lastScore, last.Id)
+	}
+	if skip >= len(ranked) {
 		return &res, nil
 	}
+	end := skip + max
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	page := ranked[skip:end]
+	ids := make([]string, len(page))
+	for i, r := range page {
+		ids[i] = r.Video.Id
+	}
+	if len(fields) > 0 {
+		fields = checkFields("id", fields)
+	}
+	videos, err := c.GetVideos(ctx, ids, fields)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]video.Video)
+	for _, v := range *videos {
+		byId[v.Id] = v
+	}
+	for _, id := range ids {
+		if v, ok := byId[id]; ok {
+			res.List = append(res.List, v)
+		}
+	}
+	if end < len(ranked) {
+		r := page[len(page)-1]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "related", Values: []interface{}{cursor.Value(reflect.ValueOf(r.Score))}, Id: r.Video.Id, Since: &now})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
 }
 
 func (c *CassandraVideoService) GetPopularVideos(ctx context.Context, regionCode string, categoryId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
 	var query []interface{}
-	var not []interface{}
+	var must []interface{}
 	if len(regionCode) > 0 {
-		not = append(not, map[string]interface{}{"type": "contains", "field": "blockedregions", "values": regionCode})
+		must = append(must, available(regionCode))
 	}
 	if len(categoryId) > 0 {
 		query = append(query, map[string]interface{}{"type": "match", "field": "categoryid", "value": categoryId})
 		fields = checkFields("categoryId", fields)
 	}
-	sort := map[string]interface{}{"field": "publishedat", "reverse": true}
+	sort := []interface{}{
+		map[string]interface{}{"field": "viewcount", "reverse": true},
+		map[string]interface{}{"field": "publishedat", "reverse": true},
+	}
+	fields = checkFields("viewCount", fields)
 	fields = checkFields("publishedAt", fields)
+	filter := map[string]interface{}{}
+	if len(must) > 0 {
+		filter["must"] = must
+	}
+	if !video.IncludeUnavailable(ctx) {
+		filter["not"] = []interface{}{tombstoned()}
+	}
 	a := map[string]interface{}{
-		"filter": map[string]interface{}{
-			"not": not,
-		},
-		"query": query,
-		"sort":  sort,
+		"filter": filter,
+		"query":  query,
+		"sort":   sort,
 	}
-	if len(not) == 0 {
+	if len(filter) == 0 {
 		delete(a, "filter")
 	}
 	if len(query) == 0 {
@@ -413,19 +704,294 @@ func (c *CassandraVideoService) GetPopularVideos(ctx context.Context, regionCode
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
-	sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(fields, ","), quote(queryObj))
 	var res video.ListResultVideos
 	var value []interface{}
-	res.NextPageToken, err = QueryWithPage(c.session, c.videoFieldsIndex, &res.List, sql, value, max, nextPageToken)
+	res.NextPageToken, err = QueryWithCursor(c.session, c.videoFieldsIndex, &res.List, sql, value, max, "viewCount", nextPageToken)
+	if err != nil {
+		return nil, err
+	}
 	res.Limit = max
 	return &res, nil
 }
 
-func buildChannelSearch(s video.ChannelSM, fields []string) (string, error) {
+// GetTrendingVideos ranks in memory and keeps the start of the window in the page token.
+func (c *CassandraVideoService) GetTrendingVideos(ctx context.Context, regionCode string, categoryId string, window time.Duration, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	last, er0 := cursor.Decode(nextPageToken, "trending")
+	if er0 != nil {
+		return nil, er0
+	}
+	since := time.Now().Add(-window).UTC()
+	var lastVelocity float64
+	if last != nil {
+		if last.Since == nil || len(last.Values) != 1 {
+			return nil, cursor.ErrInvalid
+		}
+		v, err := cursor.Parse(last.Values[0], reflect.TypeOf(lastVelocity))
+		if v == nil || err != nil {
+			return nil, cursor.ErrInvalid
+		}
+		since = *last.Since
+		lastVelocity = v.(float64)
+	}
+	filter := map[string]interface{}{
+		"filter": map[string]interface{}{"type": "range", "field": "timestamp", "lower": since.UTC().Format("2006-01-02 15:04:05"), "include_lower": true},
+	}
+	queryObj, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var statistics []video.VideoStatistics
+	err = Query(c.session, c.statisticsFieldsIndex, &statistics, fmt.Sprintf(`select * from videoStatistics where expr(video_statistics_index,'%s')`, quote(queryObj)))
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make(map[string][]video.VideoStatistics)
+	var ids []string
+	for _, v := range statistics {
+		if _, ok := snapshots[v.VideoId]; !ok {
+			ids = append(ids, v.VideoId)
+		}
+		snapshots[v.VideoId] = append(snapshots[v.VideoId], v)
+	}
+	candidates, err := c.GetVideos(ctx, ids, []string{"id", "categoryId", "allowedRegions", "blockedRegions", "publishedAt", "status"})
+	if err != nil {
+		return nil, err
+	}
+	velocities := make(map[string]float64)
+	var ranked []string
+	for _, v := range *candidates {
+		if len(categoryId) > 0 && v.CategoryId != categoryId {
+			continue
+		}
+		if !video.Available(v, regionCode) || !video.Listed(ctx, v) {
+			continue
+		}
+		if r, ok := velocity(snapshots[v.Id], v.PublishedAt, since); ok {
+			velocities[v.Id] = r
+			ranked = append(ranked, v.Id)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		vi, vj := velocities[ranked[i]], velocities[ranked[j]]
+		if vi == vj {
+			return ranked[i] < ranked[j]
+		}
+		return vi > vj
+	})
+	if max <= 0 {
+		max = 12
+	}
+	res := video.ListResultVideos{Limit: max}
+	skip := 0
+	if last != nil {
+		skip = sort.Search(len(ranked), func(i int) bool {
+			r := velocities[ranked[i]]
+			return r < lastVelocity || r == lastVelocity && ranked[i] > last.Id
+		})
+	}
+	if skip >= len(ranked) {
+		return &res, nil
+	}
+	end := skip + max
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	page := ranked[skip:end]
+	if len(fields) > 0 {
+		fields = checkFields("id", fields)
+	}
+	videos, err := c.GetVideos(ctx, page, fields)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]video.Video)
+	for _, v := range *videos {
+		byId[v.Id] = v
+	}
+	for _, id := range page {
+		if v, ok := byId[id]; ok {
+			res.List = append(res.List, v)
+		}
+	}
+	if end < len(ranked) {
+		id := page[len(page)-1]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "trending", Values: []interface{}{cursor.Value(reflect.ValueOf(velocities[id]))}, Id: id, Since: &since})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func (c *CassandraVideoService) GetChannelSubscriptions(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := validateFields(fields, c.channelFieldsIndex); err != nil {
+		return nil, err
+	}
+	var subscriptions []video.Subscription
+	next, er1 := QueryWithCursor(c.session, c.subscriptionFieldsIndex, &subscriptions, `select * from subscription where subscriberId = ?`, []interface{}{channelId}, max, "subscriptions", nextPageToken)
+	if er1 != nil {
+		return nil, er1
+	}
+	ids := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ChannelId
+	}
+	channels, er2 := c.GetChannels(ctx, ids, checkFields("id", fields))
+	if er2 != nil {
+		return nil, er2
+	}
+	byId := make(map[string]video.Channel)
+	for _, channel := range *channels {
+		byId[channel.Id] = channel
+	}
+	res := video.ListResultChannel{NextPageToken: next, Limit: max}
+	for _, subscription := range subscriptions {
+		if channel, ok := byId[subscription.ChannelId]; ok {
+			res.List = append(res.List, channel)
+		} else {
+			res.List = append(res.List, video.SubscriptionChannel(subscription, fields))
+		}
+	}
+	return &res, nil
+}
+
+func (c *CassandraVideoService) GetChannelSubscribers(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := validateFields(fields, c.channelFieldsIndex); err != nil {
+		return nil, err
+	}
+	ids, er1 := c.subscriptionIds(`select subscriberId from subscription where channelId = ?`, channelId)
+	if er1 != nil {
+		return nil, er1
+	}
+	res := video.ListResultChannel{Limit: max}
+	if len(ids) == 0 {
+		return &res, nil
+	}
+	question := make([]string, len(ids))
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		question[i] = "?"
+		values[i] = id
+	}
+	if len(fields) <= 0 {
+		fields = append(fields, "*")
+	}
+	query := fmt.Sprintf(`select %s from channel where id in (%s)`, strings.Join(fields, ","), strings.Join(question, ","))
+	next, er2 := QueryWithCursor(c.session, c.channelFieldsIndex, &res.List, query, values, max, "subscribers", nextPageToken)
+	if er2 != nil {
+		return nil, er2
+	}
+	res.NextPageToken = next
+	return &res, nil
+}
+
+func (c *CassandraVideoService) GetSubscriptionVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := validateFields(fields, c.videoFieldsIndex); err != nil {
+		return nil, err
+	}
+	ids, er1 := c.subscriptionIds(`select channelId from subscription where subscriberId = ?`, channelId)
+	if er1 != nil {
+		return nil, er1
+	}
+	res := video.ListResultVideos{Limit: max}
+	if len(ids) == 0 {
+		return &res, nil
+	}
+	must := []interface{}{map[string]interface{}{"type": "contains", "field": "channelid", "values": ids}}
+	if len(regionCode) > 0 {
+		must = append(must, available(regionCode))
+	}
+	filter := map[string]interface{}{
+		"must": must,
+	}
+	if !video.IncludeUnavailable(ctx) {
+		filter["not"] = []interface{}{tombstoned()}
+	}
+	a := map[string]interface{}{
+		"filter": filter,
+		"sort":   map[string]interface{}{"field": `publishedat`, "reverse": true},
+	}
+	queryObj, er2 := json.Marshal(a)
+	if er2 != nil {
+		return nil, er2
+	}
+	if len(fields) <= 0 {
+		fields = append(fields, "*")
+	}
+	query := fmt.Sprintf(`select %s from video where expr(video_index, '%s')`, strings.Join(fields, ","), quote(queryObj))
+	next, er3 := QueryWithCursor(c.session, c.videoFieldsIndex, &res.List, query, nil, max, "date", nextPageToken)
+	if er3 != nil {
+		return nil, er3
+	}
+	res.NextPageToken = next
+	return &res, nil
+}
+
+func (c *CassandraVideoService) subscriptionIds(query string, id string) ([]string, error) {
+	iter := c.session.Query(query, id).Iter()
+	var ids []string
+	var v string
+	for iter.Scan(&v) {
+		ids = append(ids, v)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func velocity(statistics []video.VideoStatistics, publishedAt *time.Time, since time.Time) (float64, bool) {
+	var first, last *video.VideoStatistics
+	for i := range statistics {
+		s := &statistics[i]
+		if s.Timestamp == nil || s.ViewCount == nil || s.Timestamp.Before(since) {
+			continue
+		}
+		if first == nil || s.Timestamp.Before(*first.Timestamp) {
+			first = s
+		}
+		if last == nil || s.Timestamp.After(*last.Timestamp) {
+			last = s
+		}
+	}
+	if last == nil {
+		return 0, false
+	}
+	from, views := *first.Timestamp, *first.ViewCount
+	if publishedAt != nil && !publishedAt.Before(since) && publishedAt.Before(from) {
+		from, views = *publishedAt, 0
+	}
+	hours := last.Timestamp.Sub(from).Hours()
+	if hours <= 0 {
+		return 0, false
+	}
+	return float64(*last.ViewCount-views) / hours, true
+}
+
+func available(regionCode string) map[string]interface{} {
+	regionCode = video.RegionCode(regionCode)
+	return map[string]interface{}{"type": "boolean", "should": []interface{}{
+		map[string]interface{}{"type": "contains", "field": "allowedregions", "values": regionCode},
+		map[string]interface{}{"type": "boolean",
+			"must": []interface{}{map[string]interface{}{"type": "all"}},
+			"not": []interface{}{
+				map[string]interface{}{"type": "wildcard", "field": "allowedregions", "value": "*"},
+				map[string]interface{}{"type": "contains", "field": "blockedregions", "values": regionCode},
+			},
+		},
+	}}
+}
+
+func buildChannelSearch(s video.ChannelSM, keys []video.SortKey, fields []string) (string, error) {
 	var should []interface{}
 	var must []interface{}
 	var not []interface{}
-	var sort []interface{}
 	if len(s.Q) > 0 {
 		should = append(should, map[string]interface{}{"type": "phrase", "field": "title", "value": fmt.Sprintf(`%s`, s.Q)})
 		should = append(should, map[string]interface{}{"type": "prefix", "field": "title", "value": fmt.Sprintf(`%s`, s.Q)})
@@ -439,17 +1005,17 @@ func buildChannelSearch(s video.ChannelSM, fields []string) (string, error) {
 		should = append(should, map[string]interface{}{"type": "wildcard", "field": "description", "value": fmt.Sprintf(`*%s*`, s.Q)})
 	}
 	if s.PublishedBefore != nil && s.PublishedAfter != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2, "include_lower": true, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedAfter != nil {
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "include_lower": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedBefore != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1})
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	}
 	if len(s.ChannelId) > 0 {
@@ -472,35 +1038,12 @@ func buildChannelSearch(s video.ChannelSM, fields []string) (string, error) {
 		must = append(must, map[string]interface{}{"type": "match", "field": "relevancelanguage", "value": s.RelevanceLanguage})
 		fields = checkFields("relevanceLanguage", fields)
 	}
-	if len(s.Sort) > 0 {
-		sort = append(sort, map[string]interface{}{"field": strings.ToLower(s.Sort), "reverse": true})
-		fields = checkFields(s.Sort, fields)
-	}
-	filter := map[string]interface{}{
-		"should": should,
-		"not":    not,
-	}
-	a := map[string]interface{}{
-		"filter": filter,
-		"query":  map[string]interface{}{"must": must},
-		"sort":   sort,
-	}
-	if len(should) == 0 && len(not) == 0 {
-		delete(a, "filter")
-	} else {
-		if len(should) == 0 {
-			delete(filter, "should")
+	for _, key := range keys {
+		if key.Field != video.Relevance {
+			fields = checkFields(key.Field, fields)
 		}
-		if len(not) == 0 {
-			delete(filter, "not")
-		}
-	}
-	if len(must) == 0 {
-		delete(a, "query")
-	}
-	if len(sort) == 0 {
-		delete(a, "sort")
 	}
+	a := search(should, must, not, keys)
 	queryObj, err := json.Marshal(a)
 	if err != nil {
 		return "", err
@@ -508,15 +1051,14 @@ func buildChannelSearch(s video.ChannelSM, fields []string) (string, error) {
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
-	sql := fmt.Sprintf(`select %s from channel where expr(channel_index,'%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from channel where expr(channel_index,'%s')`, strings.Join(fields, ","), quote(queryObj))
 	return sql, nil
 }
 
-func buildPlaylistSearch(s video.PlaylistSM, fields []string) (string, error) {
+func buildPlaylistSearch(s video.PlaylistSM, keys []video.SortKey, fields []string) (string, error) {
 	var should []interface{}
 	var must []interface{}
 	var not []interface{}
-	var sort []interface{}
 	if len(s.Q) > 0 {
 		should = append(should, map[string]interface{}{"type": "phrase", "field": "title", "value": fmt.Sprintf(`%s`, s.Q)})
 		should = append(should, map[string]interface{}{"type": "prefix", "field": "title", "value": fmt.Sprintf(`%s`, s.Q)})
@@ -530,17 +1072,17 @@ func buildPlaylistSearch(s video.PlaylistSM, fields []string) (string, error) {
 		should = append(should, map[string]interface{}{"type": "wildcard", "field": "description", "value": fmt.Sprintf(`*%s*`, s.Q)})
 	}
 	if s.PublishedBefore != nil && s.PublishedAfter != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2, "include_lower": true, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedAfter != nil {
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "include_lower": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedBefore != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1})
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	}
 	if len(s.ChannelId) > 0 {
@@ -559,35 +1101,12 @@ func buildPlaylistSearch(s video.PlaylistSM, fields []string) (string, error) {
 		must = append(must, map[string]interface{}{"type": "match", "field": "relevancelanguage", "value": s.RelevanceLanguage})
 		fields = checkFields("relevanceLanguage", fields)
 	}
-	if len(s.Sort) > 0 {
-		sort = append(sort, map[string]interface{}{"field": strings.ToLower(s.Sort), "reverse": true})
-	}
-	filter := map[string]interface{}{
-		"should": should,
-		"not":    not,
-	}
-	a := map[string]interface{}{
-		"filter": filter,
-		"query":  map[string]interface{}{"must": must},
-		"sort":   sort,
-	}
-	if len(should) == 0 && len(not) == 0 {
-		delete(a, "filter")
-	} else {
-		if len(should) == 0 {
-			delete(filter, "should")
+	for _, key := range keys {
+		if key.Field != video.Relevance {
+			fields = checkFields(key.Field, fields)
 		}
-		if len(not) == 0 {
-			delete(filter, "not")
-		}
-	}
-	if len(must) == 0 {
-		delete(a, "query")
 	}
-	if len(sort) == 0 {
-		delete(a, "sort")
-	}
-
+	a := search(should, must, not, keys)
 	queryObj, err := json.Marshal(a)
 	if err != nil {
 		return "", err
@@ -595,22 +1114,25 @@ func buildPlaylistSearch(s video.PlaylistSM, fields []string) (string, error) {
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
-	sql := fmt.Sprintf(`select %s from playlist where expr(playlist_index,'%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from playlist where expr(playlist_index,'%s')`, strings.Join(fields, ","), quote(queryObj))
 	return sql, nil
 }
 
-func buildVideosSearch(s video.ItemSM, fields []string) (string, error) {
+func tombstoned() map[string]interface{} {
+	return map[string]interface{}{"type": "contains", "field": "status", "values": video.VideoStatuses}
+}
+
+func buildVideosSearch(s video.ItemSM, related []string, unavailable bool, keys []video.SortKey, fields []string) (string, error) {
 	var should []interface{}
 	var must []interface{}
 	var not []interface{}
-	var sort []interface{}
 	if len(s.Duration) > 0 {
 		switch s.Duration {
 		case "short":
-			must = append(must, map[string]interface{}{"type": "range", "field": "duration", "upper": "240"})
+			must = append(must, map[string]interface{}{"type": "range", "field": "duration", "lower": "0", "upper": "240", "include_upper": true})
 			break
 		case "medium":
-			must = append(must, map[string]interface{}{"type": "range", "field": "duration", "lower": "240", "upper": "1200"})
+			must = append(must, map[string]interface{}{"type": "range", "field": "duration", "lower": "240", "upper": "1200", "include_upper": true})
 			break
 		case "long":
 			must = append(must, map[string]interface{}{"type": "range", "field": "duration", "lower": "1200"})
@@ -633,51 +1155,102 @@ func buildVideosSearch(s video.ItemSM, fields []string) (string, error) {
 		should = append(should, map[string]interface{}{"type": "wildcard", "field": "description", "value": fmt.Sprintf(`*%s*`, s.Q)})
 	}
 	if s.PublishedBefore != nil && s.PublishedAfter != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "upper": t2, "include_lower": true, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedAfter != nil {
-		t2 := s.PublishedAfter.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2})
+		t1 := s.PublishedAfter.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1, "include_lower": true})
 		fields = checkFields("publishedAt", fields)
 	} else if s.PublishedBefore != nil {
-		t1 := s.PublishedBefore.Format("2006-01-02 15:04:05")
-		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "lower": t1})
+		t2 := s.PublishedBefore.Format("2006-01-02 15:04:05")
+		must = append(must, map[string]interface{}{"type": "range", "field": "publishedat", "upper": t2, "include_upper": true})
 		fields = checkFields("publishedAt", fields)
 	}
 	if len(s.RegionCode) > 0 {
-		not = append(not, map[string]interface{}{"type": "match", "field": "blockedregions", "value": s.RegionCode})
+		must = append(must, available(s.RegionCode))
 	}
-	if len(s.Sort) > 0 {
-		sort = append(sort, map[string]interface{}{"field": strings.ToLower(s.Sort), "reverse": true})
-		fields = checkFields(s.Sort, fields)
+	if len(s.CategoryId) > 0 {
+		must = append(must, map[string]interface{}{"type": "match", "field": "categoryid", "value": s.CategoryId})
+		fields = checkFields("categoryId", fields)
 	}
-	filter := map[string]interface{}{
-		"should": should,
-		"not":    not,
+	if category, ok := video.VideoTypeCategories[s.VideoType]; ok {
+		must = append(must, map[string]interface{}{"type": "match", "field": "categoryid", "value": category})
+		fields = checkFields("categoryId", fields)
 	}
-	a := map[string]interface{}{
-		"filter": filter,
-		"query":  map[string]interface{}{"must": must},
-		"sort":   sort,
+	switch s.Caption {
+	case "closedCaption":
+		must = append(must, map[string]interface{}{"type": "match", "field": "caption", "value": "true"})
+		fields = checkFields("caption", fields)
+	case "none":
+		not = append(not, map[string]interface{}{"type": "match", "field": "caption", "value": "true"})
+		fields = checkFields("caption", fields)
+	}
+	switch s.Definition {
+	case "high":
+		must = append(must, map[string]interface{}{"type": "match", "field": "definition", "value": 5})
+		fields = checkFields("definition", fields)
+	case "standard":
+		not = append(not, map[string]interface{}{"type": "match", "field": "definition", "value": 5})
+		fields = checkFields("definition", fields)
+	}
+	if s.Dimension == "2d" || s.Dimension == "3d" {
+		must = append(must, map[string]interface{}{"type": "match", "field": "dimension", "value": s.Dimension})
+		fields = checkFields("dimension", fields)
+	}
+	switch s.EventType {
+	case "live", "upcoming":
+		must = append(must, map[string]interface{}{"type": "match", "field": "livebroadcastcontent", "value": s.EventType})
+		fields = checkFields("liveBroadcastContent", fields)
+	case "completed":
+		not = append(not, map[string]interface{}{"type": "contains", "field": "livebroadcastcontent", "values": []string{"live", "upcoming"}})
+		fields = checkFields("liveBroadcastContent", fields)
+	}
+	if len(s.License) > 0 && s.License != "any" {
+		must = append(must, map[string]interface{}{"type": "match", "field": "license", "value": s.License})
+		fields = checkFields("license", fields)
+	}
+	if s.Embeddable == "true" {
+		must = append(must, map[string]interface{}{"type": "match", "field": "embeddable", "value": true})
+		fields = checkFields("embeddable", fields)
+	}
+	if s.SafeSearch == "moderate" || s.SafeSearch == "strict" {
+		not = append(not, map[string]interface{}{"type": "match", "field": "ytrating", "value": video.AgeRestricted})
+		fields = checkFields("ytRating", fields)
 	}
-	if len(should) == 0 && len(not) == 0 {
-		delete(a, "filter")
-	} else {
-		if len(should) == 0 {
-			delete(filter, "should")
+	if len(s.TopicId) > 0 {
+		must = append(must, map[string]interface{}{"type": "contains", "field": "topicids", "values": []string{s.TopicId}})
+		fields = checkFields("topicIds", fields)
+	}
+	if len(s.RelevanceLanguage) > 0 {
+		language := strings.ToLower(s.RelevanceLanguage)
+		var languages []interface{}
+		var declared []interface{}
+		for _, field := range []string{"defaultlanguage", "defaultaudiolanguage"} {
+			languages = append(languages, map[string]interface{}{"type": "match", "field": field, "value": language})
+			languages = append(languages, map[string]interface{}{"type": "prefix", "field": field, "value": language + "-"})
+			declared = append(declared, map[string]interface{}{"type": "wildcard", "field": field, "value": "?*"})
 		}
-		if len(not) == 0 {
-			delete(filter, "not")
+		none := map[string]interface{}{"type": "boolean", "must": []interface{}{map[string]interface{}{"type": "all"}}, "not": declared}
+		must = append(must, map[string]interface{}{"type": "boolean", "should": append(languages, none)})
+		fields = checkFields("defaultLanguage", fields)
+		fields = checkFields("defaultAudioLanguage", fields)
+	}
+	if len(s.RelatedToVideoId) > 0 {
+		must = append(must, map[string]interface{}{"type": "contains", "field": "tags", "values": related})
+		not = append(not, map[string]interface{}{"type": "match", "field": "id", "value": s.RelatedToVideoId})
+		fields = checkFields("tags", fields)
+	}
+	if !unavailable {
+		not = append(not, tombstoned())
+	}
+	for _, key := range keys {
+		if key.Field != video.Relevance {
+			fields = checkFields(key.Field, fields)
 		}
 	}
-	if len(must) == 0 {
-		delete(a, "query")
-	}
-	if len(sort) == 0 {
-		delete(a, "sort")
-	}
+	a := search(should, must, not, keys)
 	queryObj, err := json.Marshal(a)
 	if err != nil {
 		return "", err
@@ -685,10 +1258,70 @@ func buildVideosSearch(s video.ItemSM, fields []string) (string, error) {
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
-	sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(fields, ","), queryObj)
+	sql := fmt.Sprintf(`select %s from video where expr(video_index,'%s')`, strings.Join(fields, ","), quote(queryObj))
 	return sql, nil
 }
 
+// search puts the text conditions in should only when sorted by relevance.
+func search(should []interface{}, must []interface{}, not []interface{}, keys []video.SortKey) map[string]interface{} {
+	a := make(map[string]interface{})
+	filter := make(map[string]interface{})
+	query := make(map[string]interface{})
+	if len(keys) > 0 && keys[0].Field == video.Relevance {
+		if len(should) > 0 {
+			query["should"] = should
+		}
+		if len(must) > 0 {
+			filter["must"] = must
+		}
+	} else {
+		if len(should) > 0 {
+			filter["should"] = should
+		}
+		if len(must) > 0 {
+			query["must"] = must
+		}
+		if len(keys) > 0 {
+			a["sort"] = luceneSort(keys)
+		}
+	}
+	if len(not) > 0 {
+		filter["not"] = not
+	}
+	if len(filter) > 0 {
+		a["filter"] = filter
+	}
+	if len(query) > 0 {
+		a["query"] = query
+	}
+	return a
+}
+
+func luceneSort(keys []video.SortKey) []interface{} {
+	sort := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		field := strings.ToLower(key.Field)
+		if key.Field == "title" {
+			field = "sorttitle"
+		}
+		sort = append(sort, map[string]interface{}{"field": field, "reverse": key.Desc})
+	}
+	return sort
+}
+
+func quote(queryObj []byte) string {
+	return strings.Replace(string(queryObj), "'", "''", -1)
+}
+
+func validateFields(fields []string, fieldsIndex map[string]int) error {
+	for _, field := range fields {
+		if _, ok := fieldsIndex[strings.ToLower(field)]; !ok {
+			return fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
+	}
+	return nil
+}
+
 func checkFields(check string, fields []string) []string {
 	if len(fields) == 0 {
 		return fields
@@ -0,0 +1,104 @@
+package cassandra
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
+	initcassandra "github.com/core-go/video/init-cassandra"
+	synccassandra "github.com/core-go/video/sync-cassandra"
+	"github.com/core-go/video/videotest"
+)
+
+func init() {
+	cursor.SetKey([]byte("test key"))
+}
+
+// testKeyspace is dropped and created again for each test.
+const testKeyspace = "videotest"
+
+// openTestSession connects to the comma separated hosts VIDEO_TEST_CASSANDRA names, with testKeyspace empty and
+// migrated, or skips the test. The hosts must have the Lucene index plugin.
+func openTestSession(t *testing.T) *gocql.Session {
+	hosts := os.Getenv("VIDEO_TEST_CASSANDRA")
+	if len(hosts) == 0 {
+		t.Skip("VIDEO_TEST_CASSANDRA is not set")
+	}
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	cluster.Consistency = gocql.One
+	session, er0 := cluster.CreateSession()
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	er1 := session.Query("drop keyspace if exists " + testKeyspace).Exec()
+	session.Close()
+	if er1 != nil {
+		t.Fatal(er1)
+	}
+	session, er2 := initcassandra.Initialize(cluster, testKeyspace)
+	if er2 != nil {
+		t.Fatal(er2)
+	}
+	t.Cleanup(session.Close)
+	for _, stmt := range []string{initcassandra.CreateVideoLuceneIndex, initcassandra.CreateVideoStatisticsLuceneIndex, initcassandra.CreateChannelLuceneIndex, initcassandra.CreatePlaylistLuceneIndex} {
+		if er3 := session.Query(stmt).Exec(); er3 != nil {
+			t.Fatal(er3)
+		}
+	}
+	return session
+}
+
+func newTestBackend(t *testing.T) (*gocql.Session, *CassandraVideoService, *synccassandra.CassandraVideoRepository) {
+	session := openTestSession(t)
+	service, er0 := NewCassandraVideoService(session, category.CategorySyncClient{})
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	repository, er1 := synccassandra.NewCassandraVideoRepository(session)
+	if er1 != nil {
+		t.Fatal(er1)
+	}
+	return session, service, repository
+}
+
+func TestVideoService(t *testing.T) {
+	videotest.RunVideoServiceSuite(t, func(t *testing.T) (video.VideoService, video.SyncRepository) {
+		_, service, repository := newTestBackend(t)
+		return service, repository
+	})
+}
+
+func TestSyncRepository(t *testing.T) {
+	videotest.RunSyncRepositorySuite(t, func(t *testing.T) video.SyncRepository {
+		_, _, repository := newTestBackend(t)
+		return repository
+	})
+}
+
+func TestBackend(t *testing.T) {
+	videotest.RunBackendSuite(t, func(t *testing.T) videotest.Backend {
+		session, service, repository := newTestBackend(t)
+		playlistItems, er0 := synccassandra.NewCassandraPlaylistItemRepository(session)
+		if er0 != nil {
+			t.Fatal(er0)
+		}
+		checkpoints, er1 := synccassandra.NewCassandraCheckpointRepository(session)
+		if er1 != nil {
+			t.Fatal(er1)
+		}
+		return videotest.Backend{
+			Service:       service,
+			Repository:    repository,
+			Statistics:    synccassandra.NewCassandraStatisticsRepository(session),
+			Subscriptions: synccassandra.NewCassandraSubscriptionRepository(session),
+			PlaylistItems: playlistItems,
+			Tombstones:    synccassandra.NewCassandraTombstoneRepository(session),
+			Checkpoints:   checkpoints,
+		}
+	})
+}
@@ -1,11 +1,11 @@
 package cassandra
 
 import (
-	"encoding/hex"
+	"github.com/core-go/video/cursor"
 	"github.com/gocql/gocql"
 )
 
-func Exec(ses *gocql.Session, query string, values...interface{}) (int64, error) {
+func Exec(ses *gocql.Session, query string, values ...interface{}) (int64, error) {
 	q := ses.Query(query, values...)
 	err := q.Exec()
 	if err != nil {
@@ -20,23 +20,31 @@ func Query(ses *gocql.Session, fieldsIndex map[string]int, results interface{},
 	}
 	return ScanIter(q.Iter(), results, fieldsIndex)
 }
-func QueryWithPage(ses *gocql.Session, fieldsIndex map[string]int, results interface{}, sql string, values []interface{}, max int, options ...string) (string, error) {
-	nextPageToken := ""
-	if len(options) > 0 && len(options[0]) > 0 {
-		nextPageToken = options[0]
-	}
-	next, er0 := hex.DecodeString(nextPageToken)
+
+// QueryWithCursor wraps the paging state Cassandra returns in a signed page token.
+func QueryWithCursor(ses *gocql.Session, fieldsIndex map[string]int, results interface{}, sql string, values []interface{}, max int, sort string, nextPageToken string) (string, error) {
+	c, er0 := cursor.Decode(nextPageToken, sort)
 	if er0 != nil {
 		return "", er0
 	}
-	query := ses.Query(sql, values...).PageState(next).PageSize(max)
-	if query.Exec() != nil {
-		return "", query.Exec()
+	var state []byte
+	if c != nil {
+		if len(c.State) == 0 {
+			return "", cursor.ErrInvalid
+		}
+		state = c.State
 	}
-	err := ScanIter(query.Iter(), results, fieldsIndex)
-	if err != nil {
-		return "", err
+	iter := ses.Query(sql, values...).PageState(state).PageSize(max).Iter()
+	next := iter.PageState()
+	if er1 := ScanIter(iter, results, fieldsIndex); er1 != nil {
+		iter.Close()
+		return "", er1
+	}
+	if er2 := iter.Close(); er2 != nil {
+		return "", er2
+	}
+	if len(next) == 0 {
+		return "", nil
 	}
-	nextPageToken = hex.EncodeToString(query.Iter().PageState())
-	return nextPageToken, nil
+	return cursor.Encode(cursor.Cursor{Sort: sort, State: next})
 }
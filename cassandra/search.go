@@ -0,0 +1,135 @@
+package cassandra
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+// searchSource buffers a page of one of the tables Search merges.
+type searchSource struct {
+	kind        string
+	sql         string
+	fieldsIndex map[string]int
+	modelType   reflect.Type
+	position    cursor.Source
+	buffer      []searchRow
+	read        bool
+	next        []byte
+}
+
+// searchRow keeps its offset, which ranks it by relevance since the score is not read back.
+type searchRow struct {
+	result video.SearchResult
+	title  string
+	date   *time.Time
+	id     string
+	offset int
+}
+
+func (s *searchSource) fill(session *gocql.Session, limit int) error {
+	for len(s.buffer) == 0 && !s.position.Done {
+		if s.read {
+			if len(s.next) == 0 {
+				s.position.Done = true
+				return nil
+			}
+			s.position = cursor.Source{State: s.next, Offset: s.position.Offset}
+		}
+		iter := session.Query(s.sql).PageState(s.position.State).PageSize(limit).Iter()
+		s.next = iter.PageState()
+		list := reflect.New(reflect.SliceOf(s.modelType))
+		if err := ScanIter(iter, list.Interface(), s.fieldsIndex); err != nil {
+			iter.Close()
+			return err
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+		s.read = true
+		for i := s.position.Skip; i < list.Elem().Len(); i++ {
+			item := list.Elem().Index(i)
+			s.buffer = append(s.buffer, searchRow{
+				result: searchResult(s.kind, item.Addr().Interface()),
+				title:  video.SortTitle(item.FieldByName("Title").String()),
+				date:   item.FieldByName("PublishedAt").Interface().(*time.Time),
+				id:     item.FieldByName("Id").String(),
+			})
+		}
+	}
+	return nil
+}
+
+func (s *searchSource) pop() searchRow {
+	row := s.buffer[0]
+	row.offset = s.position.Offset
+	s.buffer = s.buffer[1:]
+	s.position.Skip++
+	s.position.Offset++
+	return row
+}
+
+// before alternates the tables when sorted by relevance.
+func before(keys []video.SortKey, a searchRow, b searchRow) bool {
+	for _, key := range keys {
+		var c int
+		switch key.Field {
+		case video.Relevance:
+			c = b.offset - a.offset
+		case "title":
+			c = strings.Compare(a.title, b.title)
+		default:
+			if a.date == nil || b.date == nil {
+				if (a.date == nil) != (b.date == nil) {
+					return b.date == nil
+				}
+				continue
+			}
+			if a.date.Before(*b.date) {
+				c = -1
+			} else if a.date.After(*b.date) {
+				c = 1
+			}
+		}
+		if c != 0 {
+			return (c < 0) != key.Desc
+		}
+	}
+	return a.id < b.id
+}
+
+func searchFields(fields []string, fieldsIndex map[string]int, keys []video.SortKey) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	res := []string{"id"}
+	for _, field := range fields {
+		if _, ok := fieldsIndex[strings.ToLower(field)]; ok {
+			res = checkFields(field, res)
+		}
+	}
+	for _, key := range keys {
+		if key.Field != video.Relevance {
+			res = checkFields(key.Field, res)
+		}
+	}
+	return res
+}
+
+func searchResult(kind string, item interface{}) video.SearchResult {
+	result := video.SearchResult{Kind: kind}
+	switch item := item.(type) {
+	case *video.Channel:
+		result.Channel = item
+	case *video.Playlist:
+		result.Playlist = item
+	case *video.Video:
+		result.Video = item
+	}
+	return result
+}
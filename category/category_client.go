@@ -1,49 +1,49 @@
 package category
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"context"
+	"net/url"
 
 	"github.com/core-go/video"
+	"github.com/core-go/video/youtube"
 )
 
 type CategorySyncClient struct {
-	Key string
+	youtube.Caller
 }
 
-func NewCategorySyncService(key string) *CategorySyncClient {
-	return &CategorySyncClient{Key: key}
+func NewCategorySyncService(key string, options ...*youtube.QuotaLedger) *CategorySyncClient {
+	var quota *youtube.QuotaLedger
+	if len(options) > 0 {
+		quota = options[0]
+	}
+	return &CategorySyncClient{youtube.Caller{Key: key, Quota: quota}}
 }
 
-func (c *CategorySyncClient) GetCagetories(regionCode string) (*[]video.DataCategory, error) {
+func NewCategorySyncServiceWithPool(keys *youtube.KeyPool, options ...*youtube.QuotaLedger) *CategorySyncClient {
+	var quota *youtube.QuotaLedger
+	if len(options) > 0 {
+		quota = options[0]
+	}
+	return &CategorySyncClient{youtube.Caller{Keys: keys, Quota: quota}}
+}
+
+func (c *CategorySyncClient) GetCagetories(ctx context.Context, regionCode string) (*[]video.DataCategory, error) {
 	if len(regionCode) <= 0 {
 		regionCode = "US"
 	}
-	url := fmt.Sprintf(`https://www.googleapis.com/youtube/v3/videoCategories?key=%s&regionCode=%s`, c.Key, regionCode)
-	res, err := convertCategory(url)
+	query := url.Values{}
+	query.Set("regionCode", regionCode)
+	query.Set("part", "snippet")
+	var summary CategoryTubeResponse
+	err := c.Get(ctx, "videoCategories", query, &summary)
 	if err != nil {
 		return nil, err
 	}
-	return res, err
+	return convertCategory(summary), nil
 }
 
-func convertCategory(url string) (*[]video.DataCategory, error) {
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
-	}
-	var summary CategoryTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
-	}
+func convertCategory(summary CategoryTubeResponse) *[]video.DataCategory {
 	var categories []video.DataCategory
 	for _, v := range summary.Items {
 		var category video.DataCategory
@@ -53,5 +53,5 @@ func convertCategory(url string) (*[]video.DataCategory, error) {
 		category.Assignable = v.Snippet.Assignable
 		categories = append(categories, category)
 	}
-	return &categories, nil
+	return &categories
 }
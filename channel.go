@@ -31,6 +31,10 @@ type Channel struct {
 	LastUpload             *time.Time `mapstructure:"lastUpload" json:"lastUpload,omitempty" gorm:"column:lastUpload" bson:"lastUpload,omitempty" dynamodbav:"lastUpload,omitempty" firestore:"lastUpload,omitempty" cql:"lastupload,omitempty"`
 	Title                  string     `mapstructure:"title" json:"title,omitempty" gorm:"column:title" bson:"title,omitempty" dynamodbav:"title,omitempty" firestore:"title,omitempty" cql:"title,omitempty"`
 	Uploads                string     `mapstructure:"uploads" json:"uploads,omitempty" gorm:"column:uploads" bson:"uploads,omitempty" dynamodbav:"uploads,omitempty" firestore:"uploads,omitempty" cql:"uploads,omitempty"`
+	ViewCount              *int64     `mapstructure:"viewCount" json:"viewCount,omitempty" gorm:"column:viewCount" bson:"viewCount,omitempty" dynamodbav:"viewCount,omitempty" firestore:"viewCount,omitempty" cql:"viewcount,omitempty"`
+	SubscriberCount        *int64     `mapstructure:"subscriberCount" json:"subscriberCount,omitempty" gorm:"column:subscriberCount" bson:"subscriberCount,omitempty" dynamodbav:"subscriberCount,omitempty" firestore:"subscriberCount,omitempty" cql:"subscribercount,omitempty"`
+	VideoCount             *int64     `mapstructure:"videoCount" json:"videoCount,omitempty" gorm:"column:videoCount" bson:"videoCount,omitempty" dynamodbav:"videoCount,omitempty" firestore:"videoCount,omitempty" cql:"videocount,omitempty"`
 	ChannelList            []string   `mapstructure:"channel_list" json:"-" gorm:"column:channels" bson:"channels,omitempty" dynamodbav:"channels,omitempty" firestore:"channels,omitempty" cql:"channels,omitempty"`
 	Channels               []Channel  `mapstructure:"channels" json:"channels,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-" cql:"-"`
+	Highlight              string     `mapstructure:"highlight" json:"highlight,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-" cql:"-"`
 }
@@ -0,0 +1,103 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid nextPageToken")
+
+var ErrNoKey = errors.New("cursor: no key, SetKey must be called before lists are paged")
+
+// Cursor is the position after the last item of a page.
+type Cursor struct {
+	Sort    string            `json:"s,omitempty"`
+	Values  []interface{}     `json:"v,omitempty"`
+	Id      string            `json:"i,omitempty"`
+	Since   *time.Time        `json:"t,omitempty"`
+	State   []byte            `json:"p,omitempty"`
+	Sources map[string]Source `json:"m,omitempty"`
+}
+
+// Source is the position in one of the lists a merged list is read from.
+type Source struct {
+	State  []byte `json:"p,omitempty"`
+	Skip   int    `json:"k,omitempty"`
+	Offset int    `json:"o,omitempty"`
+	Done   bool   `json:"d,omitempty"`
+}
+
+var (
+	mutex sync.RWMutex
+	key   []byte
+)
+
+// SetKey must be called before any list is paged, with the same key on every instance.
+func SetKey(k []byte) {
+	if len(k) == 0 {
+		panic("cursor: empty key")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	key = append([]byte(nil), k...)
+}
+
+func sign(data []byte) ([]byte, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if len(key) == 0 {
+		return nil, ErrNoKey
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func Encode(c Cursor) (string, error) {
+	data, er0 := json.Marshal(c)
+	if er0 != nil {
+		return "", er0
+	}
+	signature, er1 := sign(data)
+	if er1 != nil {
+		return "", er1
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Decode returns nil for an empty token.
+func Decode(token string, sort string) (*Cursor, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return nil, ErrInvalid
+	}
+	data, er0 := base64.RawURLEncoding.DecodeString(token[:i])
+	signature, er1 := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if er0 != nil || er1 != nil {
+		return nil, ErrInvalid
+	}
+	expected, er2 := sign(data)
+	if er2 != nil {
+		return nil, er2
+	}
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalid
+	}
+	var c Cursor
+	if er3 := json.Unmarshal(data, &c); er3 != nil {
+		return nil, ErrInvalid
+	}
+	if c.Sort != sort {
+		return nil, ErrInvalid
+	}
+	return &c, nil
+}
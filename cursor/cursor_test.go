@@ -0,0 +1,42 @@
+package cursor
+
+import (
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	key = nil
+	if _, err := Encode(Cursor{Sort: "date", Id: "vid1"}); err != ErrNoKey {
+		t.Fatalf("err = %v; want %v", err, ErrNoKey)
+	}
+	if _, err := Decode("e30.e30", "date"); err != ErrNoKey {
+		t.Fatalf("err = %v; want %v", err, ErrNoKey)
+	}
+	SetKey([]byte("test key"))
+	token, er0 := Encode(Cursor{Sort: "date", Id: "vid1"})
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	tests := []struct {
+		name  string
+		token string
+		sort  string
+		err   error
+	}{
+		{name: "valid", token: token, sort: "date"},
+		{name: "other sort", token: token, sort: "title", err: ErrInvalid},
+		{name: "tampered", token: token[:len(token)-2] + "xx", sort: "date", err: ErrInvalid},
+		{name: "malformed", token: "abc", sort: "date", err: ErrInvalid},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := Decode(tc.token, tc.sort)
+			if err != tc.err {
+				t.Fatalf("err = %v; want %v", err, tc.err)
+			}
+			if err == nil && c.Id != "vid1" {
+				t.Errorf("id = %s; want vid1", c.Id)
+			}
+		})
+	}
+}
@@ -1,26 +1,31 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
 )
 
 type VideoHandler struct {
-	Video video.VideoService
-	channelType reflect.Type
-	playlistType reflect.Type
-	videoType reflect.Type
-	channelFields []string
+	Video          video.VideoService
+	channelType    reflect.Type
+	playlistType   reflect.Type
+	videoType      reflect.Type
+	channelFields  []string
 	playlistFields []string
-	videoFields []string
+	videoFields    []string
+	searchFields   []string
 }
 
-func NewVideoHandler(clientService video.VideoService) (*VideoHandler,error) {
+func NewVideoHandler(clientService video.VideoService) (*VideoHandler, error) {
 	var channel video.Channel
 	channelType := reflect.TypeOf(channel)
 	if channelType.Kind() != reflect.Struct {
@@ -42,15 +47,17 @@ func NewVideoHandler(clientService video.VideoService) (*VideoHandler,error) {
 	channelFields := getFields(channelType)
 	playlistFields := getFields(playlistType)
 	videoFields := getFields(videoType)
+	searchFields := append(append(append([]string{}, channelFields...), playlistFields...), videoFields...)
 
 	return &VideoHandler{
-		Video: clientService,
-		channelType: channelType,
-		playlistType: playlistType,
-		videoType: videoType,
-		channelFields: channelFields,
+		Video:          clientService,
+		channelType:    channelType,
+		playlistType:   playlistType,
+		videoType:      videoType,
+		channelFields:  channelFields,
 		playlistFields: playlistFields,
-		videoFields: videoFields,
+		videoFields:    videoFields,
+		searchFields:   searchFields,
 	}, nil
 }
 
@@ -61,7 +68,7 @@ func (c *VideoHandler) GetChannel(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.channelFields)
 		res, err := c.Video.GetChannel(r.Context(), s, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -75,7 +82,7 @@ func (c *VideoHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.channelFields)
 		res, err := c.Video.GetChannels(r.Context(), arrayId, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -89,7 +96,7 @@ func (c *VideoHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.playlistFields)
 		res, err := c.Video.GetPlaylist(r.Context(), s, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -103,7 +110,8 @@ func (c *VideoHandler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.playlistFields)
 		res, err := c.Video.GetPlaylists(r.Context(), arrayId, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
+			return
 		}
 		respond(w, res)
 	}
@@ -116,7 +124,7 @@ func (c *VideoHandler) GetVideo(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.videoFields)
 		res, err := c.Video.GetVideo(r.Context(), s, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -130,7 +138,7 @@ func (c *VideoHandler) GetVideos(w http.ResponseWriter, r *http.Request) {
 		fields := QueryArray(ps, "fields", c.videoFields)
 		res, err := c.Video.GetVideos(r.Context(), arrayId, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -146,7 +154,7 @@ func (c *VideoHandler) GetChannelPlaylists(w http.ResponseWriter, r *http.Reques
 		fields := QueryArray(query, "fields", c.playlistFields)
 		res, err := c.Video.GetChannelPlaylists(r.Context(), channelId, *limit, nextPageToken, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -158,21 +166,22 @@ func (c *VideoHandler) GetVideosFromChannelIdOrPlaylistId(w http.ResponseWriter,
 	limit := QueryInt(query, "limit", 10)
 	nextPageToken := QueryString(query, "nextPageToken")
 	fields := QueryArray(query, "fields", c.videoFields)
+	regionCode := video.RegionCode(query.Get("regionCode"))
 
 	playlistId := query.Get("playlistId")
 	if len(playlistId) > 0 {
-		res, er1 := c.Video.GetPlaylistVideos(r.Context(), playlistId, *limit, nextPageToken, fields)
+		res, er1 := c.Video.GetPlaylistVideos(listContext(r), playlistId, regionCode, *limit, nextPageToken, fields)
 		if er1 != nil {
-			http.Error(w, er1.Error(), http.StatusInternalServerError)
+			http.Error(w, er1.Error(), getStatus(er1))
 			return
 		}
 		respond(w, res)
 	} else {
 		channelId := QueryRequiredString(w, query, "channelId")
 		if len(channelId) > 0 {
-			res, er1 := c.Video.GetChannelVideos(r.Context(), channelId, *limit, nextPageToken, fields)
+			res, er1 := c.Video.GetChannelVideos(listContext(r), channelId, regionCode, *limit, nextPageToken, fields)
 			if er1 != nil {
-				http.Error(w, er1.Error(), http.StatusInternalServerError)
+				http.Error(w, er1.Error(), getStatus(er1))
 				return
 			}
 			respond(w, res)
@@ -206,7 +215,7 @@ func (c *VideoHandler) SearchChannel(w http.ResponseWriter, r *http.Request) {
 
 	res, er1 := c.Video.SearchChannel(r.Context(), channelSM, *limit, nextPageToken, fields)
 	if er1 != nil {
-		http.Error(w, er1.Error(), http.StatusInternalServerError)
+		http.Error(w, er1.Error(), getStatus(er1))
 		return
 	}
 	respond(w, res)
@@ -219,15 +228,15 @@ func (c *VideoHandler) SearchPlaylists(w http.ResponseWriter, r *http.Request) {
 	fields := QueryArray(query, "fields", c.playlistFields)
 
 	var playlistSM video.PlaylistSM
-	playlistSM.Q = strings.TrimSpace(QueryString(query,"q"))
-	playlistSM.ChannelId = strings.TrimSpace(QueryString(query,"channelId"))
-	playlistSM.Sort = strings.TrimSpace(QueryString(query,"sort"))
+	playlistSM.Q = strings.TrimSpace(QueryString(query, "q"))
+	playlistSM.ChannelId = strings.TrimSpace(QueryString(query, "channelId"))
+	playlistSM.Sort = strings.TrimSpace(QueryString(query, "sort"))
 	playlistSM.PublishedAfter = QueryTime(query, "publishedAfter")
 	playlistSM.PublishedBefore = QueryTime(query, "publishedBefore")
 
 	res, er1 := c.Video.SearchPlaylists(r.Context(), playlistSM, *limit, nextPageToken, fields)
 	if er1 != nil {
-		http.Error(w, er1.Error(), http.StatusInternalServerError)
+		http.Error(w, er1.Error(), getStatus(er1))
 		return
 	}
 	respond(w, res)
@@ -239,18 +248,10 @@ func (c *VideoHandler) SearchVideos(w http.ResponseWriter, r *http.Request) {
 	nextPageToken := QueryString(query, "nextPageToken")
 	fields := QueryArray(query, "fields", c.videoFields)
 
-	var itemSM video.ItemSM
-	itemSM.Q = strings.TrimSpace(QueryString(query,"q"))
-	itemSM.ChannelId = strings.TrimSpace(QueryString(query,"channelId"))
-	itemSM.Sort = strings.TrimSpace(QueryString(query,"sort"))
-	itemSM.RegionCode = strings.TrimSpace(QueryString(query,"regionCode"))
-	itemSM.Duration = strings.TrimSpace(QueryString(query, "duration"))
-	itemSM.PublishedAfter = QueryTime(query, "publishedAfter")
-	itemSM.PublishedBefore = QueryTime(query, "publishedBefore")
-
-	res, er1 := c.Video.SearchVideos(r.Context(), itemSM, *limit, nextPageToken, fields)
+	itemSM := getItemSM(query)
+	res, er1 := c.Video.SearchVideos(listContext(r), itemSM, *limit, nextPageToken, fields)
 	if er1 != nil {
-		http.Error(w, er1.Error(), http.StatusInternalServerError)
+		http.Error(w, er1.Error(), getStatus(er1))
 		return
 	}
 	respond(w, res)
@@ -260,20 +261,12 @@ func (c *VideoHandler) Search(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	limit := QueryInt(query, "limit", 10)
 	nextPageToken := QueryString(query, "nextPageToken")
-	fields := QueryArray(query, "fields", c.videoFields)
-
-	var itemSM video.ItemSM
-	itemSM.Q = strings.TrimSpace(QueryString(query,"q"))
-	itemSM.ChannelId = strings.TrimSpace(QueryString(query,"channelId"))
-	itemSM.Sort = strings.TrimSpace(QueryString(query,"sort"))
-	itemSM.RegionCode = strings.TrimSpace(QueryString(query,"regionCode"))
-	itemSM.Duration = strings.TrimSpace(QueryString(query, "duration"))
-	itemSM.PublishedAfter = QueryTime(query, "publishedAfter")
-	itemSM.PublishedBefore = QueryTime(query, "publishedBefore")
+	fields := QueryArray(query, "fields", c.searchFields)
 
-	res, er1 := c.Video.Search(r.Context(), itemSM, *limit, nextPageToken, fields)
+	itemSM := getItemSM(query)
+	res, er1 := c.Video.Search(listContext(r), itemSM, *limit, nextPageToken, fields)
 	if er1 != nil {
-		http.Error(w, er1.Error(), http.StatusInternalServerError)
+		http.Error(w, er1.Error(), getStatus(er1))
 		return
 	}
 	respond(w, res)
@@ -286,9 +279,9 @@ func (c *VideoHandler) GetRelatedVideos(w http.ResponseWriter, r *http.Request)
 		limit := QueryInt(query, "limit", 10)
 		nextPageToken := QueryString(query, "nextPageToken")
 		fields := QueryArray(query, "fields", c.videoFields)
-		res, err := c.Video.GetRelatedVideos(r.Context(), id, *limit, nextPageToken, fields)
+		res, err := c.Video.GetRelatedVideos(listContext(r), id, *limit, nextPageToken, fields)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), getStatus(err))
 			return
 		}
 		respond(w, res)
@@ -298,19 +291,124 @@ func (c *VideoHandler) GetRelatedVideos(w http.ResponseWriter, r *http.Request)
 func (c *VideoHandler) GetPopularVideos(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	categoryId := query.Get("categoryId")
-	regionCode := query.Get("regionCode")
+	regionCode := video.RegionCode(query.Get("regionCode"))
 
 	limit := QueryInt(query, "limit", 10)
 	nextPageToken := QueryString(query, "nextPageToken")
 	fields := QueryArray(query, "fields", c.videoFields)
-	res, err := c.Video.GetPopularVideos(r.Context(), regionCode, categoryId, *limit, nextPageToken, fields)
+	res, err := c.Video.GetPopularVideos(listContext(r), regionCode, categoryId, *limit, nextPageToken, fields)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), getStatus(err))
 		return
 	}
 	respond(w, res)
 }
 
+func (c *VideoHandler) GetTrendingVideos(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	categoryId := query.Get("categoryId")
+	regionCode := video.RegionCode(query.Get("regionCode"))
+	window := 24 * time.Hour
+	if s := query.Get("window"); len(s) > 0 {
+		d, er0 := time.ParseDuration(s)
+		if er0 != nil || d <= 0 {
+			http.Error(w, "window must be a positive duration such as 24h", http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+	limit := QueryInt(query, "limit", 10)
+	nextPageToken := QueryString(query, "nextPageToken")
+	fields := QueryArray(query, "fields", c.videoFields)
+	res, err := c.Video.GetTrendingVideos(listContext(r), regionCode, categoryId, window, *limit, nextPageToken, fields)
+	if err != nil {
+		http.Error(w, err.Error(), getStatus(err))
+		return
+	}
+	respond(w, res)
+}
+
+func (c *VideoHandler) GetChannelSubscriptions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := GetRequiredParam(w, r, 1)
+	if len(id) > 0 {
+		limit := QueryInt(query, "limit", 10)
+		nextPageToken := QueryString(query, "nextPageToken")
+		fields := QueryArray(query, "fields", c.channelFields)
+		res, err := c.Video.GetChannelSubscriptions(r.Context(), id, *limit, nextPageToken, fields)
+		if err != nil {
+			http.Error(w, err.Error(), getStatus(err))
+			return
+		}
+		respond(w, res)
+	}
+}
+
+func (c *VideoHandler) GetChannelSubscribers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := GetRequiredParam(w, r, 1)
+	if len(id) > 0 {
+		limit := QueryInt(query, "limit", 10)
+		nextPageToken := QueryString(query, "nextPageToken")
+		fields := QueryArray(query, "fields", c.channelFields)
+		res, err := c.Video.GetChannelSubscribers(r.Context(), id, *limit, nextPageToken, fields)
+		if err != nil {
+			http.Error(w, err.Error(), getStatus(err))
+			return
+		}
+		respond(w, res)
+	}
+}
+
+func (c *VideoHandler) GetSubscriptionVideos(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := GetRequiredParam(w, r, 1)
+	if len(id) > 0 {
+		regionCode := video.RegionCode(query.Get("regionCode"))
+		limit := QueryInt(query, "limit", 10)
+		nextPageToken := QueryString(query, "nextPageToken")
+		fields := QueryArray(query, "fields", c.videoFields)
+		res, err := c.Video.GetSubscriptionVideos(listContext(r), id, regionCode, *limit, nextPageToken, fields)
+		if err != nil {
+			http.Error(w, err.Error(), getStatus(err))
+			return
+		}
+		respond(w, res)
+	}
+}
+
+func getItemSM(query url.Values) video.ItemSM {
+	var itemSM video.ItemSM
+	itemSM.Q = strings.TrimSpace(QueryString(query, "q"))
+	itemSM.Kind = strings.TrimSpace(QueryString(query, "kind"))
+	itemSM.ChannelId = strings.TrimSpace(QueryString(query, "channelId"))
+	itemSM.Sort = strings.TrimSpace(QueryString(query, "sort"))
+	itemSM.RegionCode = video.RegionCode(QueryString(query, "regionCode"))
+	itemSM.Duration = strings.TrimSpace(QueryString(query, "duration"))
+	itemSM.PublishedAfter = QueryTime(query, "publishedAfter")
+	itemSM.PublishedBefore = QueryTime(query, "publishedBefore")
+	itemSM.CategoryId = strings.TrimSpace(QueryString(query, "categoryId"))
+	itemSM.Caption = strings.TrimSpace(QueryString(query, "caption"))
+	itemSM.Definition = strings.TrimSpace(QueryString(query, "definition"))
+	itemSM.Dimension = strings.TrimSpace(QueryString(query, "dimension"))
+	itemSM.Embeddable = strings.TrimSpace(QueryString(query, "embeddable"))
+	itemSM.EventType = strings.TrimSpace(QueryString(query, "eventType"))
+	itemSM.License = strings.TrimSpace(QueryString(query, "license"))
+	itemSM.RelatedToVideoId = strings.TrimSpace(QueryString(query, "relatedToVideoId"))
+	itemSM.RelevanceLanguage = strings.TrimSpace(QueryString(query, "relevanceLanguage"))
+	itemSM.SafeSearch = strings.TrimSpace(QueryString(query, "safeSearch"))
+	itemSM.TopicId = strings.TrimSpace(QueryString(query, "topicId"))
+	itemSM.VideoType = strings.TrimSpace(QueryString(query, "videoType"))
+	return itemSM
+}
+
+func listContext(r *http.Request) context.Context {
+	if r.URL.Query().Get("includeUnavailable") == "true" {
+		return video.WithUnavailable(r.Context())
+	}
+	return r.Context()
+}
+
 func getFields(modelType reflect.Type) (res []string) {
 	for i := 0; i < modelType.NumField(); i++ {
 		field := modelType.Field(i)
@@ -323,9 +421,17 @@ func getFields(modelType reflect.Type) (res []string) {
 	return res
 }
 
+// getStatus maps a malformed or tampered page token, and a sort or field outside the model, to 400.
+func getStatus(err error) int {
+	if errors.Is(err, cursor.ErrInvalid) || errors.Is(err, video.ErrInvalidSort) || errors.Is(err, video.ErrInvalidField) || errors.Is(err, video.ErrInvalidKind) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func respond(w http.ResponseWriter, result interface{}) {
 	response, _ := json.Marshal(result)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write(response)
-}
\ No newline at end of file
+}
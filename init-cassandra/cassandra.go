@@ -1,8 +1,13 @@
 package cassandra
 
 import (
+	"context"
 	"fmt"
+	"strings"
+
 	"github.com/gocql/gocql"
+
+	"github.com/core-go/video/migration"
 )
 
 const (
@@ -12,7 +17,7 @@ const (
 		} AND durable_writes = 'true';`
 
 	CreateChannelTable = `
-					CREATE TABLE IF NOT EXISTS tube.channel (
+					CREATE TABLE IF NOT EXISTS channel (
 	id varchar,
 	count int,
 	country varchar,
@@ -34,15 +39,49 @@ const (
 	lastUpload timestamp,
 	title varchar,
 	uploads varchar,
+	viewCount bigint,
+	subscriberCount bigint,
+	videoCount bigint,
 	channels list<varchar>, 
 	PRIMARY KEY(id )
 );`
 	CreateChannelSyncTable = `
-					CREATE TABLE IF NOT EXISTS tube.channelSync (
+					CREATE TABLE IF NOT EXISTS channelSync (
 	id varchar,synctime timestamp,uploads varchar, level int, PRIMARY KEY(id )
+);`
+	CreateSyncCheckpointTable = `
+					CREATE TABLE IF NOT EXISTS syncCheckpoint (
+	id varchar,
+	channelId varchar,
+	pageToken varchar,
+	count int,
+	total int,
+	success int,
+	videoCount int,
+	videos list<varchar>,
+	lastUpload timestamp,
+	updatedAt timestamp,
+	PRIMARY KEY(id )
+);`
+	CreateSyncJobTable = `
+					CREATE TABLE IF NOT EXISTS syncJob (
+	id varchar,
+	type varchar,
+	targetId varchar,
+	level int,
+	status varchar,
+	pages int,
+	videos int,
+	playlists int,
+	errors list<varchar>,
+	result int,
+	createdAt timestamp,
+	startedAt timestamp,
+	endedAt timestamp,
+	PRIMARY KEY(id )
 );`
 	CreatePlaylistTable = `
-					CREATE TABLE IF NOT EXISTS tube.playlist (
+					CREATE TABLE IF NOT EXISTS playlist (
 	id varchar,
 	channelId varchar,
 	channelTitle varchar,
@@ -61,13 +100,13 @@ const (
 	PRIMARY KEY(id )
 );`
 	CreatePlaylistVideoTable = `
-					CREATE TABLE IF NOT EXISTS tube.playlistvideo (
+					CREATE TABLE IF NOT EXISTS playlistvideo (
 	id varchar,
 	videos list<varchar>,
 	 PRIMARY KEY(id )
 );`
 	CreateVideoTable = `
-					CREATE TABLE IF NOT EXISTS tube.video (
+					CREATE TABLE IF NOT EXISTS video (
 	id varchar,
 	caption varchar,
 	categoryId varchar,
@@ -81,67 +120,134 @@ const (
 	duration int,
 	highThumbnail varchar,
 	licensedContent boolean,
+	license varchar,
+	embeddable boolean,
 	liveBroadcastContent varchar,
+	liveStatus varchar,
+	scheduledStartTime timestamp,
+	scheduledEndTime timestamp,
+	actualStartTime timestamp,
+	actualEndTime timestamp,
 	localizedDescription varchar,
 	localizedTitle varchar,
 	maxresThumbnail varchar,
 	mediumThumbnail varchar,
 	projection varchar,
+	ytRating varchar,
 	publishedAt timestamp,
 	standardThumbnail varchar,
 	tags list<varchar>,
+	topicIds list<varchar>,
 	thumbnail varchar,
 	title varchar,
 	blockedRegions list<varchar>,
 	allowedRegions list<varchar>, 
+	viewCount bigint,
+	likeCount bigint,
+	commentCount bigint,
 	PRIMARY KEY((id) )
 );`
-	CreateCategoryType = `CREATE TYPE IF NOT EXISTS tube.categoriesType (
+	CreateVideoStatisticsTable = `
+					CREATE TABLE IF NOT EXISTS videoStatistics (
+	videoId varchar,
+	timestamp timestamp,
+	viewCount bigint,
+	likeCount bigint,
+	commentCount bigint,
+	PRIMARY KEY((videoId), timestamp)
+);`
+	CreateSubscriptionTable = `
+					CREATE TABLE IF NOT EXISTS subscription (
+	subscriberId varchar,
+	channelId varchar,
+	id varchar,
+	title varchar,
+	thumbnail varchar,
+	publishedAt timestamp,
+	PRIMARY KEY((subscriberId), channelId)
+);`
+	CreateSubscriptionChannelIndex = `CREATE INDEX IF NOT EXISTS subscription_channel_index ON subscription (channelId);`
+	CreatePlaylistItemTable        = `
+					CREATE TABLE IF NOT EXISTS playlistItem (
+	playlistId varchar,
+	videoId varchar,
+	id varchar,
+	position int,
+	videoOwnerChannelId varchar,
+	videoOwnerChannelTitle varchar,
+	addedAt timestamp,
+	updatedAt timestamp,
+	removedAt timestamp,
+	PRIMARY KEY((playlistId), videoId)
+);`
+	CreateCategoryType = `CREATE TYPE IF NOT EXISTS categoriesType (
 	id varchar,
 	title varchar,
 	assignable boolean,
 	channelId varchar
 );`
-	CreateCategoryTable = `CREATE TABLE IF NOT EXISTS tube.category (
+	CreateCategoryTable = `CREATE TABLE IF NOT EXISTS category (
 	id varchar,
 	data list<frozen<categoriesType>>, 
 	PRIMARY KEY(id )
 );`
 
-	// run LuceneIndex must use cmd cqlsh
-	CreateVideoLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS video_index ON tube.video (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
+	// run LuceneIndex must use cmd cqlsh, after USE of the keyspace
+	CreateVideoLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS video_index ON video (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
 		'refresh_seconds': '1',
 		'schema': '{
 				fields: {
 					"id":{"type":"text"},
-					"caption":{"type":"boolean"},
-					"categoryid":{"type":"text"},
+					"caption":{"type":"string"},
+					"categoryid":{"type":"string"},
 					"channelid":{"type":"text"},
 					"channeltitle":{"type":"text"},
-					"defaultaudiolanguage":{"type":"text"},
-					"defaultlanguage":{"type":"text"},
+					"defaultaudiolanguage":{"type":"string","case_sensitive":false},
+					"defaultlanguage":{"type":"string","case_sensitive":false},
 					"definition":{"type":"float"},
 					"description":{"type":"text"},
-					"dimension":{"type":"text"},
+					"dimension":{"type":"string"},
 					"duration":{"type":"float"},
 					"highthumbnail":{"type":"float"},
 					"licensedcontent":{"type":"boolean"},
-					"livebroadcastcontent":{"type":"text"},
+					"license":{"type":"string"},
+					"embeddable":{"type":"boolean"},
+					"livebroadcastcontent":{"type":"string"},
+					"livestatus":{"type":"string"},
 					"localizeddescription":{"type":"text"},
 					"localizedtitle":{"type":"text"},
 					"maxresthumbnail":{"type":"text"},
 					"mediumthumbnail":{"type":"text"},
 					"projection":{"type":"text"},
+					"ytrating":{"type":"string"},
 					"publishedat":{"type":"date","pattern":"yyyy-MM-dd HH:mm:ss"},
 					"standardthumbnail":{"type":"text"},
 					"blockedregions":{"type":"string"},
+					"allowedregions":{"type":"string"},
 					"tags":{"type":"string"},
+					"topicids":{"type":"string"},
 					"thumbnail":{"type":"text"},
-					"title":{"type":"string"}
+					"title":{"type":"string"},
+					"sorttitle":{"type":"string","column":"title","case_sensitive":false},
+					"viewcount":{"type":"long"},
+					"likecount":{"type":"long"},
+					"commentcount":{"type":"long"},
+					"status":{"type":"string"},
+					"tombstonedat":{"type":"date","pattern":"yyyy-MM-dd HH:mm:ss"}
+				}
+		}'
+};`
+	CreateVideoStatisticsLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS video_statistics_index ON videoStatistics () USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
+		'refresh_seconds': '1',
+		'schema': '{
+				fields: {
+					"videoid":{"type":"string"},
+					"timestamp":{"type":"date","pattern":"yyyy-MM-dd HH:mm:ss"},
+					"viewcount":{"type":"long"}
 				}
 		}'
 };`
-	CreateChannelLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS channel_index  ON tube.channel (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
+	CreateChannelLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS channel_index  ON channel (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
 		'refresh_seconds': '1',
 		'schema': '{	
 				fields: {
@@ -166,11 +272,15 @@ const (
 					"lastupload":{"type":"date",
 					"pattern":"yyyy-MM-dd HH:mm:ss"},
 					"title":{"type":"text"},
-					"uploads":{"type":"text"}
+					"sorttitle":{"type":"string","column":"title","case_sensitive":false},
+					"uploads":{"type":"text"},
+					"viewcount":{"type":"long"},
+					"subscribercount":{"type":"long"},
+					"videocount":{"type":"long"}
 				}
 		}'
 };`
-	CreatePlaylistLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS playlist_index ON tube.playlist (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
+	CreatePlaylistLuceneIndex = `CREATE CUSTOM INDEX IF NOT EXISTS playlist_index ON playlist (title) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {
 		'refresh_seconds': '1',
 		'schema': '{
 				fields: {
@@ -188,26 +298,95 @@ const (
 					"publishedat":{"type":"date","pattern":"yyyy-MM-dd HH:mm:ss"},
 					"standardthumbnail":{"type":"text"},
 					"thumbnail":{"type":"text"},
-					"title":{"type":"text"}
+					"title":{"type":"text"},
+					"sorttitle":{"type":"string","column":"title","case_sensitive":false}
 				}
 		}'
 };	`
 )
 
+// Migrations does not create the Lucene indexes, which must be created with cqlsh.
+func Migrations(session *gocql.Session, keyspace string) []migration.Migration {
+	return []migration.Migration{
+		{Version: 1, Description: "create channel, playlist, video and category tables", Up: exec(session,
+			CreateChannelTable, CreateChannelSyncTable, CreatePlaylistTable, CreatePlaylistVideoTable, CreateVideoTable, CreateCategoryType, CreateCategoryTable)},
+		{Version: 2, Description: "add license, embeddable, ytRating and topicIds to video", Up: addColumns(session, keyspace, "video",
+			[2]string{"license", "varchar"}, [2]string{"embeddable", "boolean"}, [2]string{"ytRating", "varchar"}, [2]string{"topicIds", "list<varchar>"})},
+		{Version: 3, Description: "create sync checkpoint and job tables", Up: exec(session, CreateSyncCheckpointTable, CreateSyncJobTable)},
+		{Version: 4, Description: "add statistics to video and channel, create videoStatistics table", Up: func(ctx context.Context) error {
+			if err := addColumns(session, keyspace, "video", [2]string{"viewCount", "bigint"}, [2]string{"likeCount", "bigint"}, [2]string{"commentCount", "bigint"})(ctx); err != nil {
+				return err
+			}
+			if err := addColumns(session, keyspace, "channel", [2]string{"viewCount", "bigint"}, [2]string{"subscriberCount", "bigint"}, [2]string{"videoCount", "bigint"})(ctx); err != nil {
+				return err
+			}
+			return exec(session, CreateVideoStatisticsTable)(ctx)
+		}},
+		{Version: 5, Description: "create subscription table", Up: exec(session, CreateSubscriptionTable, CreateSubscriptionChannelIndex)},
+		{Version: 6, Description: "add live status and live streaming times to video", Up: addColumns(session, keyspace, "video",
+			[2]string{"liveStatus", "varchar"}, [2]string{"scheduledStartTime", "timestamp"}, [2]string{"scheduledEndTime", "timestamp"},
+			[2]string{"actualStartTime", "timestamp"}, [2]string{"actualEndTime", "timestamp"})},
+		{Version: 7, Description: "create playlistItem table", Up: exec(session, CreatePlaylistItemTable)},
+		{Version: 8, Description: "add status and tombstonedAt to video", Up: addColumns(session, keyspace, "video",
+			[2]string{"status", "varchar"}, [2]string{"tombstonedAt", "timestamp"})},
+	}
+}
+
 func Initialize(cluster *gocql.ClusterConfig, keyspace string) (*gocql.Session, error) {
 	session, err := cluster.CreateSession()
 	if err != nil {
 		return nil, err
 	}
 	err = session.Query(fmt.Sprintf(CreateKeyspace, keyspace)).Exec()
+	session.Close()
 	if err != nil {
 		return nil, err
 	}
-	// create table
 	cluster.Keyspace = keyspace
 	session, err = cluster.CreateSession()
 	if err != nil {
 		return nil, err
 	}
+	ctx := context.Background()
+	_, err = migration.Migrate(ctx, NewCassandraVersionRepository(session), Migrations(session, keyspace))
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
 	return session, nil
 }
+
+func exec(session *gocql.Session, stmts ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, stmt := range stmts {
+			if err := session.Query(stmt).WithContext(ctx).Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumns skips existing columns, as Cassandra has no ADD IF NOT EXISTS before 4.1.
+func addColumns(session *gocql.Session, keyspace string, table string, columns ...[2]string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		iter := session.Query(`SELECT column_name FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?`, keyspace, strings.ToLower(table)).WithContext(ctx).Iter()
+		exists := make(map[string]bool)
+		var name string
+		for iter.Scan(&name) {
+			exists[name] = true
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if exists[strings.ToLower(column[0])] {
+				continue
+			}
+			if err := session.Query(fmt.Sprintf(`ALTER TABLE %s ADD %s %s`, table, column[0], column[1])).WithContext(ctx).Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
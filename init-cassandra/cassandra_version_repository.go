@@ -0,0 +1,84 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/core-go/video/migration"
+)
+
+const CreateVersionTable = `
+					CREATE TABLE IF NOT EXISTS schemaVersion (
+	version int,
+	description varchar,
+	appliedAt timestamp,
+	PRIMARY KEY(version )
+);`
+
+const CreateVersionLockTable = `
+CREATE TABLE IF NOT EXISTS schemaVersionLock (
+	id varchar,
+	owner timeuuid,
+	PRIMARY KEY(id)
+);`
+
+type CassandraVersionRepository struct {
+	Session *gocql.Session
+}
+
+// NewCassandraVersionRepository records versions in the schemaVersion table of the keyspace of session.
+func NewCassandraVersionRepository(session *gocql.Session) *CassandraVersionRepository {
+	return &CassandraVersionRepository{Session: session}
+}
+
+func (s *CassandraVersionRepository) GetVersions(ctx context.Context) ([]migration.Version, error) {
+	if err := s.Session.Query(CreateVersionTable).WithContext(ctx).Exec(); err != nil {
+		return nil, err
+	}
+	iter := s.Session.Query(`SELECT version, description, appliedAt FROM schemaVersion`).WithContext(ctx).Iter()
+	var versions []migration.Version
+	var v migration.Version
+	for iter.Scan(&v.Version, &v.Description, &v.AppliedAt) {
+		versions = append(versions, v)
+		v = migration.Version{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (s *CassandraVersionRepository) SaveVersion(ctx context.Context, version migration.Version) (int, error) {
+	query := `INSERT INTO schemaVersion (version, description, appliedAt) VALUES (?, ?, ?)`
+	if err := s.Session.Query(query, version.Version, version.Description, version.AppliedAt).WithContext(ctx).Exec(); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Lock inserts the lock row as a lightweight transaction, with a TTL of migration.LockExpiry that is renewed while held.
+func (s *CassandraVersionRepository) Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error) {
+	if err := s.Session.Query(CreateVersionLockTable).WithContext(ctx).Exec(); err != nil {
+		return nil, nil, err
+	}
+	owner := gocql.TimeUUID()
+	ttl := int(migration.LockExpiry / time.Second)
+	err := migration.Poll(ctx, func(ctx context.Context) (bool, error) {
+		query := `INSERT INTO schemaVersionLock (id, owner) VALUES ('migration', ?) IF NOT EXISTS USING TTL ?`
+		return s.Session.Query(query, owner, ttl).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	held, unlock := migration.Hold(ctx, func(ctx context.Context) (bool, error) {
+		query := `UPDATE schemaVersionLock USING TTL ? SET owner = ? WHERE id = 'migration' IF owner = ?`
+		return s.Session.Query(query, ttl, owner, owner).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	}, func(ctx context.Context) error {
+		query := `DELETE FROM schemaVersionLock WHERE id = 'migration' IF owner = ?`
+		_, err := s.Session.Query(query, owner).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		return err
+	})
+	return held, unlock, nil
+}
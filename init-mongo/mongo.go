@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/core-go/video/migration"
+)
+
+const VersionCollection = "schemaVersion"
+
+// Migrations takes the collection names of NewMongoVideoService. A new field needs a migration only for its index.
+func Migrations(db *mongo.Database, channelCollectionName string, channelSyncCollectionName string, playlistCollectionName string, playlistVideoCollectionName string, videoCollectionName string, categoryCollection string, options ...string) []migration.Migration {
+	statisticsCollection := "videoStatistics"
+	if len(options) > 0 && len(options[0]) > 0 {
+		statisticsCollection = options[0]
+	}
+	subscriptionCollection := "subscription"
+	if len(options) > 1 && len(options[1]) > 0 {
+		subscriptionCollection = options[1]
+	}
+	playlistItemCollection := "playlistItem"
+	if len(options) > 2 && len(options[2]) > 0 {
+		playlistItemCollection = options[2]
+	}
+	return []migration.Migration{
+		{Version: 1, Description: "create channel, playlist, video and category collections", Up: func(ctx context.Context) error {
+			if err := createCollections(ctx, db, channelCollectionName, channelSyncCollectionName, playlistCollectionName, playlistVideoCollectionName, videoCollectionName, categoryCollection); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db.Collection(videoCollectionName),
+				bson.D{{"channelId", 1}, {"publishedAt", -1}, {"_id", 1}},
+				bson.D{{"publishedAt", -1}, {"_id", 1}}); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection(playlistCollectionName), bson.D{{"channelId", 1}, {"publishedAt", -1}, {"_id", 1}})
+		}},
+		{Version: 2, Description: "create videoStatistics collection, index video by viewCount", Up: func(ctx context.Context) error {
+			if err := createCollections(ctx, db, statisticsCollection); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db.Collection(statisticsCollection), bson.D{{"videoId", 1}, {"timestamp", -1}}); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection(videoCollectionName), bson.D{{"viewCount", -1}, {"_id", 1}})
+		}},
+		{Version: 3, Description: "create subscription collection", Up: func(ctx context.Context) error {
+			if err := createCollections(ctx, db, subscriptionCollection); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection(subscriptionCollection),
+				bson.D{{"subscriberId", 1}, {"channelId", 1}},
+				bson.D{{"channelId", 1}, {"subscriberId", 1}})
+		}},
+		{Version: 4, Description: "create playlistItem collection", Up: func(ctx context.Context) error {
+			if err := createCollections(ctx, db, playlistItemCollection); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection(playlistItemCollection), bson.D{{"playlistId", 1}, {"position", 1}, {"videoId", 1}})
+		}},
+		{Version: 5, Description: "index video by status and tombstonedAt", Up: func(ctx context.Context) error {
+			return createIndexes(ctx, db.Collection(videoCollectionName), bson.D{{"status", 1}, {"tombstonedAt", 1}})
+		}},
+	}
+}
+
+func Initialize(ctx context.Context, db *mongo.Database, channelCollectionName string, channelSyncCollectionName string, playlistCollectionName string, playlistVideoCollectionName string, videoCollectionName string, categoryCollection string, options ...string) (int, error) {
+	migrations := Migrations(db, channelCollectionName, channelSyncCollectionName, playlistCollectionName, playlistVideoCollectionName, videoCollectionName, categoryCollection, options...)
+	return migration.Migrate(ctx, NewMongoVersionRepository(db, VersionCollection), migrations)
+}
+
+func createCollections(ctx context.Context, db *mongo.Database, names ...string) error {
+	existing, er0 := db.ListCollectionNames(ctx, bson.M{"name": bson.M{"$in": names}})
+	if er0 != nil {
+		return er0
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+	for _, name := range names {
+		if exists[name] {
+			continue
+		}
+		if er1 := db.CreateCollection(ctx, name); er1 != nil {
+			return er1
+		}
+		exists[name] = true
+	}
+	return nil
+}
+
+func createIndexes(ctx context.Context, collection *mongo.Collection, keys ...bson.D) error {
+	models := make([]mongo.IndexModel, len(keys))
+	for i, k := range keys {
+		models[i] = mongo.IndexModel{Keys: k}
+	}
+	_, err := collection.Indexes().CreateMany(ctx, models)
+	return err
+}
@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/core-go/video/migration"
+)
+
+type MongoVersionRepository struct {
+	Collection     *mongo.Collection
+	LockCollection *mongo.Collection
+}
+
+// NewMongoVersionRepository keeps the lock in collectionName with Lock appended.
+func NewMongoVersionRepository(db *mongo.Database, collectionName string) *MongoVersionRepository {
+	return &MongoVersionRepository{Collection: db.Collection(collectionName), LockCollection: db.Collection(collectionName + "Lock")}
+}
+
+func (m *MongoVersionRepository) GetVersions(ctx context.Context) ([]migration.Version, error) {
+	cur, er0 := m.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if er0 != nil {
+		return nil, er0
+	}
+	var versions []migration.Version
+	if er1 := cur.All(ctx, &versions); er1 != nil {
+		return nil, er1
+	}
+	return versions, nil
+}
+
+func (m *MongoVersionRepository) SaveVersion(ctx context.Context, version migration.Version) (int, error) {
+	res, err := m.Collection.ReplaceOne(ctx, bson.M{"_id": version.Version}, version, options.Replace().SetUpsert(true))
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount + res.UpsertedCount), nil
+}
+
+// Lock upserts the lock document when it expired; a process that races on its _id waits. lockedAt is renewed while held.
+func (m *MongoVersionRepository) Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error) {
+	owner := primitive.NewObjectID()
+	err := migration.Poll(ctx, func(ctx context.Context) (bool, error) {
+		now := time.Now()
+		filter := bson.M{"_id": "migration", "lockedAt": bson.M{"$lt": now.Add(-migration.LockExpiry)}}
+		update := bson.M{"$set": bson.M{"owner": owner, "lockedAt": now}}
+		err := m.LockCollection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true)).Err()
+		if err == nil || err == mongo.ErrNoDocuments {
+			return true, nil
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	held, unlock := migration.Hold(ctx, func(ctx context.Context) (bool, error) {
+		res, err := m.LockCollection.UpdateOne(ctx, bson.M{"_id": "migration", "owner": owner}, bson.M{"$set": bson.M{"lockedAt": time.Now()}})
+		if err != nil {
+			return false, err
+		}
+		return res.MatchedCount > 0, nil
+	}, func(ctx context.Context) error {
+		_, err := m.LockCollection.DeleteOne(ctx, bson.M{"_id": "migration", "owner": owner})
+		return err
+	})
+	return held, unlock, nil
+}
@@ -0,0 +1,259 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/core-go/video/migration"
+	syncpg "github.com/core-go/video/sync-pg"
+)
+
+const (
+	CreateSchema = `create schema if not exists %s`
+
+	CreateChannelTable = `
+create table if not exists channel (
+	id varchar(255) primary key,
+	count integer,
+	country varchar(255),
+	customUrl varchar(255),
+	description text,
+	favorites varchar(255),
+	highThumbnail varchar(1024),
+	itemCount integer,
+	likes varchar(255),
+	localizedDescription text,
+	localizedTitle varchar(1024),
+	mediumThumbnail varchar(1024),
+	playlistCount integer,
+	playlistItemCount integer,
+	playlistVideoCount integer,
+	playlistVideoItemCount integer,
+	publishedAt timestamp with time zone,
+	thumbnail varchar(1024),
+	lastUpload timestamp with time zone,
+	title varchar(1024),
+	uploads varchar(255),
+	channels varchar(255)[]
+)`
+	CreateChannelSyncTable = `
+create table if not exists channelSync (
+	id varchar(255) primary key,
+	synctime timestamp with time zone,
+	uploads varchar(255)
+)`
+	CreatePlaylistTable = `
+create table if not exists playlist (
+	id varchar(255) primary key,
+	channelId varchar(255),
+	channelTitle varchar(1024),
+	count integer,
+	itemCount integer,
+	description text,
+	highThumbnail varchar(1024),
+	localizedDescription text,
+	localizedTitle varchar(1024),
+	maxresThumbnail varchar(1024),
+	mediumThumbnail varchar(1024),
+	publishedAt timestamp with time zone,
+	standardThumbnail varchar(1024),
+	thumbnail varchar(1024),
+	title varchar(1024)
+)`
+	CreatePlaylistVideoTable = `
+create table if not exists playlistVideo (
+	id varchar(255) primary key,
+	videos varchar(255)[]
+)`
+	CreateVideoTable = `
+create table if not exists video (
+	id varchar(255) primary key,
+	caption varchar(255),
+	categoryId varchar(255),
+	channelId varchar(255),
+	channelTitle varchar(1024),
+	defaultAudioLanguage varchar(255),
+	defaultLanguage varchar(255),
+	definition integer,
+	description text,
+	dimension varchar(255),
+	duration bigint,
+	highThumbnail varchar(1024),
+	licensedContent boolean,
+	liveBroadcastContent varchar(255),
+	localizedDescription text,
+	localizedTitle varchar(1024),
+	maxresThumbnail varchar(1024),
+	mediumThumbnail varchar(1024),
+	projection varchar(255),
+	publishedAt timestamp with time zone,
+	standardThumbnail varchar(1024),
+	tags varchar(1024)[],
+	thumbnail varchar(1024),
+	title varchar(1024),
+	blockedRegions varchar(255)[],
+	allowedRegions varchar(255)[]
+)`
+	CreateVideoChannelIndex    = `create index if not exists video_channelId on video (channelId, publishedAt desc, id)`
+	CreateVideoPublishedIndex  = `create index if not exists video_publishedAt on video (publishedAt desc, id)`
+	CreatePlaylistChannelIndex = `create index if not exists playlist_channelId on playlist (channelId, publishedAt desc, id)`
+	CreateCategoryTable        = `
+create table if not exists category (
+	id varchar(255) primary key,
+	data jsonb[]
+)`
+
+	AlterVideoMetadata = `
+alter table video
+	add column if not exists license varchar(255),
+	add column if not exists embeddable boolean,
+	add column if not exists ytRating varchar(255),
+	add column if not exists topicIds varchar(1024)[]`
+
+	CreateSyncCheckpointTable = `
+create table if not exists syncCheckpoint (
+	id varchar(255) primary key,
+	channelId varchar(255),
+	pageToken varchar(255),
+	count integer,
+	total integer,
+	success integer,
+	videoCount integer,
+	videos varchar(255)[],
+	lastUpload timestamp with time zone,
+	updatedAt timestamp with time zone
+)`
+	CreateSyncJobTable = `
+create table if not exists syncJob (
+	id varchar(255) primary key,
+	type varchar(255),
+	targetId varchar(255),
+	level integer,
+	status varchar(255),
+	pages integer,
+	videos integer,
+	playlists integer,
+	errors text[],
+	result integer,
+	createdAt timestamp with time zone,
+	startedAt timestamp with time zone,
+	endedAt timestamp with time zone
+)`
+
+	AlterVideoStatistics = `
+alter table video
+	add column if not exists viewCount bigint,
+	add column if not exists likeCount bigint,
+	add column if not exists commentCount bigint`
+	AlterChannelStatistics = `
+alter table channel
+	add column if not exists viewCount bigint,
+	add column if not exists subscriberCount bigint,
+	add column if not exists videoCount bigint`
+	CreateVideoStatisticsTable = `
+create table if not exists videoStatistics (
+	videoId varchar(255),
+	timestamp timestamp with time zone,
+	viewCount bigint,
+	likeCount bigint,
+	commentCount bigint,
+	primary key (videoId, timestamp)
+)`
+	CreateVideoViewIndex = `create index if not exists video_viewCount on video (viewCount desc nulls last, id)`
+
+	CreateSubscriptionTable = `
+create table if not exists subscription (
+	id varchar(511) primary key,
+	subscriberId varchar(255),
+	channelId varchar(255),
+	title varchar(1024),
+	thumbnail varchar(1024),
+	publishedAt timestamp with time zone
+)`
+	AlterVideoLiveStreaming = `
+alter table video
+	add column if not exists liveStatus varchar(255),
+	add column if not exists scheduledStartTime timestamp with time zone,
+	add column if not exists scheduledEndTime timestamp with time zone,
+	add column if not exists actualStartTime timestamp with time zone,
+	add column if not exists actualEndTime timestamp with time zone`
+	CreateSubscriptionSubscriberIndex = `create index if not exists subscription_subscriberId on subscription (subscriberId, channelId)`
+	CreateSubscriptionChannelIndex    = `create index if not exists subscription_channelId on subscription (channelId, subscriberId)`
+
+	CreatePlaylistItemTable = `
+create table if not exists playlistItem (
+	id varchar(511) primary key,
+	playlistId varchar(255),
+	videoId varchar(255),
+	position integer,
+	videoOwnerChannelId varchar(255),
+	videoOwnerChannelTitle varchar(1024),
+	addedAt timestamp with time zone,
+	updatedAt timestamp with time zone,
+	removedAt timestamp with time zone
+)`
+	CreatePlaylistItemPositionIndex = `create index if not exists playlistItem_position on playlistItem (playlistId, position, videoId)`
+
+	AlterVideoTombstone = `
+alter table video
+	add column if not exists status varchar(255),
+	add column if not exists tombstonedAt timestamp with time zone`
+	CreateVideoTombstoneIndex = `create index if not exists video_tombstone on video (status, tombstonedAt) where status is not null`
+
+	DropIdIndexes               = `drop index if exists video_channelId, video_publishedAt, playlist_channelId, video_viewCount`
+	CreateVideoChannelCIndex    = `create index if not exists video_channelId on video (channelId, publishedAt desc, id collate "C")`
+	CreateVideoPublishedCIndex  = `create index if not exists video_publishedAt on video (publishedAt desc, id collate "C")`
+	CreatePlaylistChannelCIndex = `create index if not exists playlist_channelId on playlist (channelId, publishedAt desc, id collate "C")`
+	CreateVideoViewCIndex       = `create index if not exists video_viewCount on video (viewCount desc nulls last, id collate "C")`
+)
+
+func Migrations(db *sql.DB, schema string) []migration.Migration {
+	return []migration.Migration{
+		{Version: 1, Description: "create channel, playlist, video and category tables", Up: exec(db, schema,
+			CreateChannelTable, CreateChannelSyncTable, CreatePlaylistTable, CreatePlaylistVideoTable, CreateVideoTable,
+			CreateVideoChannelIndex, CreateVideoPublishedIndex, CreatePlaylistChannelIndex, CreateCategoryTable)},
+		{Version: 2, Description: "add license, embeddable, ytRating and topicIds to video", Up: exec(db, schema, AlterVideoMetadata)},
+		{Version: 3, Description: "create sync checkpoint and job tables", Up: exec(db, schema, CreateSyncCheckpointTable, CreateSyncJobTable)},
+		{Version: 4, Description: "add statistics to video and channel, create videoStatistics table", Up: exec(db, schema,
+			AlterVideoStatistics, AlterChannelStatistics, CreateVideoStatisticsTable, CreateVideoViewIndex)},
+		{Version: 5, Description: "add search vectors to channel, playlist and video", Up: exec(db, schema, syncpg.SearchSchema()...)},
+		{Version: 6, Description: "create subscription table", Up: exec(db, schema,
+			CreateSubscriptionTable, CreateSubscriptionSubscriberIndex, CreateSubscriptionChannelIndex)},
+		{Version: 7, Description: "add live status and live streaming times to video", Up: exec(db, schema, AlterVideoLiveStreaming)},
+		{Version: 8, Description: "create playlistItem table", Up: exec(db, schema, CreatePlaylistItemTable, CreatePlaylistItemPositionIndex)},
+		{Version: 9, Description: "add status and tombstonedAt to video", Up: exec(db, schema, AlterVideoTombstone, CreateVideoTombstoneIndex)},
+		{Version: 10, Description: "order the id of the paging indexes with the C collation", Up: exec(db, schema,
+			DropIdIndexes, CreateVideoChannelCIndex, CreateVideoPublishedCIndex, CreatePlaylistChannelCIndex, CreateVideoViewCIndex)},
+	}
+}
+
+// Initialize needs connections with schema first in their search_path, as table names are not qualified.
+func Initialize(ctx context.Context, db *sql.DB, schema string) (int, error) {
+	if len(schema) == 0 {
+		schema = "public"
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(CreateSchema, pq.QuoteIdentifier(schema))); err != nil {
+		return 0, err
+	}
+	return migration.Migrate(ctx, NewPostgreVersionRepository(db, schema), Migrations(db, schema))
+}
+
+func exec(db *sql.DB, schema string, stmts ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		tx, er0 := db.BeginTx(ctx, nil)
+		if er0 != nil {
+			return er0
+		}
+		all := append([]string{"set local search_path to " + pq.QuoteIdentifier(schema)}, stmts...)
+		for _, stmt := range all {
+			if _, er1 := tx.ExecContext(ctx, stmt); er1 != nil {
+				tx.Rollback()
+				return er1
+			}
+		}
+		return tx.Commit()
+	}
+}
@@ -0,0 +1,80 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/core-go/video/migration"
+)
+
+const CreateVersionTable = `
+create table if not exists %s.schemaVersion (
+	version integer primary key,
+	description varchar(1024),
+	appliedAt timestamp with time zone
+)`
+
+type PostgreVersionRepository struct {
+	DB     *sql.DB
+	schema string
+}
+
+// NewPostgreVersionRepository records versions in the schemaVersion table of schema.
+func NewPostgreVersionRepository(db *sql.DB, schema string) *PostgreVersionRepository {
+	return &PostgreVersionRepository{DB: db, schema: pq.QuoteIdentifier(schema)}
+}
+
+func (s *PostgreVersionRepository) GetVersions(ctx context.Context) ([]migration.Version, error) {
+	if _, er0 := s.DB.ExecContext(ctx, fmt.Sprintf(CreateVersionTable, s.schema)); er0 != nil {
+		return nil, er0
+	}
+	rows, er1 := s.DB.QueryContext(ctx, "select version, description, appliedAt from "+s.schema+".schemaVersion order by version")
+	if er1 != nil {
+		return nil, er1
+	}
+	defer rows.Close()
+	var versions []migration.Version
+	for rows.Next() {
+		var v migration.Version
+		var description sql.NullString
+		if er2 := rows.Scan(&v.Version, &description, &v.AppliedAt); er2 != nil {
+			return nil, er2
+		}
+		v.Description = description.String
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (s *PostgreVersionRepository) SaveVersion(ctx context.Context, version migration.Version) (int, error) {
+	query := "insert into " + s.schema + ".schemaVersion (version, description, appliedAt) values ($1, $2, $3) on conflict (version) do nothing"
+	res, err := s.DB.ExecContext(ctx, query, version.Version, version.Description, version.AppliedAt)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Lock takes the advisory lock of the schema on a connection of its own, so the lock is released if the process stops.
+func (s *PostgreVersionRepository) Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error) {
+	conn, er0 := s.DB.Conn(ctx)
+	if er0 != nil {
+		return nil, nil, er0
+	}
+	key := "schemaVersion " + s.schema
+	if _, er1 := conn.ExecContext(ctx, "select pg_advisory_lock(hashtext($1))", key); er1 != nil {
+		conn.Close()
+		return nil, nil, er1
+	}
+	return ctx, func(ctx context.Context) error {
+		_, er2 := conn.ExecContext(ctx, "select pg_advisory_unlock(hashtext($1))", key)
+		if er3 := conn.Close(); er2 == nil {
+			er2 = er3
+		}
+		return er2
+	}, nil
+}
@@ -0,0 +1,168 @@
+package inmemory
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/core-go/video"
+)
+
+func matchChannel(s video.ChannelSM, channel video.Channel) bool {
+	if len(s.ChannelId) > 0 && channel.Id != s.ChannelId {
+		return false
+	}
+	if len(s.RegionCode) > 0 && !strings.EqualFold(channel.Country, s.RegionCode) {
+		return false
+	}
+	if !inRange(channel.PublishedAt, s.PublishedAfter, s.PublishedBefore) {
+		return false
+	}
+	return matchText(s.Q, channel.Title, channel.Description)
+}
+
+func matchPlaylist(s video.PlaylistSM, playlist video.Playlist) bool {
+	if len(s.ChannelId) > 0 && playlist.ChannelId != s.ChannelId {
+		return false
+	}
+	if !inRange(playlist.PublishedAt, s.PublishedAfter, s.PublishedBefore) {
+		return false
+	}
+	return matchText(s.Q, playlist.Title, playlist.Description)
+}
+
+// matchVideo takes the tags of ItemSM.RelatedToVideoId as related.
+func matchVideo(s video.ItemSM, v video.Video, related map[string]bool) bool {
+	if len(s.ChannelId) > 0 && v.ChannelId != s.ChannelId {
+		return false
+	}
+	if len(s.CategoryId) > 0 && v.CategoryId != s.CategoryId {
+		return false
+	}
+	if !video.Available(v, s.RegionCode) {
+		return false
+	}
+	if !inRange(v.PublishedAt, s.PublishedAfter, s.PublishedBefore) {
+		return false
+	}
+	switch s.Duration {
+	case "short":
+		if v.Duration <= 0 || v.Duration > 240 {
+			return false
+		}
+	case "medium":
+		if v.Duration <= 240 || v.Duration > 1200 {
+			return false
+		}
+	case "long":
+		if v.Duration <= 1200 {
+			return false
+		}
+	}
+	switch s.Caption {
+	case "closedCaption":
+		if v.Caption != "true" {
+			return false
+		}
+	case "none":
+		if v.Caption == "true" {
+			return false
+		}
+	}
+	switch s.Definition {
+	case "high":
+		if v.Definition != 5 {
+			return false
+		}
+	case "standard":
+		if v.Definition == 5 {
+			return false
+		}
+	}
+	if (s.Dimension == "2d" || s.Dimension == "3d") && v.Dimension != s.Dimension {
+		return false
+	}
+	switch s.EventType {
+	case "live", "upcoming":
+		if v.LiveBroadcastContent != s.EventType {
+			return false
+		}
+	case "completed":
+		if v.LiveBroadcastContent == "live" || v.LiveBroadcastContent == "upcoming" {
+			return false
+		}
+	}
+	if len(s.License) > 0 && s.License != "any" && v.License != s.License {
+		return false
+	}
+	if s.Embeddable == "true" && (v.Embeddable == nil || !*v.Embeddable) {
+		return false
+	}
+	if (s.SafeSearch == "moderate" || s.SafeSearch == "strict") && v.YtRating == video.AgeRestricted {
+		return false
+	}
+	if len(s.TopicId) > 0 && !contains(v.TopicIds, s.TopicId) {
+		return false
+	}
+	if category, ok := video.VideoTypeCategories[s.VideoType]; ok && v.CategoryId != category {
+		return false
+	}
+	if len(s.RelevanceLanguage) > 0 && !matchLanguage(s.RelevanceLanguage, v.DefaultLanguage, v.DefaultAudioLanguage) {
+		return false
+	}
+	if related != nil {
+		if v.Id == s.RelatedToVideoId || !hasTag(v.Tags, related) {
+			return false
+		}
+	}
+	return matchText(s.Q, v.Title, v.Description)
+}
+
+func matchText(q string, title string, description string) bool {
+	if len(q) == 0 {
+		return true
+	}
+	q = strings.ToLower(q)
+	return strings.Contains(strings.ToLower(title), q) || strings.Contains(strings.ToLower(description), q)
+}
+
+func matchLanguage(language string, languages ...string) bool {
+	declared := false
+	for _, l := range languages {
+		if len(l) == 0 {
+			continue
+		}
+		declared = true
+		if strings.EqualFold(l, language) || strings.HasPrefix(strings.ToLower(l), strings.ToLower(language)+"-") {
+			return true
+		}
+	}
+	return !declared
+}
+
+func hasTag(tags []string, set map[string]bool) bool {
+	for _, tag := range tags {
+		if set[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+func sortItems(items interface{}, sortable video.Sortable, sort string, q string) (order, []entry, error) {
+	keys, err := video.ParseSort(sort, sortable, q)
+	if err != nil {
+		return order{}, nil, err
+	}
+	v := reflect.ValueOf(items)
+	o, values, err := newOrder(v.Type().Elem(), keys, q)
+	if err != nil {
+		return order{}, nil, err
+	}
+	entries := make([]entry, v.Len())
+	for i := range entries {
+		item := v.Index(i)
+		entries[i] = entry{values: values(item), id: item.FieldByName("Id").String()}
+	}
+	o.sort(entries, reflect.Swapper(items))
+	return o, entries, nil
+}
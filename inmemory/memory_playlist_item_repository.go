@@ -0,0 +1,41 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/core-go/video"
+)
+
+type MemoryPlaylistItemRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryPlaylistItemRepository(store *MemoryStore) *MemoryPlaylistItemRepository {
+	return &MemoryPlaylistItemRepository{store: store}
+}
+
+func (m *MemoryPlaylistItemRepository) GetPlaylistItems(ctx context.Context, playlistId string) ([]video.PlaylistItem, error) {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	return m.store.playlistItems(playlistId), nil
+}
+
+func (m *MemoryPlaylistItemRepository) SavePlaylistItems(ctx context.Context, items []video.PlaylistItem) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	for _, item := range items {
+		m.store.PlaylistItems[item.Id] = item
+	}
+	return len(items), m.store.persist()
+}
+
+// playlistItems is called with the lock held.
+func (s *MemoryStore) playlistItems(playlistId string) []video.PlaylistItem {
+	var items []video.PlaylistItem
+	for _, item := range s.PlaylistItems {
+		if item.PlaylistId == playlistId {
+			items = append(items, item)
+		}
+	}
+	return items
+}
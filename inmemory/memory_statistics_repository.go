@@ -0,0 +1,24 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/core-go/video"
+)
+
+type MemoryStatisticsRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryStatisticsRepository(store *MemoryStore) *MemoryStatisticsRepository {
+	return &MemoryStatisticsRepository{store: store}
+}
+
+func (m *MemoryStatisticsRepository) SaveStatistics(ctx context.Context, statistics []video.VideoStatistics) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	for _, s := range statistics {
+		m.store.Statistics[s.VideoId] = append(m.store.Statistics[s.VideoId], s)
+	}
+	return len(statistics), m.store.persist()
+}
@@ -0,0 +1,150 @@
+package inmemory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/core-go/video"
+)
+
+type MemoryStore struct {
+	mutex          sync.RWMutex
+	file           string
+	Channels       map[string]video.Channel
+	ChannelSyncs   map[string]video.ChannelSync
+	Playlists      map[string]video.Playlist
+	PlaylistVideos map[string][]string
+	Videos         map[string]video.Video
+	Categories     map[string]video.Categories
+	Statistics     map[string][]video.VideoStatistics
+	Subscriptions  map[string]video.Subscription
+	PlaylistItems  map[string]video.PlaylistItem
+}
+
+// snapshot keeps Channel.ChannelList beside the channels, as it is not serialized to JSON.
+type snapshot struct {
+	Channels       map[string]video.Channel           `json:"channels,omitempty"`
+	ChannelLists   map[string][]string                `json:"channelLists,omitempty"`
+	ChannelSyncs   map[string]video.ChannelSync       `json:"channelSyncs,omitempty"`
+	Playlists      map[string]video.Playlist          `json:"playlists,omitempty"`
+	PlaylistVideos map[string][]string                `json:"playlistVideos,omitempty"`
+	Videos         map[string]video.Video             `json:"videos,omitempty"`
+	Categories     map[string]video.Categories        `json:"categories,omitempty"`
+	Statistics     map[string][]video.VideoStatistics `json:"statistics,omitempty"`
+	Subscriptions  map[string]video.Subscription      `json:"subscriptions,omitempty"`
+	PlaylistItems  map[string]video.PlaylistItem      `json:"playlistItems,omitempty"`
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Channels:       make(map[string]video.Channel),
+		ChannelSyncs:   make(map[string]video.ChannelSync),
+		Playlists:      make(map[string]video.Playlist),
+		PlaylistVideos: make(map[string][]string),
+		Videos:         make(map[string]video.Video),
+		Categories:     make(map[string]video.Categories),
+		Statistics:     make(map[string][]video.VideoStatistics),
+		Subscriptions:  make(map[string]video.Subscription),
+		PlaylistItems:  make(map[string]video.PlaylistItem),
+	}
+}
+
+// LoadMemoryStore reads file if it exists, and writes it again after every change.
+func LoadMemoryStore(file string) (*MemoryStore, error) {
+	s := NewMemoryStore()
+	s.file = file
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	var snap snapshot
+	if er1 := json.Unmarshal(data, &snap); er1 != nil {
+		return nil, er1
+	}
+	for id, channel := range snap.Channels {
+		channel.ChannelList = snap.ChannelLists[id]
+		s.Channels[id] = channel
+	}
+	if snap.ChannelSyncs != nil {
+		s.ChannelSyncs = snap.ChannelSyncs
+	}
+	if snap.Playlists != nil {
+		s.Playlists = snap.Playlists
+	}
+	if snap.PlaylistVideos != nil {
+		s.PlaylistVideos = snap.PlaylistVideos
+	}
+	if snap.Videos != nil {
+		s.Videos = snap.Videos
+	}
+	if snap.Categories != nil {
+		s.Categories = snap.Categories
+	}
+	if snap.Statistics != nil {
+		s.Statistics = snap.Statistics
+	}
+	if snap.Subscriptions != nil {
+		s.Subscriptions = snap.Subscriptions
+	}
+	if snap.PlaylistItems != nil {
+		s.PlaylistItems = snap.PlaylistItems
+	}
+	return s, nil
+}
+
+func (s *MemoryStore) Snapshot(file string) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.writeSnapshot(file)
+}
+
+func (s *MemoryStore) persist() error {
+	if len(s.file) == 0 {
+		return nil
+	}
+	return s.writeSnapshot(s.file)
+}
+
+func (s *MemoryStore) writeSnapshot(file string) error {
+	snap := snapshot{
+		Channels:       s.Channels,
+		ChannelLists:   make(map[string][]string),
+		ChannelSyncs:   s.ChannelSyncs,
+		Playlists:      s.Playlists,
+		PlaylistVideos: s.PlaylistVideos,
+		Videos:         s.Videos,
+		Categories:     s.Categories,
+		Statistics:     s.Statistics,
+		Subscriptions:  s.Subscriptions,
+		PlaylistItems:  s.PlaylistItems,
+	}
+	for id, channel := range s.Channels {
+		if len(channel.ChannelList) > 0 {
+			snap.ChannelLists[id] = channel.ChannelList
+		}
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	tmp, er1 := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if er1 != nil {
+		return er1
+	}
+	if _, er2 := tmp.Write(data); er2 != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return er2
+	}
+	if er3 := tmp.Close(); er3 != nil {
+		os.Remove(tmp.Name())
+		return er3
+	}
+	return os.Rename(tmp.Name(), file)
+}
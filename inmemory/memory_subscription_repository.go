@@ -0,0 +1,29 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/core-go/video"
+)
+
+type MemorySubscriptionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySubscriptionRepository(store *MemoryStore) *MemorySubscriptionRepository {
+	return &MemorySubscriptionRepository{store: store}
+}
+
+func (m *MemorySubscriptionRepository) SaveSubscriptions(ctx context.Context, subscriberId string, subscriptions []video.Subscription) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	for id, s := range m.store.Subscriptions {
+		if s.SubscriberId == subscriberId {
+			delete(m.store.Subscriptions, id)
+		}
+	}
+	for _, s := range subscriptions {
+		m.store.Subscriptions[s.Id] = s
+	}
+	return len(subscriptions), m.store.persist()
+}
@@ -0,0 +1,95 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/core-go/video"
+)
+
+type MemoryVideoRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryVideoRepository(store *MemoryStore) *MemoryVideoRepository {
+	return &MemoryVideoRepository{store: store}
+}
+
+func (m *MemoryVideoRepository) GetChannelSync(ctx context.Context, channelId string) (*video.ChannelSync, error) {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	channelSync, ok := m.store.ChannelSyncs[channelId]
+	if !ok {
+		return nil, nil
+	}
+	return &channelSync, nil
+}
+
+func (m *MemoryVideoRepository) GetChannelSyncs(ctx context.Context) ([]video.ChannelSync, error) {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var res []video.ChannelSync
+	for _, channelSync := range m.store.ChannelSyncs {
+		res = append(res, channelSync)
+	}
+	return res, nil
+}
+
+func (m *MemoryVideoRepository) SaveChannel(ctx context.Context, channel video.Channel) (int64, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	m.store.Channels[channel.Id] = channel
+	return 1, m.store.persist()
+}
+
+func (m *MemoryVideoRepository) SavePlaylist(ctx context.Context, playlist video.Playlist) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	m.store.Playlists[playlist.Id] = playlist
+	return 1, m.store.persist()
+}
+
+func (m *MemoryVideoRepository) SavePlaylists(ctx context.Context, playlists []video.Playlist) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	for _, playlist := range playlists {
+		m.store.Playlists[playlist.Id] = playlist
+	}
+	return len(playlists), m.store.persist()
+}
+
+func (m *MemoryVideoRepository) SaveChannelSync(ctx context.Context, channel video.ChannelSync) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	m.store.ChannelSyncs[channel.Id] = channel
+	return 1, m.store.persist()
+}
+
+func (m *MemoryVideoRepository) SaveVideos(ctx context.Context, videos []video.Video) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	for _, v := range videos {
+		m.store.Videos[v.Id] = v
+	}
+	return len(videos), m.store.persist()
+}
+
+func (m *MemoryVideoRepository) SavePlaylistVideos(ctx context.Context, playlistId string, videos []string) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	ids := make([]string, len(videos))
+	copy(ids, videos)
+	m.store.PlaylistVideos[playlistId] = ids
+	return 1, m.store.persist()
+}
+
+func (m *MemoryVideoRepository) GetVideoIds(ctx context.Context, ids []string) ([]string, error) {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var res []string
+	for _, id := range ids {
+		if v, ok := m.store.Videos[id]; ok && !video.Tombstoned(v) {
+			res = append(res, id)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+)
+
+type MemoryTombstoneRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryTombstoneRepository(store *MemoryStore) *MemoryTombstoneRepository {
+	return &MemoryTombstoneRepository{store: store}
+}
+
+func (m *MemoryTombstoneRepository) TombstoneVideos(ctx context.Context, ids []string, status string, at time.Time) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	count := 0
+	for _, id := range ids {
+		v, ok := m.store.Videos[id]
+		if !ok || v.Status == status {
+			continue
+		}
+		v.Status = status
+		if v.TombstonedAt == nil {
+			t := at
+			v.TombstonedAt = &t
+		}
+		m.store.Videos[id] = v
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, m.store.persist()
+}
+
+func (m *MemoryTombstoneRepository) PurgeVideos(ctx context.Context, status string, before time.Time) (int, error) {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	count := 0
+	for id, v := range m.store.Videos {
+		if v.Status == status && v.TombstonedAt != nil && v.TombstonedAt.Before(before) {
+			delete(m.store.Videos, id)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, m.store.persist()
+}
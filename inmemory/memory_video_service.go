@@ -0,0 +1,657 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
+)
+
+type MemoryVideoService struct {
+	store        *MemoryStore
+	tubeCategory *category.CategorySyncClient
+}
+
+func NewMemoryVideoService(store *MemoryStore, options ...*category.CategorySyncClient) *MemoryVideoService {
+	var tubeCategory *category.CategorySyncClient
+	if len(options) > 0 {
+		tubeCategory = options[0]
+	}
+	return &MemoryVideoService{store: store, tubeCategory: tubeCategory}
+}
+
+func (m *MemoryVideoService) GetChannel(ctx context.Context, channelId string, fields []string) (*video.Channel, error) {
+	if err := checkFields(channelType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	channel, ok := m.store.Channels[channelId]
+	if !ok {
+		return nil, nil
+	}
+	if len(channel.ChannelList) > 0 {
+		channel.Channels = m.getChannels(channel.ChannelList, nil)
+	}
+	project(&channel, fields)
+	return &channel, nil
+}
+
+func (m *MemoryVideoService) GetChannels(ctx context.Context, ids []string, fields []string) (*[]video.Channel, error) {
+	if err := checkFields(channelType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	res := m.getChannels(ids, fields)
+	return &res, nil
+}
+
+func (m *MemoryVideoService) GetPlaylist(ctx context.Context, id string, fields []string) (*video.Playlist, error) {
+	if err := checkFields(playlistType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	playlist, ok := m.store.Playlists[id]
+	if !ok {
+		return nil, nil
+	}
+	project(&playlist, fields)
+	return &playlist, nil
+}
+
+func (m *MemoryVideoService) GetPlaylists(ctx context.Context, ids []string, fields []string) (*[]video.Playlist, error) {
+	if err := checkFields(playlistType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	res := make([]video.Playlist, 0)
+	for _, id := range ids {
+		if playlist, ok := m.store.Playlists[id]; ok {
+			project(&playlist, fields)
+			res = append(res, playlist)
+		}
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) GetVideo(ctx context.Context, id string, fields []string) (*video.Video, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	v, ok := m.store.Videos[id]
+	if !ok {
+		return nil, nil
+	}
+	project(&v, fields)
+	return &v, nil
+}
+
+func (m *MemoryVideoService) GetVideos(ctx context.Context, ids []string, fields []string) (*[]video.Video, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	res := m.getVideos(ids)
+	for i := range res {
+		project(&res[i], fields)
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) GetChannelPlaylists(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
+	return m.SearchPlaylists(ctx, video.PlaylistSM{ChannelId: channelId}, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) GetChannelVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	return m.SearchVideos(ctx, video.ItemSM{ChannelId: channelId, RegionCode: regionCode}, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	items := m.store.playlistItems(playlistId)
+	if len(items) == 0 {
+		ids, ok := m.store.PlaylistVideos[playlistId]
+		if !ok {
+			return nil, nil
+		}
+		items = video.NewPlaylistItems(playlistId, ids, nil)
+	}
+	res, err := video.PagePlaylist(video.PlaylistOrder(items), getLimit(max), nextPageToken, func(ids []string) ([]video.Video, error) {
+		var videos []video.Video
+		for _, v := range m.getVideos(ids) {
+			if video.Available(v, regionCode) && video.Listed(ctx, v) {
+				videos = append(videos, v)
+			}
+		}
+		return videos, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	return res, nil
+}
+
+func (m *MemoryVideoService) GetCategories(ctx context.Context, regionCode string) (*video.Categories, error) {
+	m.store.mutex.RLock()
+	categories, ok := m.store.Categories[regionCode]
+	m.store.mutex.RUnlock()
+	if ok {
+		return &categories, nil
+	}
+	if m.tubeCategory == nil {
+		return nil, nil
+	}
+	res, er1 := m.tubeCategory.GetCagetories(ctx, regionCode)
+	if er1 != nil {
+		return nil, er1
+	}
+	result := video.Categories{
+		Id:   regionCode,
+		Data: *res,
+	}
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+	m.store.Categories[regionCode] = result
+	if er2 := m.store.persist(); er2 != nil {
+		return nil, er2
+	}
+	return &result, nil
+}
+
+func (m *MemoryVideoService) SearchChannel(ctx context.Context, channelSM video.ChannelSM, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := checkFields(channelType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var channels []video.Channel
+	for _, channel := range m.store.Channels {
+		if matchChannel(channelSM, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	o, entries, err := sortItems(channels, video.ChannelSortable, channelSM.Sort, channelSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultChannel{List: channels[start:end], Total: end - start, Limit: limit}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	if next != nil {
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) SearchPlaylists(ctx context.Context, playlistSM video.PlaylistSM, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
+	if err := checkFields(playlistType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var playlists []video.Playlist
+	for _, playlist := range m.store.Playlists {
+		if matchPlaylist(playlistSM, playlist) {
+			playlists = append(playlists, playlist)
+		}
+	}
+	o, entries, err := sortItems(playlists, video.PlaylistSortable, playlistSM.Sort, playlistSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultPlaylist{List: playlists[start:end], Total: end - start, Limit: limit}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	if next != nil {
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) SearchVideos(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	videos := m.searchVideos(ctx, itemSM)
+	o, entries, err := sortItems(videos, video.VideoSortable, itemSM.Sort, itemSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	return pageVideos(videos, o, entries, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultSearch, error) {
+	kinds, err := video.SearchKinds(itemSM)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if findField(channelType, f) < 0 && findField(playlistType, f) < 0 && findField(videoType, f) < 0 {
+			return nil, fmt.Errorf("%w: %s", video.ErrInvalidField, f)
+		}
+	}
+	keys, err := video.ParseSort(itemSM.Sort, video.SearchSortable, itemSM.Q)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cursor.Decode(nextPageToken, video.FormatSort(keys))
+	if err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var o order
+	var results []video.SearchResult
+	var entries []entry
+	for _, kind := range kinds {
+		var items interface{}
+		switch kind {
+		case video.KindChannel:
+			channelSM := video.ToChannelSM(itemSM)
+			var channels []video.Channel
+			for _, channel := range m.store.Channels {
+				if matchChannel(channelSM, channel) {
+					channels = append(channels, channel)
+				}
+			}
+			items = channels
+		case video.KindPlaylist:
+			playlistSM := video.ToPlaylistSM(itemSM)
+			var playlists []video.Playlist
+			for _, playlist := range m.store.Playlists {
+				if matchPlaylist(playlistSM, playlist) {
+					playlists = append(playlists, playlist)
+				}
+			}
+			items = playlists
+		default:
+			items = m.searchVideos(ctx, itemSM)
+		}
+		v := reflect.ValueOf(items)
+		var values func(item reflect.Value) []interface{}
+		o, values, err = newOrder(v.Type().Elem(), keys, itemSM.Q)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < v.Len(); i++ {
+			item := reflect.New(v.Type().Elem())
+			item.Elem().Set(v.Index(i))
+			project(item.Interface(), fields)
+			results = append(results, searchResult(kind, item.Interface()))
+			entries = append(entries, entry{values: values(v.Index(i)), id: v.Index(i).FieldByName("Id").String()})
+		}
+	}
+	o.sort(entries, func(i, j int) { results[i], results[j] = results[j], results[i] })
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultSearch{List: results[start:end], Total: end - start, Limit: limit}
+	if next != nil {
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+// searchVideos needs the store locked.
+func (m *MemoryVideoService) searchVideos(ctx context.Context, itemSM video.ItemSM) []video.Video {
+	var related map[string]bool
+	if len(itemSM.RelatedToVideoId) > 0 {
+		related = make(map[string]bool)
+		if v, ok := m.store.Videos[itemSM.RelatedToVideoId]; ok {
+			for _, tag := range v.Tags {
+				related[tag] = true
+			}
+		}
+	}
+	var videos []video.Video
+	for _, v := range m.store.Videos {
+		if matchVideo(itemSM, v, related) && video.Listed(ctx, v) {
+			videos = append(videos, v)
+		}
+	}
+	return videos
+}
+
+func searchResult(kind string, item interface{}) video.SearchResult {
+	result := video.SearchResult{Kind: kind}
+	switch item := item.(type) {
+	case *video.Channel:
+		result.Channel = item
+	case *video.Playlist:
+		result.Playlist = item
+	case *video.Video:
+		result.Video = item
+	}
+	return result
+}
+
+// GetRelatedVideos keeps the time of the ranking in the page token, so every page ranks alike.
+func (m *MemoryVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	o := order{name: "related", types: []reflect.Type{float64Type}, desc: []bool{true}}
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if c != nil {
+		if c.Since == nil {
+			return nil, cursor.ErrInvalid
+		}
+		now = *c.Since
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	seed, ok := m.store.Videos[videoId]
+	if !ok {
+		return nil, nil
+	}
+	candidates := make([]video.Video, 0, len(m.store.Videos))
+	for _, v := range m.store.Videos {
+		if video.Listed(ctx, v) {
+			candidates = append(candidates, v)
+		}
+	}
+	ranked := video.RankRelated(seed, candidates, now, video.DefaultRelatedWeights)
+	entries := make([]entry, len(ranked))
+	for i, r := range ranked {
+		entries[i] = entry{values: []interface{}{r.Score}, id: r.Video.Id}
+	}
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultVideos{Total: end - start, Limit: limit}
+	for _, r := range ranked[start:end] {
+		v := r.Video
+		project(&v, fields)
+		res.List = append(res.List, v)
+	}
+	if next != nil {
+		next.Since = &now
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) GetPopularVideos(ctx context.Context, regionCode string, categoryId string, limit int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	return m.SearchVideos(ctx, video.ItemSM{RegionCode: regionCode, CategoryId: categoryId, Sort: "viewCount"}, limit, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) GetTrendingVideos(ctx context.Context, regionCode string, categoryId string, window time.Duration, limit int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	o := order{name: "trending", types: []reflect.Type{float64Type}, desc: []bool{true}}
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-window).UTC()
+	if c != nil {
+		if c.Since == nil {
+			return nil, cursor.ErrInvalid
+		}
+		since = *c.Since
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	itemSM := video.ItemSM{RegionCode: regionCode, CategoryId: categoryId}
+	var videos []video.Video
+	var entries []entry
+	for id, statistics := range m.store.Statistics {
+		v, ok := m.store.Videos[id]
+		if !ok || !matchVideo(itemSM, v, nil) || !video.Listed(ctx, v) {
+			continue
+		}
+		if r, ok := velocity(statistics, v.PublishedAt, since); ok {
+			videos = append(videos, v)
+			entries = append(entries, entry{values: []interface{}{r}, id: id})
+		}
+	}
+	o.sort(entries, func(i, j int) { videos[i], videos[j] = videos[j], videos[i] })
+	max := getLimit(limit)
+	start, end, next, err := o.page(entries, max, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultVideos{List: videos[start:end], Total: end - start, Limit: max}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	if next != nil {
+		next.Since = &since
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func (m *MemoryVideoService) GetChannelSubscriptions(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := checkFields(channelType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var channels []video.Channel
+	var entries []entry
+	for _, s := range m.store.Subscriptions {
+		if s.SubscriberId != channelId {
+			continue
+		}
+		channel, ok := m.store.Channels[s.ChannelId]
+		if !ok {
+			channel = video.SubscriptionChannel(s, fields)
+		}
+		channels = append(channels, channel)
+		entries = append(entries, entry{id: channel.Id})
+	}
+	return pageChannels(channels, order{name: "subscriptions"}, entries, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) GetChannelSubscribers(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := checkFields(channelType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	var channels []video.Channel
+	var entries []entry
+	for _, s := range m.store.Subscriptions {
+		if s.ChannelId != channelId {
+			continue
+		}
+		if channel, ok := m.store.Channels[s.SubscriberId]; ok {
+			channels = append(channels, channel)
+			entries = append(entries, entry{id: channel.Id})
+		}
+	}
+	return pageChannels(channels, order{name: "subscribers"}, entries, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) GetSubscriptionVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(videoType, fields); err != nil {
+		return nil, err
+	}
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+	subscribed := make(map[string]bool)
+	for _, s := range m.store.Subscriptions {
+		if s.SubscriberId == channelId {
+			subscribed[s.ChannelId] = true
+		}
+	}
+	videos := make([]video.Video, 0)
+	for _, v := range m.store.Videos {
+		if subscribed[v.ChannelId] && video.Available(v, regionCode) && video.Listed(ctx, v) {
+			videos = append(videos, v)
+		}
+	}
+	o, entries, err := sortItems(videos, video.VideoSortable, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return pageVideos(videos, o, entries, max, nextPageToken, fields)
+}
+
+func (m *MemoryVideoService) getChannels(ids []string, fields []string) []video.Channel {
+	res := make([]video.Channel, 0)
+	for _, id := range ids {
+		if channel, ok := m.store.Channels[id]; ok {
+			project(&channel, fields)
+			res = append(res, channel)
+		}
+	}
+	return res
+}
+
+func (m *MemoryVideoService) getVideos(ids []string) []video.Video {
+	res := make([]video.Video, 0)
+	for _, id := range ids {
+		if v, ok := m.store.Videos[id]; ok {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func pageVideos(videos []video.Video, o order, entries []entry, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultVideos{List: videos[start:end], Total: end - start, Limit: limit}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	if next != nil {
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+func pageChannels(channels []video.Channel, o order, entries []entry, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	c, err := cursor.Decode(nextPageToken, o.name)
+	if err != nil {
+		return nil, err
+	}
+	o.sort(entries, func(i, j int) { channels[i], channels[j] = channels[j], channels[i] })
+	limit := getLimit(max)
+	start, end, next, err := o.page(entries, limit, c)
+	if err != nil {
+		return nil, err
+	}
+	res := video.ListResultChannel{List: channels[start:end], Total: end - start, Limit: limit}
+	for i := range res.List {
+		project(&res.List[i], fields)
+	}
+	if next != nil {
+		token, err := cursor.Encode(*next)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
+}
+
+// velocity measures a video published inside the window from its publish time.
+func velocity(statistics []video.VideoStatistics, publishedAt *time.Time, since time.Time) (float64, bool) {
+	var first, last *video.VideoStatistics
+	for i := range statistics {
+		s := &statistics[i]
+		if s.Timestamp == nil || s.ViewCount == nil || s.Timestamp.Before(since) {
+			continue
+		}
+		if first == nil || s.Timestamp.Before(*first.Timestamp) {
+			first = s
+		}
+		if last == nil || s.Timestamp.After(*last.Timestamp) {
+			last = s
+		}
+	}
+	if last == nil {
+		return 0, false
+	}
+	from, views := *first.Timestamp, *first.ViewCount
+	if publishedAt != nil && !publishedAt.Before(since) && publishedAt.Before(from) {
+		from, views = *publishedAt, 0
+	}
+	hours := last.Timestamp.Sub(from).Hours()
+	if hours <= 0 {
+		return 0, false
+	}
+	return float64(*last.ViewCount-views) / hours, true
+}
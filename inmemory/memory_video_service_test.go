@@ -0,0 +1,40 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+	"github.com/core-go/video/videotest"
+)
+
+func init() {
+	cursor.SetKey([]byte("test key"))
+}
+
+func TestVideoService(t *testing.T) {
+	videotest.RunVideoServiceSuite(t, func(t *testing.T) (video.VideoService, video.SyncRepository) {
+		store := NewMemoryStore()
+		return NewMemoryVideoService(store), NewMemoryVideoRepository(store)
+	})
+}
+
+func TestSyncRepository(t *testing.T) {
+	videotest.RunSyncRepositorySuite(t, func(t *testing.T) video.SyncRepository {
+		return NewMemoryVideoRepository(NewMemoryStore())
+	})
+}
+
+func TestBackend(t *testing.T) {
+	videotest.RunBackendSuite(t, func(t *testing.T) videotest.Backend {
+		store := NewMemoryStore()
+		return videotest.Backend{
+			Service:       NewMemoryVideoService(store),
+			Repository:    NewMemoryVideoRepository(store),
+			Statistics:    NewMemoryStatisticsRepository(store),
+			Subscriptions: NewMemorySubscriptionRepository(store),
+			PlaylistItems: NewMemoryPlaylistItemRepository(store),
+			Tombstones:    NewMemoryTombstoneRepository(store),
+		}
+	})
+}
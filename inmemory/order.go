@@ -0,0 +1,212 @@
+package inmemory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	stringType  = reflect.TypeOf("")
+)
+
+// order sorts nil values last, ties by id.
+type order struct {
+	name  string
+	types []reflect.Type
+	desc  []bool
+}
+
+type entry struct {
+	values []interface{}
+	id     string
+}
+
+func newOrder(modelType reflect.Type, keys []video.SortKey, q string) (order, func(item reflect.Value) []interface{}, error) {
+	o := order{name: video.FormatSort(keys), types: make([]reflect.Type, len(keys)), desc: make([]bool, len(keys))}
+	indexes := make([]int, len(keys))
+	for i, key := range keys {
+		o.desc[i] = key.Desc
+		if key.Field == video.Relevance {
+			o.types[i] = int64Type
+			continue
+		}
+		indexes[i] = findField(modelType, key.Field)
+		if indexes[i] < 0 {
+			return order{}, nil, fmt.Errorf("%w: %s", video.ErrInvalidSort, key.Field)
+		}
+		o.types[i] = valueType(modelType.Field(indexes[i]).Type)
+	}
+	values := func(item reflect.Value) []interface{} {
+		res := make([]interface{}, len(keys))
+		for i, key := range keys {
+			switch key.Field {
+			case video.Relevance:
+				res[i] = video.Score(q, item.FieldByName("Title").String(), item.FieldByName("Description").String())
+			case "title":
+				res[i] = video.SortTitle(item.Field(indexes[i]).String())
+			default:
+				res[i] = value(item.Field(indexes[i]))
+			}
+		}
+		return res
+	}
+	return o, values, nil
+}
+
+func findField(modelType reflect.Type, name string) int {
+	for i := 0; i < modelType.NumField(); i++ {
+		if strings.Split(modelType.Field(i).Tag.Get("json"), ",")[0] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func valueType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return timeType
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int64Type
+	case reflect.Float32, reflect.Float64:
+		return float64Type
+	}
+	return stringType
+}
+
+func value(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func (o order) compare(a entry, b entry) int {
+	for i := range o.desc {
+		x, y := a.values[i], b.values[i]
+		if x == nil || y == nil {
+			if x == nil && y == nil {
+				continue
+			}
+			if x == nil {
+				return 1
+			}
+			return -1
+		}
+		c := compareValue(x, y)
+		if c != 0 {
+			if o.desc[i] {
+				return -c
+			}
+			return c
+		}
+	}
+	return strings.Compare(a.id, b.id)
+}
+
+func compareValue(x interface{}, y interface{}) int {
+	switch a := x.(type) {
+	case time.Time:
+		b := y.(time.Time)
+		if a.Before(b) {
+			return -1
+		} else if a.After(b) {
+			return 1
+		}
+	case int64:
+		b := y.(int64)
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	case float64:
+		b := y.(float64)
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	case string:
+		return strings.Compare(a, y.(string))
+	}
+	return 0
+}
+
+// sort calls swap for every exchange, so the items stay aligned with entries.
+func (o order) sort(entries []entry, swap func(i, j int)) {
+	sort.Sort(&sorter{order: o, entries: entries, swap: swap})
+}
+
+type sorter struct {
+	order   order
+	entries []entry
+	swap    func(i, j int)
+}
+
+func (s *sorter) Len() int           { return len(s.entries) }
+func (s *sorter) Less(i, j int) bool { return s.order.compare(s.entries[i], s.entries[j]) < 0 }
+func (s *sorter) Swap(i, j int) {
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+	s.swap(i, j)
+}
+
+func (o order) page(entries []entry, limit int, c *cursor.Cursor) (int, int, *cursor.Cursor, error) {
+	start := 0
+	if c != nil {
+		if len(c.Values) != len(o.types) {
+			return 0, 0, nil, cursor.ErrInvalid
+		}
+		last := entry{values: make([]interface{}, len(o.types)), id: c.Id}
+		for i, t := range o.types {
+			v, err := cursor.Parse(c.Values[i], t)
+			if err != nil {
+				return 0, 0, nil, err
+			}
+			last.values[i] = v
+		}
+		start = sort.Search(len(entries), func(i int) bool {
+			return o.compare(entries[i], last) > 0
+		})
+	}
+	end := start + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	if end == len(entries) || end == start {
+		return start, end, nil, nil
+	}
+	e := entries[end-1]
+	next := &cursor.Cursor{Sort: o.name, Values: make([]interface{}, len(e.values)), Id: e.id}
+	for i, v := range e.values {
+		if v != nil {
+			next.Values[i] = cursor.Value(reflect.ValueOf(v))
+		}
+	}
+	return start, end, next, nil
+}
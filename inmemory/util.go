@@ -0,0 +1,81 @@
+package inmemory
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+var (
+	channelType  = reflect.TypeOf(video.Channel{})
+	playlistType = reflect.TypeOf(video.Playlist{})
+	videoType    = reflect.TypeOf(video.Video{})
+)
+
+func inRange(t *time.Time, publishedAfter *time.Time, publishedBefore *time.Time) bool {
+	if publishedAfter == nil && publishedBefore == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	if publishedAfter != nil && t.Before(*publishedAfter) {
+		return false
+	}
+	if publishedBefore != nil && t.After(*publishedBefore) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func getLimit(max int) int {
+	if max <= 0 {
+		return 12
+	}
+	return max
+}
+
+// checkFields returns video.ErrInvalidField for a field that is not the json name of a field of modelType.
+func checkFields(modelType reflect.Type, fields []string) error {
+	for _, f := range fields {
+		if findField(modelType, f) < 0 {
+			return fmt.Errorf("%w: %s", video.ErrInvalidField, f)
+		}
+	}
+	return nil
+}
+
+// project clears every field of the struct model points to whose json name is not in fields. The id is always kept.
+func project(model interface{}, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	keep := map[string]bool{"id": true}
+	for _, f := range fields {
+		keep[strings.ToLower(f)] = true
+	}
+	v := reflect.Indirect(reflect.ValueOf(model))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" || len(name) == 0 || keep[strings.ToLower(name)] {
+			continue
+		}
+		f := v.Field(i)
+		if f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
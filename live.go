@@ -0,0 +1,28 @@
+package video
+
+import "time"
+
+// The live-stream states of a video, in LiveStatus.
+const (
+	LiveNone      = "none"
+	LiveUpcoming  = "upcoming"
+	LiveActive    = "live"
+	LiveCompleted = "completed"
+)
+
+// LiveStatus tells an ended broadcast, which is back to "none", by its actual times.
+func LiveStatus(liveBroadcastContent string, actualStartTime *time.Time, actualEndTime *time.Time) string {
+	if actualEndTime != nil {
+		return LiveCompleted
+	}
+	switch liveBroadcastContent {
+	case LiveActive:
+		return LiveActive
+	case LiveUpcoming:
+		return LiveUpcoming
+	}
+	if actualStartTime != nil {
+		return LiveCompleted
+	}
+	return LiveNone
+}
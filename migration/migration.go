@@ -0,0 +1,152 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Migration may run again before it is recorded, so Up should be idempotent.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context) error
+}
+
+type Version struct {
+	Version     int        `mapstructure:"version" json:"version,omitempty" gorm:"column:version;primary_key" bson:"_id" dynamodbav:"version,omitempty" firestore:"version,omitempty"`
+	Description string     `mapstructure:"description" json:"description,omitempty" gorm:"column:description" bson:"description,omitempty" dynamodbav:"description,omitempty" firestore:"description,omitempty"`
+	AppliedAt   *time.Time `mapstructure:"appliedAt" json:"appliedAt,omitempty" gorm:"column:appliedAt" bson:"appliedAt,omitempty" dynamodbav:"appliedAt,omitempty" firestore:"appliedAt,omitempty"`
+}
+
+type VersionRepository interface {
+	GetVersions(ctx context.Context) ([]Version, error)
+	SaveVersion(ctx context.Context, version Version) (int, error)
+}
+
+// Locker is a VersionRepository that keeps two processes from migrating its store at once.
+// Migrations run with the returned context, which is done once the lock is lost.
+type Locker interface {
+	Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error)
+}
+
+var ErrLockLost = errors.New("migration: lock lost")
+
+// LockExpiry frees the lock of a process that stopped, where the store cannot. Hold renews it while migrations run.
+var LockExpiry = 10 * time.Minute
+
+var LockInterval = time.Second
+
+func Poll(ctx context.Context, lock func(ctx context.Context) (bool, error)) error {
+	for {
+		locked, err := lock(ctx)
+		if locked || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(LockInterval):
+		}
+	}
+}
+
+// Hold renews a lock every third of LockExpiry until unlock is called. The context is cancelled when renew fails,
+// or returns false as another process took the lock; unlock then returns ErrLockLost or that error.
+func Hold(ctx context.Context, renew func(ctx context.Context) (bool, error), unlock func(ctx context.Context) error) (context.Context, func(ctx context.Context) error) {
+	held, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	var lost error
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(LockExpiry / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-held.Done():
+				return
+			case <-ticker.C:
+				renewed, err := renew(held)
+				if err == nil && !renewed {
+					err = ErrLockLost
+				}
+				if err != nil {
+					lost = err
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return held, func(ctx context.Context) error {
+		close(stop)
+		<-stopped
+		cancel()
+		if lost != nil {
+			return lost
+		}
+		return unlock(ctx)
+	}
+}
+
+// Migrate holds the lock of a Locker; else callers must not migrate the same store at once.
+func Migrate(ctx context.Context, repository VersionRepository, migrations []Migration) (int, error) {
+	locker, ok := repository.(Locker)
+	if !ok {
+		return migrate(ctx, repository, migrations)
+	}
+	held, unlock, er0 := locker.Lock(ctx)
+	if er0 != nil {
+		return 0, er0
+	}
+	count, er1 := migrate(held, repository, migrations)
+	lost := held.Err() != nil && ctx.Err() == nil
+	if er2 := unlock(context.Background()); er2 != nil && (er1 == nil || lost) {
+		return count, er2
+	}
+	return count, er1
+}
+
+func migrate(ctx context.Context, repository VersionRepository, migrations []Migration) (int, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return 0, fmt.Errorf("migration version %d is duplicated", sorted[i].Version)
+		}
+	}
+	versions, er0 := repository.GetVersions(ctx)
+	if er0 != nil {
+		return 0, er0
+	}
+	applied := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		applied[v.Version] = true
+	}
+	count := 0
+	for _, m := range sorted {
+		if applied[m.Version] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		if er1 := m.Up(ctx); er1 != nil {
+			return count, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, er1)
+		}
+		now := time.Now()
+		if _, er2 := repository.SaveVersion(ctx, Version{Version: m.Version, Description: m.Description, AppliedAt: &now}); er2 != nil {
+			return count, er2
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,145 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type memoryRepository struct {
+	versions []Version
+	calls    *[]string
+}
+
+func (r *memoryRepository) GetVersions(ctx context.Context) ([]Version, error) {
+	*r.calls = append(*r.calls, "get")
+	return r.versions, nil
+}
+
+func (r *memoryRepository) SaveVersion(ctx context.Context, version Version) (int, error) {
+	*r.calls = append(*r.calls, "save")
+	r.versions = append(r.versions, version)
+	return 1, nil
+}
+
+type lockedRepository struct {
+	memoryRepository
+	err error
+}
+
+func (r *lockedRepository) Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error) {
+	*r.calls = append(*r.calls, "lock")
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	return ctx, func(ctx context.Context) error {
+		*r.calls = append(*r.calls, "unlock")
+		return nil
+	}, nil
+}
+
+func TestMigrate(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	migrations := []Migration{{Version: 2, Up: up}, {Version: 1, Up: up}}
+	tests := []struct {
+		name    string
+		locked  bool
+		lockErr error
+		count   int
+		calls   []string
+	}{
+		{name: "unlocked", count: 2, calls: []string{"get", "save", "save"}},
+		{name: "locked", locked: true, count: 2, calls: []string{"lock", "get", "save", "save", "unlock"}},
+		{name: "lock failed", locked: true, lockErr: errors.New("timeout"), calls: []string{"lock"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var repository VersionRepository = &memoryRepository{calls: &calls}
+			if tc.locked {
+				repository = &lockedRepository{memoryRepository: memoryRepository{calls: &calls}, err: tc.lockErr}
+			}
+			count, err := Migrate(context.Background(), repository, migrations)
+			if err != tc.lockErr {
+				t.Fatalf("err = %v; want %v", err, tc.lockErr)
+			}
+			if count != tc.count || !reflect.DeepEqual(calls, tc.calls) {
+				t.Errorf("ran %d with %v; want %d with %v", count, calls, tc.count, tc.calls)
+			}
+		})
+	}
+}
+
+type expiringRepository struct {
+	memoryRepository
+	renewed bool
+}
+
+func (r *expiringRepository) Lock(ctx context.Context) (context.Context, func(ctx context.Context) error, error) {
+	*r.calls = append(*r.calls, "lock")
+	held, unlock := Hold(ctx, func(ctx context.Context) (bool, error) {
+		return r.renewed, nil
+	}, func(ctx context.Context) error {
+		*r.calls = append(*r.calls, "unlock")
+		return nil
+	})
+	return held, unlock, nil
+}
+
+func TestHold(t *testing.T) {
+	expiry := LockExpiry
+	LockExpiry = 3 * time.Millisecond
+	defer func() { LockExpiry = expiry }()
+	tests := []struct {
+		name    string
+		renewed bool
+		err     error
+		count   int
+		calls   []string
+	}{
+		{name: "renewed", renewed: true, count: 1, calls: []string{"lock", "get", "save", "unlock"}},
+		{name: "lost", err: ErrLockLost, calls: []string{"lock", "get"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			repository := &expiringRepository{memoryRepository: memoryRepository{calls: &calls}, renewed: tc.renewed}
+			up := func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(20 * time.Millisecond):
+					return nil
+				}
+			}
+			count, err := Migrate(context.Background(), repository, []Migration{{Version: 1, Up: up}})
+			if err != tc.err {
+				t.Fatalf("err = %v; want %v", err, tc.err)
+			}
+			if count != tc.count || !reflect.DeepEqual(calls, tc.calls) {
+				t.Errorf("ran %d with %v; want %d with %v", count, calls, tc.count, tc.calls)
+			}
+		})
+	}
+}
+
+func TestPoll(t *testing.T) {
+	interval := LockInterval
+	LockInterval = time.Millisecond
+	defer func() { LockInterval = interval }()
+	tries := 0
+	err := Poll(context.Background(), func(ctx context.Context) (bool, error) {
+		tries++
+		return tries == 3, nil
+	})
+	if err != nil || tries != 3 {
+		t.Errorf("tries = %d, err = %v; want 3 tries", tries, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Poll(ctx, func(ctx context.Context) (bool, error) { return false, nil }); err != context.Canceled {
+		t.Errorf("err = %v; want %v", err, context.Canceled)
+	}
+}
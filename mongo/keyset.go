@@ -0,0 +1,295 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+var (
+	stringType = reflect.TypeOf("")
+	int64Type  = reflect.TypeOf(int64(0))
+)
+
+// keyset pages after the sort values of the last document instead of a skip. Nulls sort last, ties by _id.
+type keyset struct {
+	name      string
+	keys      []video.SortKey
+	fields    []string
+	types     []reflect.Type
+	q         string
+	modelType reflect.Type
+}
+
+func newKeyset(modelType reflect.Type, keys []video.SortKey, q string) (keyset, error) {
+	k := keyset{name: video.FormatSort(keys), keys: keys, q: q, modelType: modelType}
+	for _, key := range keys {
+		switch key.Field {
+		case video.Relevance:
+			k.fields = append(k.fields, "_relevance")
+			k.types = append(k.types, int64Type)
+		case "title":
+			k.fields = append(k.fields, "_title")
+			k.types = append(k.types, stringType)
+		default:
+			i := findField(modelType, key.Field)
+			if i < 0 {
+				return keyset{}, fmt.Errorf("%w: %s", video.ErrInvalidSort, key.Field)
+			}
+			k.fields = append(k.fields, bsonName(modelType.Field(i)))
+			k.types = append(k.types, modelType.Field(i).Type)
+		}
+	}
+	return k, nil
+}
+
+func sortKeyset(modelType reflect.Type, sortable video.Sortable, sort string, q string) (keyset, error) {
+	keys, err := video.ParseSort(sort, sortable, q)
+	if err != nil {
+		return keyset{}, err
+	}
+	return newKeyset(modelType, keys, q)
+}
+
+func findField(modelType reflect.Type, name string) int {
+	for i := 0; i < modelType.NumField(); i++ {
+		if strings.Split(modelType.Field(i).Tag.Get("json"), ",")[0] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func bsonName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("bson"), ",")[0]
+}
+
+func project(modelType reflect.Type, fields []string, extra ...string) (bson.M, error) {
+	names := make([]string, 0, len(fields)+len(extra))
+	for _, field := range fields {
+		i := findField(modelType, field)
+		if i < 0 || len(bsonName(modelType.Field(i))) == 0 || bsonName(modelType.Field(i)) == "-" {
+			return nil, fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
+		names = append(names, bsonName(modelType.Field(i)))
+	}
+	return sel(append(names, extra...)...), nil
+}
+
+func (k keyset) computed() bson.D {
+	computed := bson.D{}
+	for i, key := range k.keys {
+		switch key.Field {
+		case video.Relevance:
+			computed = append(computed, bson.E{Key: "_relevance", Value: bson.M{"$add": bson.A{bson.M{"$multiply": bson.A{k.count("title"), 2}}, k.count("description")}}})
+		case "title":
+			computed = append(computed, bson.E{Key: "_title", Value: bson.M{"$toLower": bson.M{"$ifNull": bson.A{"$title", ""}}}})
+		default:
+			if !key.Desc {
+				computed = append(computed, bson.E{Key: "_null" + fmt.Sprint(i), Value: bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$" + k.fields[i], nil}}, nil}}})
+			}
+		}
+	}
+	return computed
+}
+
+func (k keyset) count(field string) bson.M {
+	find := bson.M{"input": bson.M{"$ifNull": bson.A{"$" + field, ""}}, "regex": regexp.QuoteMeta(k.q), "options": "i"}
+	return bson.M{"$size": bson.M{"$regexFindAll": find}}
+}
+
+// sort sorts on a null flag first, since MongoDB puts nulls first in an ascending sort.
+func (k keyset) sort() bson.D {
+	sort := bson.D{}
+	for i, key := range k.keys {
+		if key.Desc {
+			sort = append(sort, bson.E{Key: k.fields[i], Value: -1})
+			continue
+		}
+		if key.Field != video.Relevance && key.Field != "title" {
+			sort = append(sort, bson.E{Key: "_null" + fmt.Sprint(i), Value: 1})
+		}
+		sort = append(sort, bson.E{Key: k.fields[i], Value: 1})
+	}
+	return append(sort, bson.E{Key: "_id", Value: 1})
+}
+
+func (k keyset) after(c *cursor.Cursor) (bson.M, error) {
+	if len(c.Values) != len(k.fields) {
+		return nil, cursor.ErrInvalid
+	}
+	equal := bson.M{}
+	var or bson.A
+	for i, field := range k.fields {
+		v, err := cursor.Parse(c.Values[i], k.types[i])
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			equal[field] = nil
+			continue
+		}
+		compare := "$gt"
+		if k.keys[i].Desc {
+			compare = "$lt"
+		}
+		after := bson.M{"$or": bson.A{bson.M{field: bson.M{compare: v}}, bson.M{field: nil}}}
+		or = append(or, and(equal, after))
+		equal[field] = v
+	}
+	or = append(or, and(equal, bson.M{"_id": bson.M{"$gt": c.Id}}))
+	return bson.M{"$or": or}, nil
+}
+
+func and(equal bson.M, filter bson.M) bson.M {
+	res := bson.M{}
+	for key, v := range equal {
+		res[key] = v
+	}
+	conditions := bson.A{res, filter}
+	return bson.M{"$and": conditions}
+}
+
+func (k keyset) find(ctx context.Context, collection *mongo.Collection, query bson.D, c *cursor.Cursor, limit int, fields []string, results interface{}) (string, error) {
+	rows, err := k.read(ctx, collection, query, c, limit+1, fields, results)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) <= limit {
+		return "", nil
+	}
+	list := reflect.ValueOf(results).Elem()
+	list.Set(list.Slice(0, limit))
+	return k.token(rows[limit-1])
+}
+
+type row struct {
+	values []interface{}
+	id     string
+}
+
+func (k keyset) read(ctx context.Context, collection *mongo.Collection, query bson.D, c *cursor.Cursor, limit int, fields []string, results interface{}) ([]row, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: query}}}
+	if computed := k.computed(); len(computed) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: computed}})
+	}
+	if c != nil {
+		after, err := k.after(c)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: after}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: k.sort()}}, bson.D{{Key: "$limit", Value: int64(limit)}})
+	if len(fields) > 0 {
+		projection, er0 := project(k.modelType, fields, append([]string{"_id"}, k.fields...)...)
+		if er0 != nil {
+			return nil, er0
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+	res, er1 := collection.Aggregate(ctx, pipeline)
+	if er1 != nil {
+		return nil, er1
+	}
+	defer res.Close(ctx)
+	list := reflect.ValueOf(results).Elem()
+	var rows []row
+	for res.Next(ctx) {
+		item := reflect.New(list.Type().Elem())
+		if er2 := res.Decode(item.Interface()); er2 != nil {
+			return nil, er2
+		}
+		list.Set(reflect.Append(list, item.Elem()))
+		rows = append(rows, k.row(res.Current))
+	}
+	if er3 := res.Err(); er3 != nil {
+		return nil, er3
+	}
+	return rows, nil
+}
+
+func (k keyset) row(document bson.Raw) row {
+	r := row{values: make([]interface{}, len(k.fields))}
+	r.id, _ = document.Lookup("_id").StringValueOK()
+	for i, field := range k.fields {
+		r.values[i] = rawValue(document.Lookup(field))
+	}
+	return r
+}
+
+func (k keyset) token(r row) (string, error) {
+	return cursor.Encode(cursor.Cursor{Sort: k.name, Values: r.values, Id: r.id})
+}
+
+func (k keyset) less(a row, b row) bool {
+	for i, key := range k.keys {
+		x, _ := cursor.Parse(a.values[i], k.types[i])
+		y, _ := cursor.Parse(b.values[i], k.types[i])
+		if x == nil || y == nil {
+			if (x == nil) != (y == nil) {
+				return y == nil
+			}
+			continue
+		}
+		if c := compare(x, y); c != 0 {
+			return (c < 0) != key.Desc
+		}
+	}
+	return a.id < b.id
+}
+
+func compare(a interface{}, b interface{}) int {
+	switch x := a.(type) {
+	case time.Time:
+		y := b.(time.Time)
+		if x.Before(y) {
+			return -1
+		} else if x.After(y) {
+			return 1
+		}
+	case int64:
+		y := b.(int64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	}
+	return 0
+}
+
+func rawValue(v bson.RawValue) interface{} {
+	switch v.Type {
+	case bsontype.DateTime:
+		return cursor.Value(reflect.ValueOf(v.Time()))
+	case bsontype.Int32:
+		return cursor.Value(reflect.ValueOf(int64(v.Int32())))
+	case bsontype.Int64:
+		return cursor.Value(reflect.ValueOf(v.Int64()))
+	case bsontype.Double:
+		return cursor.Value(reflect.ValueOf(v.Double()))
+	case bsontype.String:
+		return v.StringValue()
+	}
+	return nil
+}
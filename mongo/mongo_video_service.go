@@ -2,10 +2,12 @@ package mongo
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strconv"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,6 +16,15 @@ import (
 
 	"github.com/core-go/video"
 	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
+)
+
+var (
+	channelType  = reflect.TypeOf(video.Channel{})
+	playlistType = reflect.TypeOf(video.Playlist{})
+	videoType    = reflect.TypeOf(video.Video{})
+
+	subscriptionType = reflect.TypeOf(video.Subscription{})
 )
 
 type MongoVideoService struct {
@@ -23,10 +34,25 @@ type MongoVideoService struct {
 	PlaylistVideoCollection *mongo.Collection
 	VideoCollection         *mongo.Collection
 	CategoryCollection      *mongo.Collection
+	StatisticsCollection    *mongo.Collection
+	SubscriptionCollection  *mongo.Collection
+	PlaylistItemCollection  *mongo.Collection
 	TubeCategory            category.CategorySyncClient
 }
 
-func NewMongoVideoService(db *mongo.Database, channelCollectionName string, channelSyncCollectionName string, playlistCollectionName string, playlistVideoCollectionName string, videoCollectionName string, categoryCollection string, TubeCategory category.CategorySyncClient) *MongoVideoService {
+func NewMongoVideoService(db *mongo.Database, channelCollectionName string, channelSyncCollectionName string, playlistCollectionName string, playlistVideoCollectionName string, videoCollectionName string, categoryCollection string, TubeCategory category.CategorySyncClient, options ...string) *MongoVideoService {
+	statisticsCollection := "videoStatistics"
+	if len(options) > 0 && len(options[0]) > 0 {
+		statisticsCollection = options[0]
+	}
+	subscriptionCollection := "subscription"
+	if len(options) > 1 && len(options[1]) > 0 {
+		subscriptionCollection = options[1]
+	}
+	playlistItemCollection := "playlistItem"
+	if len(options) > 2 && len(options[2]) > 0 {
+		playlistItemCollection = options[2]
+	}
 	return &MongoVideoService{
 		ChannelCollection:       db.Collection(channelCollectionName),
 		ChannelSyncCollection:   db.Collection(channelSyncCollectionName),
@@ -34,6 +60,9 @@ func NewMongoVideoService(db *mongo.Database, channelCollectionName string, chan
 		PlaylistVideoCollection: db.Collection(playlistVideoCollectionName),
 		VideoCollection:         db.Collection(videoCollectionName),
 		CategoryCollection:      db.Collection(categoryCollection),
+		StatisticsCollection:    db.Collection(statisticsCollection),
+		SubscriptionCollection:  db.Collection(subscriptionCollection),
+		PlaylistItemCollection:  db.Collection(playlistItemCollection),
 		TubeCategory:            TubeCategory,
 	}
 }
@@ -42,7 +71,11 @@ func (m *MongoVideoService) GetChannel(ctx context.Context, channelId string, fi
 	query := bson.M{"_id": channelId}
 	optionsFind := options.FindOne()
 	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+		projection, err := project(channelType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFind.SetProjection(projection)
 	}
 	result := m.ChannelCollection.FindOne(ctx, query, optionsFind)
 	if result.Err() != nil {
@@ -57,7 +90,7 @@ func (m *MongoVideoService) GetChannel(ctx context.Context, channelId string, fi
 		return nil, er1
 	}
 	if len(res.ChannelList) > 0 {
-		channels, err := m.GetChannels(ctx, res.ChannelList, []string{});
+		channels, err := m.GetChannels(ctx, res.ChannelList, []string{})
 		if err != nil {
 			return nil, err
 		}
@@ -69,8 +102,12 @@ func (m *MongoVideoService) GetChannel(ctx context.Context, channelId string, fi
 func (m *MongoVideoService) GetChannels(ctx context.Context, ids []string, fields []string) (*[]video.Channel, error) {
 	query := bson.M{"_id": bson.M{"$in": ids}}
 	optionsFind := options.Find()
-	if len(ids) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	if len(fields) > 0 {
+		projection, err := project(channelType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFind.SetProjection(projection)
 	}
 	result, er0 := m.ChannelCollection.Find(ctx, query, optionsFind)
 	if er0 != nil {
@@ -95,7 +132,11 @@ func (m *MongoVideoService) GetPlaylist(ctx context.Context, id string, fields [
 	query := bson.M{"_id": id}
 	optionsFindOne := options.FindOne()
 	if len(fields) > 0 {
-		optionsFindOne.SetProjection(sel(fields...))
+		projection, err := project(playlistType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFindOne.SetProjection(projection)
 	}
 	res := m.PlaylistCollection.FindOne(ctx, query, optionsFindOne)
 	if res.Err() != nil {
@@ -116,7 +157,11 @@ func (m *MongoVideoService) GetPlaylists(ctx context.Context, ids []string, fiel
 	query := bson.M{"_id": bson.M{"$in": ids}}
 	optionsFind := options.Find()
 	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+		projection, err := project(playlistType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFind.SetProjection(projection)
 	}
 	res, er0 := m.PlaylistCollection.Find(ctx, query, optionsFind)
 	if er0 != nil {
@@ -141,7 +186,11 @@ func (m *MongoVideoService) GetVideo(ctx context.Context, id string, fields []st
 	query := bson.M{"_id": id}
 	optionsFindOne := options.FindOne()
 	if len(fields) > 0 {
-		optionsFindOne.SetProjection(sel(fields...))
+		projection, err := project(videoType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFindOne.SetProjection(projection)
 	}
 	res := m.VideoCollection.FindOne(ctx, query, optionsFindOne)
 	if res.Err() != nil {
@@ -162,7 +211,11 @@ func (m *MongoVideoService) GetVideos(ctx context.Context, ids []string, fields
 	query := bson.M{"_id": bson.M{"$in": ids}}
 	optionsFind := options.Find()
 	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+		projection, err := project(videoType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFind.SetProjection(projection)
 	}
 	res, err := m.VideoCollection.Find(ctx, query, optionsFind)
 	if err != nil {
@@ -184,87 +237,55 @@ func (m *MongoVideoService) GetVideos(ctx context.Context, ids []string, fields
 }
 
 func (m *MongoVideoService) GetChannelPlaylists(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
-	limit := getLimit(max)
-	query := bson.M{"channelId": channelId, "count": bson.M{"$gt": 0}}
-	skip, er0 := getSkip(nextPageToken)
-	if er0 != nil {
-		return nil, er0
-	}
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
-	}
-	optionsFind.SetSort(bson.M{"publishedAt": 1})
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
-	res, er1 := m.PlaylistCollection.Find(ctx, query, optionsFind)
-	if er1 != nil {
-		if strings.Contains(er1.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
-		return nil, er1
-	}
-	var result video.ListResultPlaylist
-	for res.Next(ctx) {
-		var playlist video.Playlist
-		if er2 := res.Decode(&playlist); er2 != nil {
-			if strings.Contains(er2.Error(), "cannot decode invalid into") {
-				break
-			} else {
-				return nil, er2
-			}
-		}
-		result.List = append(result.List, playlist)
-	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
-	}
-	return &result, nil
+	return m.SearchPlaylists(ctx, video.PlaylistSM{ChannelId: channelId}, max, nextPageToken, fields)
 }
 
-func (m *MongoVideoService) GetChannelVideos(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	query := bson.M{"channelId": channelId}
-	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
-	if er0 != nil {
-		return nil, er0
-	}
+func (m *MongoVideoService) GetChannelVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	return m.SearchVideos(ctx, video.ItemSM{ChannelId: channelId, RegionCode: regionCode}, max, nextPageToken, fields)
+}
+
+func (m *MongoVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
 	optionsFind := options.Find()
 	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
-	}
-	optionsFind.SetSort(bson.M{"publishedAt": 1})
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
-	res, err := m.VideoCollection.Find(ctx, query, optionsFind)
-	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
-		return nil, err
-	}
-	var result video.ListResultVideos
-	for res.Next(ctx) {
-		var video video.Video
-		if er1 := res.Decode(&video); er1 != nil {
+		projection, err := project(videoType, fields)
+		if err != nil {
 			return nil, err
 		}
-		result.List = append(result.List, video)
+		optionsFind.SetProjection(projection)
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	items, er1 := m.getPlaylistItems(ctx, playlistId)
+	if er1 != nil || items == nil {
+		return nil, er1
 	}
-	return &result, nil
+	return video.PagePlaylist(video.PlaylistOrder(items), getLimit(max), nextPageToken, func(ids []string) ([]video.Video, error) {
+		query := bson.D{{"_id", bson.M{"$in": ids}}}
+		if regionCode != "" {
+			query = append(query, bson.E{"$and", bson.A{available("", regionCode)}})
+		}
+		if !video.IncludeUnavailable(ctx) {
+			query = append(query, listed(""))
+		}
+		cur, er2 := m.VideoCollection.Find(ctx, query, optionsFind)
+		if er2 != nil {
+			return nil, er2
+		}
+		var videos []video.Video
+		er3 := cur.All(ctx, &videos)
+		return videos, er3
+	})
 }
 
-func (m *MongoVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
-	if er0 != nil {
-		return nil, er0
+// getPlaylistItems falls back to the video ids of a playlist synced before items were stored.
+func (m *MongoVideoService) getPlaylistItems(ctx context.Context, playlistId string) ([]video.PlaylistItem, error) {
+	cur, er1 := m.PlaylistItemCollection.Find(ctx, bson.M{"playlistId": playlistId, "removedAt": nil})
+	if er1 != nil {
+		return nil, er1
 	}
-	query := bson.M{"_id": playlistId}
-	playlist := m.PlaylistVideoCollection.FindOne(ctx, query)
+	var items []video.PlaylistItem
+	if er2 := cur.All(ctx, &items); er2 != nil || len(items) > 0 {
+		return items, er2
+	}
+	playlist := m.PlaylistVideoCollection.FindOne(ctx, bson.M{"_id": playlistId})
 	if playlist.Err() != nil {
 		if strings.Contains(playlist.Err().Error(), "mongo: no documents in result") {
 			return nil, nil
@@ -272,36 +293,10 @@ func (m *MongoVideoService) GetPlaylistVideos(ctx context.Context, playlistId st
 		return nil, playlist.Err()
 	}
 	var playlistVideo video.PlaylistVideoIdVideos
-	er1 := playlist.Decode(&playlistVideo)
-	if er1 != nil {
-		return nil, er1
-	}
-	queryVideos := bson.M{"_id": bson.M{"$in": playlistVideo.Videos}}
-	optionsVideos := options.Find()
-	if len(fields) > 0 {
-		optionsVideos = optionsVideos.SetProjection(sel(fields...))
-	}
-	optionsVideos.SetSkip(int64(*skip))
-	optionsVideos.SetLimit(int64(limit))
-	res, er2 := m.VideoCollection.Find(ctx, queryVideos, optionsVideos)
-	if er2 != nil {
-		if strings.Contains(er2.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
-		return nil, er2
-	}
-	var result video.ListResultVideos
-	for res.Next(ctx) {
-		var video video.Video
-		if er3 := res.Decode(&video); er3 != nil {
-			return nil, er3
-		}
-		result.List = append(result.List, video)
+	if er3 := playlist.Decode(&playlistVideo); er3 != nil {
+		return nil, er3
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
-	}
-	return &result, nil
+	return video.NewPlaylistItems(playlistId, playlistVideo.Videos, nil), nil
 }
 
 func (m *MongoVideoService) GetCategories(ctx context.Context, regionCode string) (*video.Categories, error) {
@@ -310,7 +305,7 @@ func (m *MongoVideoService) GetCategories(ctx context.Context, regionCode string
 	var category video.Categories
 	if res.Err() != nil {
 		if strings.Contains(res.Err().Error(), "mongo: no documents in result") {
-			res, er1 := m.TubeCategory.GetCagetories(regionCode)
+			res, er1 := m.TubeCategory.GetCagetories(ctx, regionCode)
 			if er1 != nil {
 				return nil, er1
 			}
@@ -334,236 +329,556 @@ func (m *MongoVideoService) GetCategories(ctx context.Context, regionCode string
 
 func (m *MongoVideoService) SearchChannel(ctx context.Context, channelSM video.ChannelSM, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	k, er0 := sortKeyset(channelType, video.ChannelSortable, channelSM.Sort, channelSM.Q)
 	if er0 != nil {
 		return nil, er0
 	}
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
+	}
 	query := buildQueryChannelSearch(channelSM)
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	result := video.ListResultChannel{}
+	next, er2 := k.find(ctx, m.ChannelCollection, query, c, limit, fields, &result.List)
+	if er2 != nil {
+		return nil, er2
 	}
-	if channelSM.Sort != "" {
-		optionsFind.SetSort(bson.M{fmt.Sprintf(`%s`, channelSM.Sort): -1})
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
+}
+
+func (m *MongoVideoService) SearchPlaylists(ctx context.Context, playlistSM video.PlaylistSM, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
+	limit := getLimit(max)
+	k, er0 := sortKeyset(playlistType, video.PlaylistSortable, playlistSM.Sort, playlistSM.Q)
+	if er0 != nil {
+		return nil, er0
 	}
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
-	res, err := m.ChannelCollection.Find(ctx, query, optionsFind)
-	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
-		return nil, err
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
 	}
-	result := video.ListResultChannel{}
-	for res.Next(ctx) {
-		var channel video.Channel
-		if er1 := res.Decode(&channel); er1 != nil {
-			return nil, er1
+	query := buildQueryPlaylistSearch(playlistSM)
+	result := video.ListResultPlaylist{}
+	next, er2 := k.find(ctx, m.PlaylistCollection, query, c, limit, fields, &result.List)
+	if er2 != nil {
+		return nil, er2
+	}
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
+}
+
+func (m *MongoVideoService) SearchVideos(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	limit := getLimit(max)
+	k, er0 := sortKeyset(videoType, video.VideoSortable, itemSM.Sort, itemSM.Q)
+	if er0 != nil {
+		return nil, er0
+	}
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
+	}
+	result := video.ListResultVideos{}
+	var related []string
+	if itemSM.RelatedToVideoId != "" {
+		v, er2 := m.GetVideo(ctx, itemSM.RelatedToVideoId, []string{"tags"})
+		if er2 != nil {
+			return nil, er2
 		}
-		result.List = append(result.List, channel)
+		if v == nil || len(v.Tags) == 0 {
+			result.Limit = limit
+			return &result, nil
+		}
+		related = v.Tags
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	query := buildQueryVideoSearch(itemSM, related, video.IncludeUnavailable(ctx))
+	next, er3 := k.find(ctx, m.VideoCollection, query, c, limit, fields, &result.List)
+	if er3 != nil {
+		return nil, er3
 	}
-	return &result, err
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
 }
 
-func (m *MongoVideoService) SearchPlaylists(ctx context.Context, playlistSM video.PlaylistSM, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
+func (m *MongoVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultSearch, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	kinds, er0 := video.SearchKinds(itemSM)
 	if er0 != nil {
 		return nil, er0
 	}
-	query := buildQueryPlaylistSearch(playlistSM)
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	keys, er1 := video.ParseSort(itemSM.Sort, video.SearchSortable, itemSM.Q)
+	if er1 != nil {
+		return nil, er1
 	}
-	if playlistSM.Sort != "" {
-		optionsFind.SetSort(bson.M{fmt.Sprintf(`%s`, playlistSM.Sort): -1})
+	for _, field := range fields {
+		if findField(channelType, field) < 0 && findField(playlistType, field) < 0 && findField(videoType, field) < 0 {
+			return nil, fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
 	}
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
-	res, err := m.PlaylistCollection.Find(ctx, query, optionsFind)
-	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
+	c, er2 := cursor.Decode(nextPageToken, video.FormatSort(keys))
+	if er2 != nil {
+		return nil, er2
+	}
+	var k keyset
+	var results []video.SearchResult
+	var rows []row
+	for _, kind := range kinds {
+		var modelType reflect.Type
+		var collection *mongo.Collection
+		var query bson.D
+		switch kind {
+		case video.KindChannel:
+			modelType, collection, query = channelType, m.ChannelCollection, buildQueryChannelSearch(video.ToChannelSM(itemSM))
+		case video.KindPlaylist:
+			modelType, collection, query = playlistType, m.PlaylistCollection, buildQueryPlaylistSearch(video.ToPlaylistSM(itemSM))
+		default:
+			var related []string
+			if itemSM.RelatedToVideoId != "" {
+				v, er3 := m.GetVideo(ctx, itemSM.RelatedToVideoId, []string{"tags"})
+				if er3 != nil {
+					return nil, er3
+				}
+				if v == nil || len(v.Tags) == 0 {
+					continue
+				}
+				related = v.Tags
+			}
+			modelType, collection, query = videoType, m.VideoCollection, buildQueryVideoSearch(itemSM, related, video.IncludeUnavailable(ctx))
 		}
-		return nil, err
+		var er4 error
+		k, er4 = newKeyset(modelType, keys, itemSM.Q)
+		if er4 != nil {
+			return nil, er4
+		}
+		list := reflect.New(reflect.SliceOf(modelType))
+		r, er5 := k.read(ctx, collection, query, c, limit+1, searchFields(modelType, fields), list.Interface())
+		if er5 != nil {
+			return nil, er5
+		}
+		for i := 0; i < list.Elem().Len(); i++ {
+			results = append(results, searchResult(kind, list.Elem().Index(i).Addr().Interface()))
+		}
+		rows = append(rows, r...)
+	}
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return k.less(rows[order[i]], rows[order[j]])
+	})
+	res := video.ListResultSearch{Limit: limit}
+	if len(order) > limit {
+		order = order[:limit]
+		token, err := k.token(rows[order[limit-1]])
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
 	}
-	result := video.ListResultPlaylist{}
-	for res.Next(ctx) {
-		var playlist video.Playlist
-		if er1 := res.Decode(&playlist); er1 != nil {
-			return nil, er1
+	res.List = make([]video.SearchResult, len(order))
+	for i, o := range order {
+		res.List[i] = results[o]
+	}
+	res.Total = len(res.List)
+	return &res, nil
+}
+
+func searchFields(modelType reflect.Type, fields []string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	res := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if findField(modelType, field) >= 0 {
+			res = append(res, field)
 		}
-		result.List = append(result.List, playlist)
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	if len(res) == 0 {
+		res = append(res, "id")
 	}
-	return &result, err
+	return res
 }
 
-func (m *MongoVideoService) SearchVideos(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+func searchResult(kind string, item interface{}) video.SearchResult {
+	result := video.SearchResult{Kind: kind}
+	switch item := item.(type) {
+	case *video.Channel:
+		result.Channel = item
+	case *video.Playlist:
+		result.Playlist = item
+	case *video.Video:
+		result.Video = item
+	}
+	return result
+}
+
+// GetRelatedVideos keeps the time of the ranking in the page token, so every page ranks alike.
+func (m *MongoVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	if len(fields) > 0 {
+		if _, err := project(videoType, fields); err != nil {
+			return nil, err
+		}
+	}
+	c, er0 := cursor.Decode(nextPageToken, "related")
 	if er0 != nil {
 		return nil, er0
 	}
-	query := buildQueryVideoSearch(itemSM)
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	now := time.Now().UTC()
+	var lastScore float64
+	if c != nil {
+		v, err := cursor.Parse(firstValue(c.Values), reflect.TypeOf(lastScore))
+		if c.Since == nil || v == nil || err != nil {
+			return nil, cursor.ErrInvalid
+		}
+		now = *c.Since
+		lastScore = v.(float64)
+	}
+	seed, err := m.GetVideo(ctx, videoId, video.RelatedFields)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return nil, nil
+	}
+	var candidates []video.Video
+	found := make(map[string]bool)
+	for _, query := range relatedQueries(*seed, video.IncludeUnavailable(ctx)) {
+		videos, er1 := m.findVideos(ctx, query, video.RelatedFields, video.RelatedCandidates)
+		if er1 != nil {
+			return nil, er1
+		}
+		for _, v := range videos {
+			if !found[v.Id] {
+				found[v.Id] = true
+				candidates = append(candidates, v)
+			}
+		}
+	}
+	ranked := video.RankRelated(*seed, candidates, now, video.DefaultRelatedWeights)
+	result := video.ListResultVideos{Limit: limit}
+	skip := 0
+	if c != nil {
+		skip = video.RelatedAfter(ranked, lastScore, c.Id)
+	}
+	if skip >= len(ranked) {
+		return &result, nil
 	}
-	if itemSM.Sort != "" {
-		optionsFind.SetSort(bson.M{fmt.Sprintf(`%s`, itemSM.Sort): -1})
+	end := skip + limit
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	page := ranked[skip:end]
+	ids := make([]string, len(page))
+	for i, r := range page {
+		ids[i] = r.Video.Id
+	}
+	videos, er2 := m.GetVideos(ctx, ids, fields)
+	if er2 != nil {
+		return nil, er2
+	}
+	byId := make(map[string]video.Video)
+	for _, v := range *videos {
+		byId[v.Id] = v
+	}
+	for _, id := range ids {
+		if v, ok := byId[id]; ok {
+			result.List = append(result.List, v)
+		}
+	}
+	if end < len(ranked) {
+		last := page[len(page)-1]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "related", Values: []interface{}{cursor.Value(reflect.ValueOf(last.Score))}, Id: last.Video.Id, Since: &now})
+		if err != nil {
+			return nil, err
+		}
+		result.NextPageToken = token
+	}
+	return &result, nil
+}
+
+// findVideos reads the newest limit videos of query.
+func (m *MongoVideoService) findVideos(ctx context.Context, query interface{}, fields []string, limit int) ([]video.Video, error) {
+	optionsFind := options.Find().SetSort(bson.D{{"publishedAt", -1}, {"_id", 1}}).SetLimit(int64(limit))
+	if len(fields) > 0 {
+		projection, err := project(videoType, fields)
+		if err != nil {
+			return nil, err
+		}
+		optionsFind.SetProjection(projection)
 	}
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
 	res, err := m.VideoCollection.Find(ctx, query, optionsFind)
 	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
 		return nil, err
 	}
-	result := video.ListResultVideos{}
+	defer res.Close(ctx)
+	var result []video.Video
 	for res.Next(ctx) {
 		var video video.Video
 		if er1 := res.Decode(&video); er1 != nil {
 			return nil, er1
 		}
-		result.List = append(result.List, video)
+		result = append(result, video)
+	}
+	return result, res.Err()
+}
+
+// relatedQueries returns a query for each signal, so each is read up to video.RelatedCandidates.
+func relatedQueries(seed video.Video, unavailable bool) []bson.M {
+	var signals []bson.M
+	if len(seed.Tags) > 0 {
+		signals = append(signals, bson.M{"tags": bson.M{"$in": seed.Tags}})
+	}
+	if len(seed.ChannelId) > 0 {
+		signals = append(signals, bson.M{"channelId": seed.ChannelId})
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	if len(seed.CategoryId) > 0 {
+		signals = append(signals, bson.M{"categoryId": seed.CategoryId})
 	}
-	return &result, err
+	if terms := video.TitleTerms(seed.Title); len(terms) > 0 {
+		for i, term := range terms {
+			terms[i] = regexp.QuoteMeta(term)
+		}
+		signals = append(signals, bson.M{"title": primitive.Regex{Pattern: strings.Join(terms, "|"), Options: "i"}})
+	}
+	for _, query := range signals {
+		query["_id"] = bson.M{"$ne": seed.Id}
+		if !unavailable {
+			e := listed("")
+			query[e.Key] = e.Value
+		}
+	}
+	return signals
 }
 
-func (m *MongoVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+func (m *MongoVideoService) GetPopularVideos(ctx context.Context, regionCode string, categoryId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	k, _ := newKeyset(videoType, []video.SortKey{{Field: "viewCount", Desc: true}, {Field: "publishedAt", Desc: true}}, "")
+	c, er0 := cursor.Decode(nextPageToken, k.name)
 	if er0 != nil {
 		return nil, er0
 	}
-	query := buildQueryVideoSearch(itemSM)
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	query := bson.D{}
+	if regionCode != "" {
+		query = append(query, bson.E{"$and", bson.A{available("", regionCode)}})
 	}
-	if itemSM.Sort != "" {
-		optionsFind.SetSort(bson.M{fmt.Sprintf(`%s`, itemSM.Sort): -1})
+	if categoryId != "" {
+		query = append(query, bson.E{"categoryId", categoryId})
 	}
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetLimit(int64(limit))
-	res, err := m.VideoCollection.Find(ctx, query, optionsFind)
-	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
+	if !video.IncludeUnavailable(ctx) {
+		query = append(query, listed(""))
+	}
+	var result video.ListResultVideos
+	next, er1 := k.find(ctx, m.VideoCollection, query, c, limit, fields, &result.List)
+	if er1 != nil {
+		return nil, er1
+	}
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
+}
+
+// GetTrendingVideos keeps the start of the window in the page token, so every page ranks the same snapshots.
+func (m *MongoVideoService) GetTrendingVideos(ctx context.Context, regionCode string, categoryId string, window time.Duration, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	limit := getLimit(max)
+	c, er0 := cursor.Decode(nextPageToken, "trending")
+	if er0 != nil {
+		return nil, er0
+	}
+	since := time.Now().Add(-window).UTC()
+	after := bson.M{}
+	if c != nil {
+		v, err := cursor.Parse(firstValue(c.Values), reflect.TypeOf(float64(0)))
+		if c.Since == nil || v == nil || err != nil {
+			return nil, cursor.ErrInvalid
 		}
+		since = *c.Since
+		after = bson.M{"$or": bson.A{bson.M{"velocity": bson.M{"$lt": v}}, bson.M{"velocity": v, "_id": bson.M{"$gt": c.Id}}}}
+	}
+	match := bson.D{}
+	if categoryId != "" {
+		match = append(match, bson.E{"video.categoryId", categoryId})
+	}
+	if regionCode != "" {
+		match = append(match, bson.E{"$and", bson.A{available("video.", regionCode)}})
+	}
+	if !video.IncludeUnavailable(ctx) {
+		match = append(match, listed("video."))
+	}
+	fromNew := bson.M{"$and": bson.A{bson.M{"$gte": bson.A{"$video.publishedAt", since}}, bson.M{"$lt": bson.A{"$video.publishedAt", "$firstAt"}}}}
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.M{"timestamp": bson.M{"$gte": since}, "viewCount": bson.M{"$exists": true}}}},
+		{{"$sort", bson.D{{"timestamp", 1}}}},
+		{{"$group", bson.M{
+			"_id":        "$videoId",
+			"firstAt":    bson.M{"$first": "$timestamp"},
+			"firstViews": bson.M{"$first": "$viewCount"},
+			"lastAt":     bson.M{"$last": "$timestamp"},
+			"lastViews":  bson.M{"$last": "$viewCount"},
+		}}},
+		{{"$lookup", bson.M{"from": m.VideoCollection.Name(), "localField": "_id", "foreignField": "_id", "as": "video"}}},
+		{{"$unwind", "$video"}},
+		{{"$match", match}},
+		{{"$addFields", bson.M{
+			"baseAt":    bson.M{"$cond": bson.A{fromNew, "$video.publishedAt", "$firstAt"}},
+			"baseViews": bson.M{"$cond": bson.A{fromNew, 0, "$firstViews"}},
+		}}},
+		{{"$match", bson.M{"$expr": bson.M{"$gt": bson.A{"$lastAt", "$baseAt"}}}}},
+		{{"$addFields", bson.M{"velocity": bson.M{"$divide": bson.A{
+			bson.M{"$multiply": bson.A{bson.M{"$subtract": bson.A{"$lastViews", "$baseViews"}}, 3600000}},
+			bson.M{"$subtract": bson.A{"$lastAt", "$baseAt"}},
+		}}}}},
+		{{"$match", after}},
+		{{"$sort", bson.D{{"velocity", -1}, {"_id", 1}}}},
+		{{"$limit", int64(limit + 1)}},
+		{{"$replaceRoot", bson.M{"newRoot": bson.M{"$mergeObjects": bson.A{"$video", bson.M{"velocity": "$velocity"}}}}}},
+	}
+	if len(fields) > 0 {
+		projection, err := project(videoType, fields, "_id", "velocity")
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{"$project", projection}})
+	}
+	res, err := m.StatisticsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
 		return nil, err
 	}
-	result := video.ListResultVideos{}
+	defer res.Close(ctx)
+	var result video.ListResultVideos
+	var velocity float64
 	for res.Next(ctx) {
+		if len(result.List) == limit {
+			token, err := cursor.Encode(cursor.Cursor{Sort: "trending", Values: []interface{}{cursor.Value(reflect.ValueOf(velocity))}, Id: result.List[limit-1].Id, Since: &since})
+			if err != nil {
+				return nil, err
+			}
+			result.NextPageToken = token
+			break
+		}
 		var video video.Video
 		if er1 := res.Decode(&video); er1 != nil {
 			return nil, er1
 		}
+		velocity, _ = res.Current.Lookup("velocity").DoubleOK()
 		result.List = append(result.List, video)
 	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	if er2 := res.Err(); er2 != nil {
+		return nil, er2
 	}
-	return &result, err
+	result.Limit = limit
+	return &result, nil
 }
 
-func (m *MongoVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+func (m *MongoVideoService) GetChannelSubscriptions(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	if len(fields) > 0 {
+		if _, err := project(channelType, fields); err != nil {
+			return nil, err
+		}
+	}
+	k := keyset{name: "subscriptions", modelType: subscriptionType}
+	c, er0 := cursor.Decode(nextPageToken, k.name)
 	if er0 != nil {
 		return nil, er0
 	}
-	var a []string
-	resVd, err := m.GetVideo(ctx, videoId, a)
-	if err != nil {
-		return nil, err
+	var subscriptions []video.Subscription
+	next, er1 := k.find(ctx, m.SubscriptionCollection, bson.D{{"subscriberId", channelId}}, c, limit, nil, &subscriptions)
+	if er1 != nil {
+		return nil, er1
 	}
-	var result video.ListResultVideos
-	if resVd == nil {
-		return nil, errors.New("video don't exist")
-	} else {
-		array := []string{videoId}
-		query := bson.M{"tags": bson.M{"$in": resVd.Tags}, "_id": bson.M{"$nin": array}}
-		optionsFind := options.Find()
-		if len(fields) > 0 {
-			optionsFind.SetProjection(sel(fields...))
-		}
-		optionsFind.SetLimit(int64(limit))
-		optionsFind.SetSkip(int64(*skip))
-		optionsFind.SetSort(bson.M{"publishedAt": -1})
-		res, err := m.VideoCollection.Find(ctx, query, optionsFind)
-		if err != nil {
-			if strings.Contains(err.Error(), "mongo: no documents in result") {
-				return nil, nil
-			}
-			return nil, err
-		}
-		for res.Next(ctx) {
-			var video video.Video
-			if er1 := res.Decode(&video); er1 != nil {
-				return nil, er1
-			}
-			result.List = append(result.List, video)
-		}
-		if len(result.List) > 0 {
-			result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+	ids := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ChannelId
+	}
+	channels, er2 := m.GetChannels(ctx, ids, fields)
+	if er2 != nil {
+		return nil, er2
+	}
+	byId := make(map[string]video.Channel)
+	for _, channel := range *channels {
+		byId[channel.Id] = channel
+	}
+	result := video.ListResultChannel{NextPageToken: next, Limit: limit}
+	for _, subscription := range subscriptions {
+		if channel, ok := byId[subscription.ChannelId]; ok {
+			result.List = append(result.List, channel)
+		} else {
+			result.List = append(result.List, video.SubscriptionChannel(subscription, fields))
 		}
 	}
 	return &result, nil
 }
 
-func (m *MongoVideoService) GetPopularVideos(ctx context.Context, regionCode string, categoryId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+func (m *MongoVideoService) GetChannelSubscribers(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
 	limit := getLimit(max)
-	skip, er0 := getSkip(nextPageToken)
+	k := keyset{name: "subscribers", modelType: channelType}
+	c, er0 := cursor.Decode(nextPageToken, k.name)
 	if er0 != nil {
 		return nil, er0
 	}
-	query := bson.D{}
+	ids, er1 := m.subscriptionIds(ctx, bson.M{"channelId": channelId}, "subscriberId")
+	if er1 != nil {
+		return nil, er1
+	}
+	result := video.ListResultChannel{}
+	next, er2 := k.find(ctx, m.ChannelCollection, bson.D{{"_id", bson.M{"$in": ids}}}, c, limit, fields, &result.List)
+	if er2 != nil {
+		return nil, er2
+	}
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
+}
+
+func (m *MongoVideoService) GetSubscriptionVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	limit := getLimit(max)
+	k, _ := sortKeyset(videoType, video.VideoSortable, "", "")
+	c, er0 := cursor.Decode(nextPageToken, k.name)
+	if er0 != nil {
+		return nil, er0
+	}
+	ids, er1 := m.subscriptionIds(ctx, bson.M{"subscriberId": channelId}, "channelId")
+	if er1 != nil {
+		return nil, er1
+	}
+	query := bson.D{{"channelId", bson.M{"$in": ids}}}
 	if regionCode != "" {
-		query = append(query, bson.E{"blockedRegions", bson.M{"$ne": regionCode}})
+		query = append(query, bson.E{"$and", bson.A{available("", regionCode)}})
 	}
-	if categoryId != "" {
-		query = append(query, bson.E{"categoryId", categoryId})
+	if !video.IncludeUnavailable(ctx) {
+		query = append(query, listed(""))
 	}
-	optionsFind := options.Find()
-	if len(fields) > 0 {
-		optionsFind.SetProjection(sel(fields...))
+	var result video.ListResultVideos
+	next, er2 := k.find(ctx, m.VideoCollection, query, c, limit, fields, &result.List)
+	if er2 != nil {
+		return nil, er2
 	}
-	optionsFind.SetLimit(int64(limit))
-	optionsFind.SetSkip(int64(*skip))
-	optionsFind.SetSort(bson.M{"publishedAt": -1})
-	res, err := m.VideoCollection.Find(ctx, query, optionsFind)
+	result.NextPageToken = next
+	result.Limit = limit
+	return &result, nil
+}
+
+func (m *MongoVideoService) subscriptionIds(ctx context.Context, query bson.M, field string) ([]string, error) {
+	res, err := m.SubscriptionCollection.Find(ctx, query, options.Find().SetProjection(sel(field)))
 	if err != nil {
-		if strings.Contains(err.Error(), "mongo: no documents in result") {
-			return nil, nil
-		}
 		return nil, err
 	}
-	var result video.ListResultVideos
+	defer res.Close(ctx)
+	ids := make([]string, 0)
 	for res.Next(ctx) {
-		var video video.Video
-		if er1 := res.Decode(&video); er1 != nil {
+		var document bson.M
+		if er1 := res.Decode(&document); er1 != nil {
 			return nil, er1
 		}
-		result.List = append(result.List, video)
-	}
-	if len(result.List) > 0 {
-		result.NextPageToken = getNextPageToken(result.List[len(result.List)-1].Id, len(result.List), limit, *skip)
+		if id, ok := document[field].(string); ok {
+			ids = append(ids, id)
+		}
 	}
-	return &result, nil
+	return ids, res.Err()
 }
 
 func buildQueryChannelSearch(channelSM video.ChannelSM) bson.D {
@@ -572,11 +887,11 @@ func buildQueryChannelSearch(channelSM video.ChannelSM) bson.D {
 		query = append(query, bson.E{"$or", []bson.M{{"title": primitive.Regex{Pattern: channelSM.Q, Options: "i"}}, {"description": primitive.Regex{Pattern: channelSM.Q, Options: "i"}}}})
 	}
 	if channelSM.PublishedBefore != nil && channelSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": channelSM.PublishedBefore, "$lte": channelSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": channelSM.PublishedAfter, "$lte": channelSM.PublishedBefore}})
 	} else if channelSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$lte": channelSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": channelSM.PublishedAfter}})
 	} else if channelSM.PublishedBefore != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": channelSM.PublishedBefore}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$lte": channelSM.PublishedBefore}})
 	}
 	if channelSM.ChannelId != "" {
 		query = append(query, bson.E{"_id", channelSM.ChannelId})
@@ -602,11 +917,11 @@ func buildQueryPlaylistSearch(playlistSM video.PlaylistSM) bson.D {
 		query = append(query, bson.E{"$or", []bson.M{{"title": primitive.Regex{Pattern: playlistSM.Q, Options: "i"}}, {"description": primitive.Regex{Pattern: playlistSM.Q, Options: "i"}}}})
 	}
 	if playlistSM.PublishedBefore != nil && playlistSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": playlistSM.PublishedBefore, "$lte": playlistSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": playlistSM.PublishedAfter, "$lte": playlistSM.PublishedBefore}})
 	} else if playlistSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$lte": playlistSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": playlistSM.PublishedAfter}})
 	} else if playlistSM.PublishedBefore != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": playlistSM.PublishedBefore}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$lte": playlistSM.PublishedBefore}})
 	}
 	if playlistSM.ChannelId != "" {
 		query = append(query, bson.E{"channelId", playlistSM.ChannelId})
@@ -623,12 +938,12 @@ func buildQueryPlaylistSearch(playlistSM video.PlaylistSM) bson.D {
 	return query
 }
 
-func buildQueryVideoSearch(itemSM video.ItemSM) bson.D {
+func buildQueryVideoSearch(itemSM video.ItemSM, related []string, unavailable bool) bson.D {
 	query := bson.D{}
 	if itemSM.Duration != "" {
 		switch itemSM.Duration {
 		case "short":
-			query = append(query, bson.E{"duration", bson.M{"$lte": 240}})
+			query = append(query, bson.E{"duration", bson.M{"$gt": 0, "$lte": 240}})
 			break
 		case "medium":
 			query = append(query, bson.E{"duration", bson.M{"$gt": 240, "$lte": 1200}})
@@ -644,23 +959,76 @@ func buildQueryVideoSearch(itemSM video.ItemSM) bson.D {
 		query = append(query, bson.E{"$or", []bson.M{{"title": primitive.Regex{Pattern: itemSM.Q, Options: "i"}}, {"description": primitive.Regex{Pattern: itemSM.Q, Options: "i"}}}})
 	}
 	if itemSM.PublishedBefore != nil && itemSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": itemSM.PublishedBefore, "$lte": itemSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": itemSM.PublishedAfter, "$lte": itemSM.PublishedBefore}})
 	} else if itemSM.PublishedAfter != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$lte": itemSM.PublishedAfter}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$gte": itemSM.PublishedAfter}})
 	} else if itemSM.PublishedBefore != nil {
-		query = append(query, bson.E{"publishedAt", bson.M{"$gt": itemSM.PublishedBefore}})
+		query = append(query, bson.E{"publishedAt", bson.M{"$lte": itemSM.PublishedBefore}})
 	}
 	if itemSM.ChannelId != "" {
 		query = append(query, bson.E{"channelId", itemSM.ChannelId})
 	}
-	if itemSM.ChannelType != "" {
-		query = append(query, bson.E{"channelType", itemSM.ChannelType})
+	if itemSM.CategoryId != "" {
+		query = append(query, bson.E{"categoryId", itemSM.CategoryId})
 	}
-	if itemSM.RelevanceLanguage != "" {
-		query = append(query, bson.E{"relevanceLanguage", itemSM.RelevanceLanguage})
+	switch itemSM.Caption {
+	case "closedCaption":
+		query = append(query, bson.E{"caption", "true"})
+	case "none":
+		query = append(query, bson.E{"caption", bson.M{"$ne": "true"}})
+	}
+	switch itemSM.Definition {
+	case "high":
+		query = append(query, bson.E{"definition", 5})
+	case "standard":
+		query = append(query, bson.E{"definition", bson.M{"$ne": 5}})
+	}
+	if itemSM.Dimension == "2d" || itemSM.Dimension == "3d" {
+		query = append(query, bson.E{"dimension", itemSM.Dimension})
+	}
+	switch itemSM.EventType {
+	case "live", "upcoming":
+		query = append(query, bson.E{"liveBroadcastContent", itemSM.EventType})
+	case "completed":
+		query = append(query, bson.E{"liveBroadcastContent", bson.M{"$nin": []string{"live", "upcoming"}}})
+	}
+	if itemSM.License != "" && itemSM.License != "any" {
+		query = append(query, bson.E{"license", itemSM.License})
+	}
+	if itemSM.Embeddable == "true" {
+		query = append(query, bson.E{"embeddable", true})
+	}
+	if itemSM.SafeSearch == "moderate" || itemSM.SafeSearch == "strict" {
+		query = append(query, bson.E{"ytRating", bson.M{"$ne": video.AgeRestricted}})
+	}
+	if itemSM.TopicId != "" {
+		query = append(query, bson.E{"topicIds", itemSM.TopicId})
 	}
+	if itemSM.RelatedToVideoId != "" {
+		query = append(query, bson.E{"_id", bson.M{"$ne": itemSM.RelatedToVideoId}}, bson.E{"tags", bson.M{"$in": related}})
+	}
+	if !unavailable {
+		query = append(query, listed(""))
+	}
+	// the filters below repeat a field or $or, so they are put under $and
+	var and []bson.M
 	if itemSM.RegionCode != "" {
-		query = append(query, bson.E{"blockedRegions", bson.M{"$ne": itemSM.RegionCode}})
+		and = append(and, available("", itemSM.RegionCode))
+	}
+	if category, ok := video.VideoTypeCategories[itemSM.VideoType]; ok {
+		and = append(and, bson.M{"categoryId": category})
+	}
+	if itemSM.RelevanceLanguage != "" {
+		language := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(itemSM.RelevanceLanguage) + "(-|$)", Options: "i"}
+		none := bson.M{"$in": []interface{}{nil, ""}}
+		and = append(and, bson.M{"$or": []bson.M{
+			{"defaultLanguage": language},
+			{"defaultAudioLanguage": language},
+			{"defaultLanguage": none, "defaultAudioLanguage": none},
+		}})
+	}
+	if len(and) > 0 {
+		query = append(query, bson.E{"$and", and})
 	}
 	return query
 }
@@ -673,6 +1041,18 @@ func sel(q ...string) (r bson.M) {
 	return
 }
 
+func available(prefix string, regionCode string) bson.M {
+	regionCode = video.RegionCode(regionCode)
+	return bson.M{"$or": bson.A{
+		bson.M{prefix + "allowedRegions": regionCode},
+		bson.M{prefix + "allowedRegions.0": bson.M{"$exists": false}, prefix + "blockedRegions": bson.M{"$nin": bson.A{regionCode}}},
+	}}
+}
+
+func listed(prefix string) bson.E {
+	return bson.E{prefix + "status", bson.M{"$in": bson.A{nil, ""}}}
+}
+
 func getLimit(max int) int {
 	if max == 0 {
 		return 12
@@ -681,33 +1061,9 @@ func getLimit(max int) int {
 	}
 }
 
-func getSkip(nextPageToken string) (*int, error) {
-	a := 0
-	if len(nextPageToken) > 0 {
-		arr := strings.Split(nextPageToken, "|")
-		if len(arr) < 2 {
-			return nil, nil
-		}
-		if len(arr[0]) <= 0 {
-			return &a, nil
-		}
-		i, err := strconv.Atoi(arr[0])
-		if err != nil {
-			return nil, err
-		}
-		return &i, nil
-	}
-	return &a, nil
-}
-
-func getNextPageToken(id string, len int, limit int, skip int) string {
-	if len < limit {
-		return ""
-	} else {
-		if len < 0 {
-			return ""
-		} else {
-			return fmt.Sprintf(`%d|%s`, skip+limit, id)
-		}
+func firstValue(values []interface{}) interface{} {
+	if len(values) != 1 {
+		return nil
 	}
+	return values[0]
 }
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
+	initmongo "github.com/core-go/video/init-mongo"
+	syncmongo "github.com/core-go/video/sync-mongo"
+	"github.com/core-go/video/videotest"
+)
+
+func init() {
+	cursor.SetKey([]byte("test key"))
+}
+
+// testDatabase is dropped and created again for each test.
+const testDatabase = "videotest"
+
+// openTestDB connects to the server VIDEO_TEST_MONGO names, with testDatabase empty and migrated, or skips the test.
+func openTestDB(t *testing.T) *mongo.Database {
+	uri := os.Getenv("VIDEO_TEST_MONGO")
+	if len(uri) == 0 {
+		t.Skip("VIDEO_TEST_MONGO is not set")
+	}
+	ctx := context.Background()
+	client, er0 := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+	db := client.Database(testDatabase)
+	if er1 := db.Drop(ctx); er1 != nil {
+		t.Fatal(er1)
+	}
+	if _, er2 := initmongo.Initialize(ctx, db, "channel", "channelSync", "playlist", "playlistVideo", "video", "category"); er2 != nil {
+		t.Fatal(er2)
+	}
+	return db
+}
+
+func newTestBackend(t *testing.T) (*mongo.Database, *MongoVideoService, *syncmongo.MongoVideoRepository) {
+	db := openTestDB(t)
+	service := NewMongoVideoService(db, "channel", "channelSync", "playlist", "playlistVideo", "video", "category", category.CategorySyncClient{})
+	repository := syncmongo.NewMongoVideoRepository(db, "channel", "channelSync", "playlist", "playlistVideo", "video", "category")
+	return db, service, repository
+}
+
+func TestVideoService(t *testing.T) {
+	videotest.RunVideoServiceSuite(t, func(t *testing.T) (video.VideoService, video.SyncRepository) {
+		_, service, repository := newTestBackend(t)
+		return service, repository
+	})
+}
+
+func TestSyncRepository(t *testing.T) {
+	videotest.RunSyncRepositorySuite(t, func(t *testing.T) video.SyncRepository {
+		_, _, repository := newTestBackend(t)
+		return repository
+	})
+}
+
+func TestBackend(t *testing.T) {
+	videotest.RunBackendSuite(t, func(t *testing.T) videotest.Backend {
+		db, service, repository := newTestBackend(t)
+		return videotest.Backend{
+			Service:       service,
+			Repository:    repository,
+			Statistics:    syncmongo.NewMongoStatisticsRepository(db, "videoStatistics"),
+			Subscriptions: syncmongo.NewMongoSubscriptionRepository(db, "subscription"),
+			PlaylistItems: syncmongo.NewMongoPlaylistItemRepository(db, "playlistItem"),
+			Tombstones:    syncmongo.NewMongoTombstoneRepository(db, "video"),
+			Checkpoints:   syncmongo.NewMongoCheckpointRepository(db, "syncCheckpoint"),
+		}
+	})
+}
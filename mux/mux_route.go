@@ -17,6 +17,9 @@ type Sync interface {
 	SyncChannel(w http.ResponseWriter, r *http.Request)
 	SyncPlaylist(w http.ResponseWriter, r *http.Request)
 	SyncSubscription(w http.ResponseWriter, r *http.Request)
+	SyncSubscriptions(w http.ResponseWriter, r *http.Request)
+	GetJob(w http.ResponseWriter, r *http.Request)
+	CancelJob(w http.ResponseWriter, r *http.Request)
 }
 
 type Service interface {
@@ -34,20 +37,28 @@ type Service interface {
 	SearchVideos(w http.ResponseWriter, r *http.Request)
 	GetRelatedVideos(w http.ResponseWriter, r *http.Request)
 	GetPopularVideos(w http.ResponseWriter, r *http.Request)
+	GetTrendingVideos(w http.ResponseWriter, r *http.Request)
 	Search(w http.ResponseWriter, r *http.Request)
+	GetChannelSubscriptions(w http.ResponseWriter, r *http.Request)
+	GetChannelSubscribers(w http.ResponseWriter, r *http.Request)
+	GetSubscriptionVideos(w http.ResponseWriter, r *http.Request)
 }
 
-func Register(ctx context.Context, r *mux.Router, param string, service Service)  {
+func Register(ctx context.Context, r *mux.Router, param string, service Service) {
 	s := r.PathPrefix(param).Subrouter()
 	s.HandleFunc("/category", service.GetCategory).Methods(GET)
 	s.HandleFunc("/channels/search", service.SearchChannel).Methods(GET)
 	s.HandleFunc("/channels/list", service.GetChannels).Methods(GET)
 	s.HandleFunc("/channels/{id}", service.GetChannel).Methods(GET)
+	s.HandleFunc("/channels/{id}/subscriptions", service.GetChannelSubscriptions).Methods(GET)
+	s.HandleFunc("/channels/{id}/subscribers", service.GetChannelSubscribers).Methods(GET)
+	s.HandleFunc("/channels/{id}/feed", service.GetSubscriptionVideos).Methods(GET)
 	s.HandleFunc("/playlists/search", service.SearchPlaylists).Methods(GET)
 	s.HandleFunc("/playlists/list", service.GetPlaylists).Methods(GET)
 	s.HandleFunc("/playlists", service.GetChannelPlaylists).Methods(GET)
 	s.HandleFunc("/playlists/{id}", service.GetPlaylist).Methods(GET)
 	s.HandleFunc("/videos/popular", service.GetPopularVideos).Methods(GET)
+	s.HandleFunc("/videos/trending", service.GetTrendingVideos).Methods(GET)
 	s.HandleFunc("/videos/search", service.SearchVideos).Methods(GET)
 	s.HandleFunc("/videos/list", service.GetVideos).Methods(GET)
 	s.HandleFunc("/video/{id}", service.GetVideo).Methods(GET)
@@ -56,9 +67,12 @@ func Register(ctx context.Context, r *mux.Router, param string, service Service)
 	s.HandleFunc("/search", service.Search).Methods(GET)
 }
 
-func RegisterSync(ctx context.Context, r *mux.Router, param string, sync Sync)  {
+func RegisterSync(ctx context.Context, r *mux.Router, param string, sync Sync) {
 	s := r.PathPrefix(param).Subrouter()
 	s.HandleFunc("/channel", sync.SyncChannel).Methods(POST)
 	s.HandleFunc("/playlists", sync.SyncPlaylist).Methods(POST)
 	s.HandleFunc("/channels/subscriptions/{id}", sync.SyncSubscription).Methods(GET)
+	s.HandleFunc("/channels/subscriptions", sync.SyncSubscriptions).Methods(POST)
+	s.HandleFunc("/jobs/{id}", sync.GetJob).Methods(GET)
+	s.HandleFunc("/jobs/{id}", sync.CancelJob).Methods(DELETE)
 }
@@ -0,0 +1,294 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+const byteId = `id collate "C"`
+
+var (
+	stringType  = reflect.TypeOf("")
+	float64Type = reflect.TypeOf(float64(0))
+)
+
+// keyset pages after the sort values of the last row instead of an offset. Nulls sort last, ties by id.
+// Text is compared with the C collation, so the byte order of before agrees with SQL.
+type keyset struct {
+	name        string
+	keys        []video.SortKey
+	search      textSearch
+	modelType   reflect.Type
+	fieldsIndex map[string]int
+}
+
+func newKeyset(keys []video.SortKey, search textSearch, modelType reflect.Type, fieldsIndex map[string]int) (keyset, error) {
+	for _, key := range keys {
+		if _, ok := fieldsIndex[strings.ToLower(key.Field)]; !ok && key.Field != video.Relevance {
+			return keyset{}, fmt.Errorf("%w: %s", video.ErrInvalidSort, key.Field)
+		}
+	}
+	return keyset{name: video.FormatSort(keys), keys: keys, search: search, modelType: modelType, fieldsIndex: fieldsIndex}, nil
+}
+
+func sortKeyset(sort string, sortable video.Sortable, search textSearch, modelType reflect.Type, fieldsIndex map[string]int) (keyset, error) {
+	keys, err := video.ParseSort(sort, sortable, search.q)
+	if err != nil {
+		return keyset{}, err
+	}
+	return newKeyset(keys, search, modelType, fieldsIndex)
+}
+
+func (k keyset) expression(key video.SortKey) string {
+	switch key.Field {
+	case video.Relevance:
+		return k.search.rank()
+	case "title":
+		return `lower(coalesce(title, '')) collate "C"`
+	}
+	return key.Field
+}
+
+func (k keyset) valueType(key video.SortKey) reflect.Type {
+	switch key.Field {
+	case video.Relevance:
+		return float64Type
+	case "title":
+		return stringType
+	}
+	return k.modelType.Field(k.fieldsIndex[strings.ToLower(key.Field)]).Type
+}
+
+func (k keyset) orderBy() string {
+	orders := make([]string, 0, len(k.keys)+1)
+	for _, key := range k.keys {
+		if key.Desc {
+			orders = append(orders, k.expression(key)+" desc nulls last")
+		} else {
+			orders = append(orders, k.expression(key)+" asc nulls last")
+		}
+	}
+	orders = append(orders, byteId)
+	return " order by " + strings.Join(orders, ", ")
+}
+
+// project adds the id and the sort columns to fields, so the cursor can be read from the last row.
+func (k keyset) project(fields []string) []string {
+	if len(fields) == 0 {
+		return append([]string{"*"}, k.computed()...)
+	}
+	selected := make(map[string]bool)
+	for _, field := range fields {
+		selected[strings.ToLower(field)] = true
+	}
+	columns := []string{"id"}
+	for _, key := range k.keys {
+		if key.Field != video.Relevance {
+			columns = append(columns, key.Field)
+		}
+	}
+	res := append([]string{}, fields...)
+	for _, column := range columns {
+		if !selected[strings.ToLower(column)] {
+			selected[strings.ToLower(column)] = true
+			res = append(res, column)
+		}
+	}
+	return append(res, k.computed()...)
+}
+
+func (k keyset) computed() []string {
+	var columns []string
+	if len(k.keys) > 0 && k.keys[0].Field == video.Relevance {
+		columns = append(columns, k.search.rank()+" as rank")
+	}
+	if len(k.search.q) > 0 {
+		columns = append(columns, k.search.headline()+" as highlight")
+	}
+	return columns
+}
+
+func (k keyset) where(c *cursor.Cursor, i int) (string, []interface{}, error) {
+	if len(c.Values) != len(k.keys) {
+		return "", nil, cursor.ErrInvalid
+	}
+	var params []interface{}
+	equal := make([]string, 0, len(k.keys)+1)
+	var or []string
+	for j, key := range k.keys {
+		column := k.expression(key)
+		v, err := cursor.Parse(c.Values[j], k.valueType(key))
+		if err != nil {
+			return "", nil, err
+		}
+		if v == nil {
+			equal = append(equal, column+" is null")
+			continue
+		}
+		params = append(params, v)
+		compare := ">"
+		if key.Desc {
+			compare = "<"
+		}
+		after := fmt.Sprintf(`(%s %s $%d or %s is null)`, column, compare, i, column)
+		or = append(or, strings.Join(append(equal, after), " and "))
+		equal = append(equal, fmt.Sprintf(`%s = $%d`, column, i))
+		i++
+	}
+	params = append(params, c.Id)
+	or = append(or, strings.Join(append(equal, fmt.Sprintf(`%s > $%d`, byteId, i)), " and "))
+	return "(" + strings.Join(or, " or ") + ")", params, nil
+}
+
+// query also returns the rank column of each row, 0 when there is none.
+func (k keyset) query(ctx context.Context, db *sql.DB, results interface{}, toArray func(interface{}) interface {
+	driver.Valuer
+	sql.Scanner
+}, sql string, values ...interface{}) ([]float64, error) {
+	rows, er1 := db.QueryContext(ctx, sql, values...)
+	if er1 != nil {
+		return nil, er1
+	}
+	defer rows.Close()
+	columns, er2 := GetColumns(rows.Columns())
+	if er2 != nil {
+		return nil, er2
+	}
+	list := reflect.ValueOf(results).Elem()
+	var ranks []float64
+	for rows.Next() {
+		item := reflect.New(list.Type().Elem())
+		r, swapValues := StructScan(item.Interface(), columns, k.fieldsIndex, toArray)
+		var rank float64
+		for i, column := range columns {
+			switch column {
+			case "rank":
+				r[i] = &rank
+			case "highlight":
+				r[i] = item.Elem().FieldByName("Highlight").Addr().Interface()
+			}
+		}
+		if er3 := rows.Scan(r...); er3 != nil {
+			return nil, er3
+		}
+		SwapValuesToBool(item.Interface(), &swapValues)
+		list.Set(reflect.Append(list, item.Elem()))
+		ranks = append(ranks, rank)
+	}
+	return ranks, rows.Err()
+}
+
+func (k keyset) values(row reflect.Value, rank float64) []interface{} {
+	values := make([]interface{}, len(k.keys))
+	for j, key := range k.keys {
+		switch key.Field {
+		case video.Relevance:
+			values[j] = rank
+		case "title":
+			values[j] = video.SortTitle(row.FieldByName("Title").String())
+		default:
+			v := row.Field(k.fieldsIndex[strings.ToLower(key.Field)])
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					continue
+				}
+				v = v.Elem()
+			}
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				values[j] = v.Int()
+			case reflect.Float32, reflect.Float64:
+				values[j] = v.Float()
+			default:
+				values[j] = v.Interface()
+			}
+		}
+	}
+	return values
+}
+
+func (k keyset) before(va []interface{}, a string, vb []interface{}, b string) bool {
+	for j, key := range k.keys {
+		if va[j] == nil || vb[j] == nil {
+			if (va[j] == nil) != (vb[j] == nil) {
+				return vb[j] == nil
+			}
+			continue
+		}
+		if c := compare(va[j], vb[j]); c != 0 {
+			return (c < 0) != key.Desc
+		}
+	}
+	return a < b
+}
+
+func compare(a interface{}, b interface{}) int {
+	switch x := a.(type) {
+	case time.Time:
+		y := b.(time.Time)
+		if x.Before(y) {
+			return -1
+		} else if x.After(y) {
+			return 1
+		}
+	case int64:
+		y := b.(int64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	}
+	return 0
+}
+
+// next trims the row fetched past limit off list and returns the token of the next page.
+func (k keyset) next(list interface{}, limit int, ranks ...float64) (string, error) {
+	v := reflect.ValueOf(list).Elem()
+	if limit <= 0 || v.Len() <= limit {
+		return "", nil
+	}
+	v.Set(v.Slice(0, limit))
+	last := v.Index(limit - 1)
+	var rank float64
+	if len(ranks) >= limit {
+		rank = ranks[limit-1]
+	}
+	return k.token(k.values(last, rank), last.Field(k.fieldsIndex["id"]).String())
+}
+
+func (k keyset) token(values []interface{}, id string) (string, error) {
+	c := cursor.Cursor{Sort: k.name, Values: make([]interface{}, len(k.keys)), Id: id}
+	for j, value := range values {
+		if value != nil {
+			c.Values[j] = cursor.Value(reflect.ValueOf(value))
+		}
+	}
+	return cursor.Encode(c)
+}
+
+func checkFields(fields []string, fieldsIndex map[string]int) error {
+	for _, field := range fields {
+		if _, ok := fieldsIndex[strings.ToLower(field)]; !ok {
+			return fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
+	}
+	return nil
+}
@@ -3,25 +3,29 @@ package pg
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/core-go/video"
 	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
 	"github.com/lib/pq"
 )
 
 type PostgreVideoService struct {
-	db                  *sql.DB
-	tubeCategory        category.CategorySyncClient
-	channelFields  		map[string]int
-	modelTypeChannel 	reflect.Type
-	playlistFields 		map[string]int
-	videoFields    		map[string]int
-	categoryFields 		map[string]int
+	db                 *sql.DB
+	tubeCategory       category.CategorySyncClient
+	channelFields      map[string]int
+	modelTypeChannel   reflect.Type
+	playlistFields     map[string]int
+	modelTypePlaylist  reflect.Type
+	videoFields        map[string]int
+	modelTypeVideo     reflect.Type
+	categoryFields     map[string]int
+	subscriptionFields map[string]int
 }
 
 func NewPostgreVideoService(db *sql.DB, tubeCategory category.CategorySyncClient) (*PostgreVideoService, error) {
@@ -53,18 +57,30 @@ func NewPostgreVideoService(db *sql.DB, tubeCategory category.CategorySyncClient
 		return nil, er4
 	}
 
+	modelTypeSubscription := reflect.TypeOf(video.Subscription{})
+	subscriptionFields, er5 := GetColumnIndexes(modelTypeSubscription)
+	if er5 != nil {
+		return nil, er5
+	}
+
 	return &PostgreVideoService{
-		db:             db,
-		tubeCategory:   tubeCategory,
-		channelFields:  channelFields,
-		modelTypeChannel: modelTypeChannel,
-		playlistFields: playlistFields,
-		videoFields:    videoFields,
-		categoryFields: categoryFields,
+		db:                 db,
+		tubeCategory:       tubeCategory,
+		channelFields:      channelFields,
+		modelTypeChannel:   modelTypeChannel,
+		playlistFields:     playlistFields,
+		modelTypePlaylist:  modelTypePlaylist,
+		videoFields:        videoFields,
+		modelTypeVideo:     modelTypeVideo,
+		categoryFields:     categoryFields,
+		subscriptionFields: subscriptionFields,
 	}, nil
 }
 
 func (s *PostgreVideoService) GetChannel(ctx context.Context, channelId string, fields []string) (*video.Channel, error) {
+	if err := checkFields(fields, s.channelFields); err != nil {
+		return nil, err
+	}
 	if len(fields) == 0 {
 		fields = append(fields, "*")
 	}
@@ -88,6 +104,12 @@ func (s *PostgreVideoService) GetChannel(ctx context.Context, channelId string,
 }
 
 func (s *PostgreVideoService) GetChannels(ctx context.Context, ids []string, fields []string) (*[]video.Channel, error) {
+	if err := checkFields(fields, s.channelFields); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Channel{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -103,13 +125,13 @@ func (s *PostgreVideoService) GetChannels(ctx context.Context, ids []string, fie
 	if err != nil {
 		return nil, err
 	}
-	if len(arrRes) == 0 {
-		return nil, nil
-	}
 	return &arrRes, nil
 }
 
 func (s *PostgreVideoService) GetPlaylist(ctx context.Context, id string, fields []string) (*video.Playlist, error) {
+	if err := checkFields(fields, s.playlistFields); err != nil {
+		return nil, err
+	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
@@ -119,10 +141,19 @@ func (s *PostgreVideoService) GetPlaylist(ctx context.Context, id string, fields
 	if err != nil {
 		return nil, err
 	}
+	if len(res) == 0 {
+		return nil, nil
+	}
 	return &res[0], nil
 }
 
 func (s *PostgreVideoService) GetPlaylists(ctx context.Context, ids []string, fields []string) (*[]video.Playlist, error) {
+	if err := checkFields(fields, s.playlistFields); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Playlist{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -142,6 +173,9 @@ func (s *PostgreVideoService) GetPlaylists(ctx context.Context, ids []string, fi
 }
 
 func (s *PostgreVideoService) GetVideo(ctx context.Context, id string, fields []string) (*video.Video, error) {
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
 	if len(fields) <= 0 {
 		fields = append(fields, "*")
 	}
@@ -158,6 +192,12 @@ func (s *PostgreVideoService) GetVideo(ctx context.Context, id string, fields []
 }
 
 func (s *PostgreVideoService) GetVideos(ctx context.Context, ids []string, fields []string) (*[]video.Video, error) {
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &[]video.Video{}, nil
+	}
 	question := make([]string, len(ids))
 	cc := make([]interface{}, len(ids))
 	for i, v := range ids {
@@ -173,102 +213,75 @@ func (s *PostgreVideoService) GetVideos(ctx context.Context, ids []string, field
 	if err != nil {
 		return nil, err
 	}
-	if len(arrRes) == 0 {
-		return nil, nil
-	}
 	return &arrRes, nil
 }
 
 func (s *PostgreVideoService) GetChannelPlaylists(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
-	next := getNext(nextPageToken)
-	query := fmt.Sprintf(`select %s from playlist where channelId=$1 order by publishedAt desc limit %d offset %s`, strings.Join(fields, ","), max, next)
-	var res video.ListResultPlaylist
-	er1 := QueryWithMap(ctx, s.db, s.playlistFields, &res.List, query, channelId)
-	if er1 != nil {
-		return nil, er1
-	}
-	res.Limit = max
-	lenList := len(res.List)
-	res.Total = lenList
-	r, er2 := strconv.Atoi(next)
-	if er2 != nil {
-		return nil, errors.New("nextPageToken wrong")
-	}
-	res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
+	return s.SearchPlaylists(ctx, video.PlaylistSM{ChannelId: channelId}, max, nextPageToken, fields)
+}
 
-	return &res, nil
+func (s *PostgreVideoService) GetChannelVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	return s.SearchVideos(ctx, video.ItemSM{ChannelId: channelId, RegionCode: regionCode}, max, nextPageToken, fields)
 }
 
-func (s *PostgreVideoService) GetChannelVideos(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
+func (s *PostgreVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
 	}
-	next := getNext(nextPageToken)
-	query := fmt.Sprintf(`select %s from video where channelId=$1 order by publishedAt desc limit %d offset %s`, strings.Join(fields, ","), max, next)
-	var res video.ListResultVideos
-	er1 := QueryWithMapAndArray(ctx, s.db, s.videoFields, &res.List, pq.Array, query, channelId)
-	if er1 != nil {
+	items, er1 := s.getPlaylistItems(ctx, playlistId)
+	if er1 != nil || items == nil {
 		return nil, er1
 	}
-	res.Limit = max
-	lenList := len(res.List)
-	res.Total = lenList
-	r, er2 := strconv.Atoi(next)
-	if er2 != nil {
-		return nil, errors.New("nextPageToken wrong")
+	if max <= 0 {
+		max = 12
 	}
-	res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
-
-	return &res, nil
+	columns := "*"
+	if len(fields) > 0 {
+		columns = strings.Join(append(fields, "id"), ",")
+	}
+	return video.PagePlaylist(video.PlaylistOrder(items), max, nextPageToken, func(ids []string) ([]video.Video, error) {
+		query := fmt.Sprintf(`select %s from video where id = any($1)`, columns)
+		values := []interface{}{pq.Array(ids)}
+		if len(regionCode) > 0 {
+			values = append(values, video.RegionCode(regionCode))
+			query += ` and ` + available("", len(values))
+		}
+		if !video.IncludeUnavailable(ctx) {
+			query += ` and ` + listed("")
+		}
+		var videos []video.Video
+		err := QueryWithMapAndArray(ctx, s.db, s.videoFields, &videos, pq.Array, query, values...)
+		return videos, err
+	})
 }
 
-func (s *PostgreVideoService) GetPlaylistVideos(ctx context.Context, playlistId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	query1 := `select * from playlistVideo where id = $1 `
-	var resPlaylistVideoIdVideos []video.PlaylistVideoIdVideos
-	er1 := QueryWithMapAndArray(ctx, s.db, nil, &resPlaylistVideoIdVideos, pq.Array, query1, playlistId)
-	if er1 != nil {
-		return nil, er1
-	}
-	questions := make([]string, len(resPlaylistVideoIdVideos[0].Videos))
-	values := make([]interface{}, len(resPlaylistVideoIdVideos[0].Videos))
-	for i, v := range resPlaylistVideoIdVideos[0].Videos {
-		questions[i] = fmt.Sprintf(`$%d`, i+1)
-		values[i] = v
-	}
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
-	next := getNext(nextPageToken)
-	query2 := fmt.Sprintf(`select %s from video where id in (%s) order by publishedAt desc limit %d offset %s`, strings.Join(fields, ","), strings.Join(questions, ","), max, next)
-	var res video.ListResultVideos
-	er2 := QueryWithMapAndArray(ctx, s.db, s.videoFields, &res.List, pq.Array, query2, values...)
-	if er2 != nil {
+// getPlaylistItems falls back to the video ids of a playlist synced before items were stored.
+func (s *PostgreVideoService) getPlaylistItems(ctx context.Context, playlistId string) ([]video.PlaylistItem, error) {
+	query1 := `select * from playlistItem where playlistId = $1 and removedAt is null`
+	var items []video.PlaylistItem
+	er1 := QueryWithMapAndArray(ctx, s.db, nil, &items, pq.Array, query1, playlistId)
+	if er1 != nil || len(items) > 0 {
+		return items, er1
+	}
+	query2 := `select * from playlistVideo where id = $1`
+	var playlistVideos []video.PlaylistVideoIdVideos
+	er2 := QueryWithMapAndArray(ctx, s.db, nil, &playlistVideos, pq.Array, query2, playlistId)
+	if er2 != nil || len(playlistVideos) == 0 {
 		return nil, er2
 	}
-	lenList := len(res.List)
-	res.Total = lenList
-	res.Limit = max
-	r, err := strconv.Atoi(next)
-	if err != nil {
-		return nil, errors.New("invalid nextPageToken")
-	}
-	res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
-	return &res, nil
+	return video.NewPlaylistItems(playlistId, playlistVideos[0].Videos, nil), nil
 }
 
 func (s *PostgreVideoService) GetCategories(ctx context.Context, regionCode string) (*video.Categories, error) {
 	sql := `select * from category where id = $1`
 	var arrCategory []video.Categories
-	err := QueryWithMapAndArray(ctx, s.db, s.categoryFields, &arrCategory, pq.Array,sql , regionCode)
+	err := QueryWithMapAndArray(ctx, s.db, s.categoryFields, &arrCategory, pq.Array, sql, regionCode)
 	if err != nil {
 		return nil, err
 	}
 	category := arrCategory[0]
 	if category.Data == nil {
-		res, er1 := s.tubeCategory.GetCagetories(regionCode)
+		res, er1 := s.tubeCategory.GetCagetories(ctx, regionCode)
 		if er1 != nil {
 			return nil, er1
 		}
@@ -287,164 +300,297 @@ func (s *PostgreVideoService) GetCategories(ctx context.Context, regionCode stri
 }
 
 func (s *PostgreVideoService) SearchChannel(ctx context.Context, channelSM video.ChannelSM, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
-	next := getNext(nextPageToken)
-	query, statement := buildChannelQuery(channelSM, fields)
-	query = query + fmt.Sprintf(` limit %d offset %s`, max, next)
+	if err := checkFields(fields, s.channelFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	k, er0 := sortKeyset(channelSM.Sort, video.ChannelSortable, newTextSearch(channelSM.Q, channelSM.RelevanceLanguage).simple(), s.modelTypeChannel, s.channelFields)
+	if er0 != nil {
+		return nil, er0
+	}
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
+	}
+	query, statement, er2 := buildChannelQuery(channelSM, fields, k, c)
+	if er2 != nil {
+		return nil, er2
+	}
+	query = query + fmt.Sprintf(` limit %d`, max+1)
 	var listResultChannel video.ListResultChannel
-	err := QueryWithMapAndArray(ctx, s.db, s.channelFields, &listResultChannel.List, pq.Array, query, statement...)
+	ranks, err := k.query(ctx, s.db, &listResultChannel.List, pq.Array, query, statement...)
 	if err != nil {
 		return nil, err
 	}
 	listResultChannel.Limit = max
-	lenList := len(listResultChannel.List)
-	r, err := strconv.Atoi(next)
+	token, err := k.next(&listResultChannel.List, max, ranks...)
 	if err != nil {
-		return nil, errors.New("invalid nextPageToken")
-	}
-	if lenList > 0 {
-		listResultChannel.NextPageToken = createNextPageToken(lenList, max, r, listResultChannel.List[lenList-1].Id, "")
+		return nil, err
 	}
+	listResultChannel.NextPageToken = token
 	return &listResultChannel, nil
 }
 
 func (s *PostgreVideoService) SearchPlaylists(ctx context.Context, playlistSM video.PlaylistSM, max int, nextPageToken string, fields []string) (*video.ListResultPlaylist, error) {
-	next := getNext(nextPageToken)
-	query, statement := buildPlaylistQuery(playlistSM, fields)
-	query = query + fmt.Sprintf(` limit %d offset %s`, max, next)
+	if err := checkFields(fields, s.playlistFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	k, er0 := sortKeyset(playlistSM.Sort, video.PlaylistSortable, newTextSearch(playlistSM.Q, playlistSM.RelevanceLanguage).simple(), s.modelTypePlaylist, s.playlistFields)
+	if er0 != nil {
+		return nil, er0
+	}
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
+	}
+	query, statement, er2 := buildPlaylistQuery(playlistSM, fields, k, c)
+	if er2 != nil {
+		return nil, er2
+	}
+	query = query + fmt.Sprintf(` limit %d`, max+1)
 	var res video.ListResultPlaylist
-	err := QueryWithMap(ctx, s.db, s.playlistFields, &res.List, query, statement...)
+	ranks, err := k.query(ctx, s.db, &res.List, nil, query, statement...)
 	if err != nil {
 		return nil, err
 	}
-	lenList := len(res.List)
 	res.Limit = max
-	r, err := strconv.Atoi(next)
+	token, err := k.next(&res.List, max, ranks...)
 	if err != nil {
-		return nil, errors.New("nextPageToken wrong")
-	}
-	if lenList > 0 {
-		res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
+		return nil, err
 	}
-
+	res.NextPageToken = token
+	res.Total = len(res.List)
 	return &res, nil
 }
 
 func (s *PostgreVideoService) SearchVideos(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	next := getNext(nextPageToken)
-	query, statement := buildVideoQuery(itemSM, fields)
-	query = query + fmt.Sprintf(` limit %d offset %s`, max, next)
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	k, er0 := sortKeyset(itemSM.Sort, video.VideoSortable, newTextSearch(itemSM.Q, itemSM.RelevanceLanguage), s.modelTypeVideo, s.videoFields)
+	if er0 != nil {
+		return nil, er0
+	}
+	c, er1 := cursor.Decode(nextPageToken, k.name)
+	if er1 != nil {
+		return nil, er1
+	}
+	query, statement, er2 := buildVideoQuery(itemSM, video.IncludeUnavailable(ctx), fields, k, c)
+	if er2 != nil {
+		return nil, er2
+	}
+	query = query + fmt.Sprintf(` limit %d`, max+1)
 	var res video.ListResultVideos
-	err := QueryWithMapAndArray(ctx, s.db, s.videoFields, &res.List, pq.Array, query, statement...)
+	ranks, err := k.query(ctx, s.db, &res.List, pq.Array, query, statement...)
 	if err != nil {
 		return nil, err
 	}
-	lenList := len(res.List)
 	res.Limit = max
-	r, err := strconv.Atoi(next)
+	token, err := k.next(&res.List, max, ranks...)
 	if err != nil {
-		return nil, errors.New("nextPageToken wrong")
-	}
-	if lenList > 0 {
-		res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
+		return nil, err
 	}
+	res.NextPageToken = token
+	res.Total = len(res.List)
 	return &res, nil
 }
 
-func (s *PostgreVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	queryChannel, statementChannel := buildSearchUnionQuery("channel", itemSM, fields)
-	var channels []video.Video
-	var resChannel video.ListResultVideos
-	er1 := QueryWithMap(ctx, s.db, s.videoFields, &channels, queryChannel, statementChannel...)
+func (s *PostgreVideoService) Search(ctx context.Context, itemSM video.ItemSM, max int, nextPageToken string, fields []string) (*video.ListResultSearch, error) {
+	kinds, er0 := video.SearchKinds(itemSM)
+	if er0 != nil {
+		return nil, er0
+	}
+	keys, er1 := video.ParseSort(itemSM.Sort, video.SearchSortable, itemSM.Q)
 	if er1 != nil {
 		return nil, er1
 	}
-	resChannel.List = channels
-	resChannelList := resChannel.List
-
-	// ----------------------
-	queryPlaylist, statementPlaylist := buildSearchUnionQuery("playlist", itemSM, fields)
-	var playlists []video.Video
-	var resPlaylist video.ListResultVideos
-	er2 := QueryWithMap(ctx, s.db, s.videoFields, &playlists, queryPlaylist, statementPlaylist...)
+	if err := checkSearchFields(fields, s.channelFields, s.playlistFields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	search := newTextSearch(itemSM.Q, itemSM.RelevanceLanguage)
+	c, er2 := cursor.Decode(nextPageToken, video.FormatSort(keys))
 	if er2 != nil {
 		return nil, er2
 	}
-	resPlaylist.List = playlists
-	resPlaylistList := resPlaylist.List
-
-	// ----------------------
-	queryVideo, statementVideo := buildSearchUnionQuery("video", itemSM, fields)
-	var videos []video.Video
-	var resVideo video.ListResultVideos
-	er3 := QueryWithMap(ctx, s.db, s.videoFields, &videos, queryVideo, statementVideo...)
-	if er3 != nil {
-		return nil, er3
+	var k keyset
+	var rows []searchRow
+	for _, kind := range kinds {
+		var modelType reflect.Type
+		var fieldsIndex map[string]int
+		switch kind {
+		case video.KindChannel:
+			modelType, fieldsIndex = s.modelTypeChannel, s.channelFields
+		case video.KindPlaylist:
+			modelType, fieldsIndex = s.modelTypePlaylist, s.playlistFields
+		default:
+			modelType, fieldsIndex = s.modelTypeVideo, s.videoFields
+		}
+		kindSearch := search
+		if kind != video.KindVideo {
+			kindSearch = search.simple()
+		}
+		var er3 error
+		k, er3 = newKeyset(keys, kindSearch, modelType, fieldsIndex)
+		if er3 != nil {
+			return nil, er3
+		}
+		f := searchFields(fields, fieldsIndex)
+		var query string
+		var params []interface{}
+		var er4 error
+		switch kind {
+		case video.KindChannel:
+			query, params, er4 = buildChannelQuery(video.ToChannelSM(itemSM), f, k, c)
+		case video.KindPlaylist:
+			query, params, er4 = buildPlaylistQuery(video.ToPlaylistSM(itemSM), f, k, c)
+		default:
+			query, params, er4 = buildVideoQuery(itemSM, video.IncludeUnavailable(ctx), f, k, c)
+		}
+		if er4 != nil {
+			return nil, er4
+		}
+		query = query + fmt.Sprintf(` limit %d`, max+1)
+		list := reflect.New(reflect.SliceOf(modelType))
+		ranks, er5 := k.query(ctx, s.db, list.Interface(), pq.Array, query, params...)
+		if er5 != nil {
+			return nil, er5
+		}
+		for i := 0; i < list.Elem().Len(); i++ {
+			row := list.Elem().Index(i)
+			rows = append(rows, searchRow{result: searchResult(kind, row), values: k.values(row, ranks[i]), id: row.Field(fieldsIndex["id"]).String()})
+		}
 	}
-	resVideo.List = videos
-	resVideoList := resVideo.List
-	var result, combine1 []video.Video
-	combine1 = append(resPlaylistList, resVideoList...)
-	result = append(resChannelList, combine1...)
-	var res video.ListResultVideos
-	res.List = result
-	lenList := len(res.List)
-	res.Limit = max
-	next := getNext(nextPageToken)
-	r, err := strconv.Atoi(next)
-	if err != nil {
-		return nil, errors.New("nextPageToken wrong")
+	sort.SliceStable(rows, func(i, j int) bool {
+		return k.before(rows[i].values, rows[i].id, rows[j].values, rows[j].id)
+	})
+	res := video.ListResultSearch{Limit: max}
+	if max > 0 && len(rows) > max {
+		rows = rows[:max]
+		token, err := k.token(rows[max-1].values, rows[max-1].id)
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
 	}
-	if lenList > 0 {
-		res.NextPageToken = createNextPageToken(lenList, max, r, res.List[lenList-1].Id, "")
+	res.List = make([]video.SearchResult, len(rows))
+	for i, row := range rows {
+		res.List[i] = row.result
 	}
+	res.Total = len(res.List)
 	return &res, nil
 }
 
+// GetRelatedVideos keeps the time of the ranking in the page token, so every page ranks alike.
 func (s *PostgreVideoService) GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	next := getNext(nextPageToken)
-	var a []string
-	resVd, err := s.GetVideo(ctx, videoId, a)
-	if err != nil {
+	if err := checkFields(fields, s.videoFields); err != nil {
 		return nil, err
 	}
-	var result video.ListResultVideos
-	if resVd == nil {
-		return nil, errors.New("video doesn't exist")
-	} else {
-		if len(resVd.Tags) == 0 {
-			return nil, errors.New("video doesn't have any tag")
-		} else {
-			query, statement := buildRelatedVideoQuery(videoId, resVd.Tags, fields)
-			query = query + fmt.Sprintf(` limit %d offset %s`, max, next)
-			var arrRes []video.Video
-			err := QueryWithMapAndArray(ctx, s.db, s.videoFields, &arrRes, pq.Array, query, statement...)
-			if err != nil {
-				return nil, err
-			}
-			result.List = arrRes
-			lenList := len(arrRes)
-			if lenList == 0 {
-				return nil, errors.New("there is no related video")
-			}
-			result.List = arrRes
-			result.Limit = max
-			r, err := strconv.Atoi(next)
-			if err != nil {
-				return nil, errors.New("nextPageToken wrong")
-			}
-			if lenList > 0 {
-				result.NextPageToken = createNextPageToken(lenList, max, r, result.List[lenList-1].Id, "")
-			}
-		}
-	}
-
-	return &result, nil
+	c, er0 := cursor.Decode(nextPageToken, "related")
+	if er0 != nil {
+		return nil, er0
+	}
+	now := time.Now().UTC()
+	var lastScore float64
+	if c != nil {
+		if c.Since == nil || len(c.Values) != 1 {
+			return nil, cursor.ErrInvalid
+		}
+		v, err := cursor.Parse(c.Values[0], float64Type)
+		if v == nil || err != nil {
+			return nil, cursor.ErrInvalid
+		}
+		now = *c.Since
+		lastScore = v.(float64)
+	}
+	seed, er1 := s.GetVideo(ctx, videoId, video.RelatedFields)
+	if er1 != nil {
+		return nil, er1
+	}
+	if seed == nil {
+		return nil, nil
+	}
+	query, statement := buildRelatedVideoQuery(*seed, video.IncludeUnavailable(ctx))
+	var candidates []video.Video
+	er2 := QueryWithMapAndArray(ctx, s.db, s.videoFields, &candidates, pq.Array, query, statement...)
+	if er2 != nil {
+		return nil, er2
+	}
+	ranked := video.RankRelated(*seed, candidates, now, video.DefaultRelatedWeights)
+	if max <= 0 {
+		max = 12
+	}
+	res := video.ListResultVideos{Limit: max}
+	skip := 0
+	if c != nil {
+		skip = video.RelatedAfter(ranked, lastScore, c.Id)
+	}
+	if skip >= len(ranked) {
+		return &res, nil
+	}
+	end := skip + max
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	page := ranked[skip:end]
+	ids := make([]string, len(page))
+	for i, r := range page {
+		ids[i] = r.Video.Id
+	}
+	if len(fields) > 0 {
+		fields = append(fields, "id")
+	}
+	videos, er3 := s.GetVideos(ctx, ids, fields)
+	if er3 != nil {
+		return nil, er3
+	}
+	byId := make(map[string]video.Video)
+	for _, v := range *videos {
+		byId[v.Id] = v
+	}
+	for _, id := range ids {
+		if v, ok := byId[id]; ok {
+			res.List = append(res.List, v)
+		}
+	}
+	if end < len(ranked) {
+		last := page[len(page)-1]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "related", Values: []interface{}{cursor.Value(reflect.ValueOf(last.Score))}, Id: last.Video.Id, Since: &now})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	return &res, nil
 }
 
 func (s *PostgreVideoService) GetPopularVideos(ctx context.Context, regionCode string, categoryId string, limit int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
-	next := getNext(nextPageToken)
-	query, statement := buildPopularVideoQuery(regionCode, categoryId, fields)
-	query = query + fmt.Sprintf(` limit %d offset %s`, limit, next)
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 12
+	}
+	k, _ := newKeyset([]video.SortKey{{Field: "viewCount", Desc: true}, {Field: "publishedAt", Desc: true}}, textSearch{}, s.modelTypeVideo, s.videoFields)
+	c, er0 := cursor.Decode(nextPageToken, k.name)
+	if er0 != nil {
+		return nil, er0
+	}
+	query, statement, er1 := buildPopularVideoQuery(regionCode, categoryId, video.IncludeUnavailable(ctx), fields, k, c)
+	if er1 != nil {
+		return nil, er1
+	}
+	query = query + fmt.Sprintf(` limit %d`, limit+1)
 	var videos []video.Video
 	err := QueryWithMapAndArray(ctx, s.db, s.videoFields, &videos, pq.Array, query, statement...)
 	if err != nil {
@@ -452,48 +598,203 @@ func (s *PostgreVideoService) GetPopularVideos(ctx context.Context, regionCode s
 	}
 	var res video.ListResultVideos
 	res.List = videos
-	lenList := len(videos)
 	res.Limit = limit
-	r, err := strconv.Atoi(next)
+	token, err := k.next(&res.List, limit)
 	if err != nil {
-		return nil, errors.New("invalid nextPageToken")
-	}
-	if lenList > 0 {
-		res.NextPageToken = createNextPageToken(lenList, limit, r, res.List[lenList-1].Id, "")
+		return nil, err
 	}
-
+	res.NextPageToken = token
 	return &res, nil
 }
 
-func getNext(nextPageToken string) (next string) {
-	if len(nextPageToken) > 0 {
-		next = strings.Split(nextPageToken, "|")[0]
-	} else {
-		next = "0"
+// GetTrendingVideos keeps the start of the window in the page token, so every page ranks the same snapshots.
+func (s *PostgreVideoService) GetTrendingVideos(ctx context.Context, regionCode string, categoryId string, window time.Duration, limit int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 12
+	}
+	c, er0 := cursor.Decode(nextPageToken, "trending")
+	if er0 != nil {
+		return nil, er0
+	}
+	since := time.Now().Add(-window).UTC()
+	if c != nil {
+		if c.Since == nil {
+			return nil, cursor.ErrInvalid
+		}
+		since = *c.Since
+	}
+	query, statement, er1 := buildTrendingVideoQuery(regionCode, categoryId, video.IncludeUnavailable(ctx), since, fields, c)
+	if er1 != nil {
+		return nil, er1
+	}
+	query = query + fmt.Sprintf(` limit %d`, limit+1)
+	var videos []video.Video
+	k := keyset{fieldsIndex: s.videoFields}
+	velocities, er2 := k.query(ctx, s.db, &videos, pq.Array, query, statement...)
+	if er2 != nil {
+		return nil, er2
+	}
+	var res video.ListResultVideos
+	res.List = videos
+	res.Limit = limit
+	if limit > 0 && len(videos) > limit {
+		res.List = videos[:limit]
+		velocity := velocities[limit-1]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "trending", Values: []interface{}{cursor.Value(reflect.ValueOf(velocity))}, Id: videos[limit-1].Id, Since: &since})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
 	}
-	return
+	return &res, nil
 }
 
-func createNextPageToken(lenList int, limit int, skip int, id string, name string) string {
-	if len(name) <= 0 {
-		name = "id"
+func (s *PostgreVideoService) GetChannelSubscriptions(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := checkFields(fields, s.channelFields); err != nil {
+		return nil, err
 	}
-	if lenList < limit {
-		return ""
-	} else {
-		if lenList > 0 {
-			return fmt.Sprintf(`%d|%s`, skip+limit, id)
+	if max <= 0 {
+		max = 12
+	}
+	c, er0 := cursor.Decode(nextPageToken, "subscriptions")
+	if er0 != nil {
+		return nil, er0
+	}
+	query := `select * from subscription where subscriberId = $1`
+	params := []interface{}{channelId}
+	if c != nil {
+		params = append(params, c.Id)
+		query += ` and channelId > $2`
+	}
+	query += fmt.Sprintf(` order by channelId limit %d`, max+1)
+	var subscriptions []video.Subscription
+	er1 := QueryWithMapAndArray(ctx, s.db, s.subscriptionFields, &subscriptions, pq.Array, query, params...)
+	if er1 != nil {
+		return nil, er1
+	}
+	res := video.ListResultChannel{Limit: max}
+	if max > 0 && len(subscriptions) > max {
+		subscriptions = subscriptions[:max]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "subscriptions", Id: subscriptions[max-1].ChannelId})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	ids := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ChannelId
+	}
+	if len(fields) > 0 {
+		fields = append(fields, "id")
+	}
+	channels, er2 := s.GetChannels(ctx, ids, fields)
+	if er2 != nil {
+		return nil, er2
+	}
+	byId := make(map[string]video.Channel)
+	for _, channel := range *channels {
+		byId[channel.Id] = channel
+	}
+	for _, subscription := range subscriptions {
+		if channel, ok := byId[subscription.ChannelId]; ok {
+			res.List = append(res.List, channel)
 		} else {
-			return ""
+			res.List = append(res.List, video.SubscriptionChannel(subscription, fields))
 		}
 	}
+	res.Total = len(res.List)
+	return &res, nil
 }
 
-func buildChannelQuery(s video.ChannelSM, fields []string) (string, []interface{}) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
+func (s *PostgreVideoService) GetChannelSubscribers(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*video.ListResultChannel, error) {
+	if err := checkFields(fields, s.channelFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	k := keyset{name: "subscribers", modelType: s.modelTypeChannel, fieldsIndex: s.channelFields}
+	c, er0 := cursor.Decode(nextPageToken, k.name)
+	if er0 != nil {
+		return nil, er0
+	}
+	query := fmt.Sprintf(`select %s from channel where id in (select subscriberId from subscription where channelId = $1)`, strings.Join(k.project(fields), ","))
+	params := []interface{}{channelId}
+	if c != nil {
+		cond, values, er1 := k.where(c, 2)
+		if er1 != nil {
+			return nil, er1
+		}
+		query += ` and ` + cond
+		params = append(params, values...)
+	}
+	query += k.orderBy() + fmt.Sprintf(` limit %d`, max+1)
+	var res video.ListResultChannel
+	er2 := QueryWithMapAndArray(ctx, s.db, s.channelFields, &res.List, pq.Array, query, params...)
+	if er2 != nil {
+		return nil, er2
 	}
-	query := fmt.Sprintf(`select %s from channel`, strings.Join(fields, ","))
+	res.Limit = max
+	token, err := k.next(&res.List, max)
+	if err != nil {
+		return nil, err
+	}
+	res.NextPageToken = token
+	res.Total = len(res.List)
+	return &res, nil
+}
+
+func (s *PostgreVideoService) GetSubscriptionVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*video.ListResultVideos, error) {
+	if err := checkFields(fields, s.videoFields); err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = 12
+	}
+	k, _ := sortKeyset("", video.VideoSortable, textSearch{}, s.modelTypeVideo, s.videoFields)
+	c, er0 := cursor.Decode(nextPageToken, k.name)
+	if er0 != nil {
+		return nil, er0
+	}
+	query := fmt.Sprintf(`select %s from video where channelId in (select channelId from subscription where subscriberId = $1)`, strings.Join(k.project(fields), ","))
+	params := []interface{}{channelId}
+	if len(regionCode) > 0 {
+		params = append(params, video.RegionCode(regionCode))
+		query += ` and ` + available("", len(params))
+	}
+	if !video.IncludeUnavailable(ctx) {
+		query += ` and ` + listed("")
+	}
+	if c != nil {
+		cond, values, er1 := k.where(c, len(params)+1)
+		if er1 != nil {
+			return nil, er1
+		}
+		query += ` and ` + cond
+		params = append(params, values...)
+	}
+	query += k.orderBy() + fmt.Sprintf(` limit %d`, max+1)
+	var res video.ListResultVideos
+	er2 := QueryWithMapAndArray(ctx, s.db, s.videoFields, &res.List, pq.Array, query, params...)
+	if er2 != nil {
+		return nil, er2
+	}
+	res.Limit = max
+	token, err := k.next(&res.List, max)
+	if err != nil {
+		return nil, err
+	}
+	res.NextPageToken = token
+	res.Total = len(res.List)
+	return &res, nil
+}
+
+func buildChannelQuery(s video.ChannelSM, fields []string, k keyset, c *cursor.Cursor) (string, []interface{}, error) {
+	query := fmt.Sprintf(`select %s from channel`, strings.Join(k.project(fields), ","))
 	var condition []string
 	var params []interface{}
 	i := 1
@@ -509,36 +810,36 @@ func buildChannelQuery(s video.ChannelSM, fields []string) (string, []interface{
 	}
 	if s.PublishedAfter != nil {
 		params = append(params, s.PublishedAfter)
-		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt >= $%d`, i))
 		i++
 	}
 	if s.PublishedBefore != nil {
 		params = append(params, s.PublishedBefore)
-		condition = append(condition, fmt.Sprintf(`publishedAt > $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
 		i++
 	}
 	if len(s.Q) > 0 {
-		q := "%" + s.Q + "%"
-		params = append(params, q, q)
-		condition = append(condition, fmt.Sprintf(`(title ilike $%d or description ilike $%d)`, i, i+1))
+		condition = append(condition, k.search.match())
+	}
+	if c != nil {
+		cond, values, err := k.where(c, i)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, values...)
+		condition = append(condition, cond)
 	}
 
 	if len(condition) > 0 {
 		cond := strings.Join(condition, " and ")
 		query += fmt.Sprintf(` where %s`, cond)
 	}
-	if len(s.Sort) > 0 {
-		query += fmt.Sprintf(` order by %s desc`, s.Sort)
-	}
-	return query, params
+	query += k.orderBy()
+	return query, params, nil
 }
 
-func buildPlaylistQuery(s video.PlaylistSM, fields []string) (string, []interface{}) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
-
-	query := fmt.Sprintf(`select %s from playlist`, strings.Join(fields, ","))
+func buildPlaylistQuery(s video.PlaylistSM, fields []string, k keyset, c *cursor.Cursor) (string, []interface{}, error) {
+	query := fmt.Sprintf(`select %s from playlist`, strings.Join(k.project(fields), ","))
 	var condition []string
 	var params []interface{}
 	i := 1
@@ -550,37 +851,36 @@ func buildPlaylistQuery(s video.PlaylistSM, fields []string) (string, []interfac
 	}
 	if s.PublishedAfter != nil {
 		params = append(params, s.PublishedAfter)
-		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt >= $%d`, i))
 		i++
 	}
 	if s.PublishedBefore != nil {
 		params = append(params, s.PublishedBefore)
-		condition = append(condition, fmt.Sprintf(`publishedAt > $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
 		i++
 	}
 	if len(s.Q) > 0 {
-		q := "%" + s.Q + "%"
-		params = append(params, q, q)
-		condition = append(condition, fmt.Sprintf(`(title ilike $%d or description ilike $%d)`, i, i+1))
+		condition = append(condition, k.search.match())
+	}
+	if c != nil {
+		cond, values, err := k.where(c, i)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, values...)
+		condition = append(condition, cond)
 	}
 
 	if len(condition) > 0 {
 		cond := strings.Join(condition, " and ")
 		query += fmt.Sprintf(` where %s`, cond)
 	}
-
-	if len(s.Sort) > 0 {
-		query += fmt.Sprintf(` order by %s desc`, s.Sort)
-	}
-
-	return query, params
+	query += k.orderBy()
+	return query, params, nil
 }
 
-func buildVideoQuery(s video.ItemSM, fields []string) (string, []interface{}) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
-	query := fmt.Sprintf(`select %s from video`, strings.Join(fields, ","))
+func buildVideoQuery(s video.ItemSM, unavailable bool, fields []string, k keyset, c *cursor.Cursor) (string, []interface{}, error) {
+	query := fmt.Sprintf(`select %s from video`, strings.Join(k.project(fields), ","))
 	var condition []string
 	var params []interface{}
 	i := 1
@@ -592,24 +892,94 @@ func buildVideoQuery(s video.ItemSM, fields []string) (string, []interface{}) {
 	}
 	if s.PublishedAfter != nil {
 		params = append(params, s.PublishedAfter)
-		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt >= $%d`, i))
 		i++
 	}
 	if s.PublishedBefore != nil {
 		params = append(params, s.PublishedBefore)
-		condition = append(condition, fmt.Sprintf(`publishedAt > $%d`, i))
+		condition = append(condition, fmt.Sprintf(`publishedAt <= $%d`, i))
 		i++
 	}
 	if len(s.RegionCode) > 0 {
-		params = append(params, s.RegionCode)
-		condition = append(condition, fmt.Sprintf(`(blockedRegions is null or $%d != all(blockedRegions))`, i))
-		//https://popsql.com/learn-sql/postgresql/how-to-query-arrays-in-postgresql
+		params = append(params, video.RegionCode(s.RegionCode))
+		condition = append(condition, available("", i))
 		i++
 	}
+	if !unavailable {
+		condition = append(condition, listed(""))
+	}
 	if len(s.Q) > 0 {
-		q := "%" + s.Q + "%"
-		params = append(params, q, q)
-		condition = append(condition, fmt.Sprintf(`(title ilike $%d or description ilike $%d)`, i, i+1))
+		condition = append(condition, k.search.match())
+	}
+	if len(s.CategoryId) > 0 {
+		params = append(params, s.CategoryId)
+		condition = append(condition, fmt.Sprintf(`categoryId = $%d`, i))
+		i++
+	}
+	if category, ok := video.VideoTypeCategories[s.VideoType]; ok {
+		params = append(params, category)
+		condition = append(condition, fmt.Sprintf(`categoryId = $%d`, i))
+		i++
+	}
+	switch s.Caption {
+	case "closedCaption":
+		condition = append(condition, `caption = 'true'`)
+	case "none":
+		condition = append(condition, `(caption is null or caption <> 'true')`)
+	}
+	switch s.Definition {
+	case "high":
+		condition = append(condition, `definition = 5`)
+	case "standard":
+		condition = append(condition, `(definition is null or definition <> 5)`)
+	}
+	if s.Dimension == "2d" || s.Dimension == "3d" {
+		params = append(params, s.Dimension)
+		condition = append(condition, fmt.Sprintf(`dimension = $%d`, i))
+		i++
+	}
+	switch s.EventType {
+	case "live", "upcoming":
+		params = append(params, s.EventType)
+		condition = append(condition, fmt.Sprintf(`liveBroadcastContent = $%d`, i))
+		i++
+	case "completed":
+		condition = append(condition, `(liveBroadcastContent is null or liveBroadcastContent not in ('live', 'upcoming'))`)
+	}
+	if len(s.License) > 0 && s.License != "any" {
+		params = append(params, s.License)
+		condition = append(condition, fmt.Sprintf(`license = $%d`, i))
+		i++
+	}
+	if s.Embeddable == "true" {
+		condition = append(condition, `embeddable = true`)
+	}
+	if s.SafeSearch == "moderate" || s.SafeSearch == "strict" {
+		params = append(params, video.AgeRestricted)
+		condition = append(condition, fmt.Sprintf(`(ytRating is null or ytRating <> $%d)`, i))
+		i++
+	}
+	if len(s.TopicId) > 0 {
+		params = append(params, s.TopicId)
+		condition = append(condition, fmt.Sprintf(`$%d = any(topicIds)`, i))
+		i++
+	}
+	if len(s.RelevanceLanguage) > 0 {
+		params = append(params, strings.ToLower(s.RelevanceLanguage))
+		language := func(column string) string {
+			return fmt.Sprintf(`lower(coalesce(%s, ''))`, column)
+		}
+		matches := func(column string) string {
+			return fmt.Sprintf(`%s = $%d or starts_with(%s, $%d || '-')`, language(column), i, language(column), i)
+		}
+		condition = append(condition, fmt.Sprintf(`(%s or %s or (%s = '' and %s = ''))`,
+			matches("defaultLanguage"), matches("defaultAudioLanguage"), language("defaultLanguage"), language("defaultAudioLanguage")))
+		i++
+	}
+	if len(s.RelatedToVideoId) > 0 {
+		params = append(params, s.RelatedToVideoId)
+		condition = append(condition, fmt.Sprintf(`id <> $%d and tags && (select tags from video where id = $%d)`, i, i))
+		i++
 	}
 	if len(s.Duration) > 0 {
 		var compare string
@@ -627,58 +997,62 @@ func buildVideoQuery(s video.ItemSM, fields []string) (string, []interface{}) {
 			condition = append(condition, compare)
 		}
 	}
+	if c != nil {
+		cond, values, err := k.where(c, i)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, values...)
+		condition = append(condition, cond)
+	}
 
 	if len(condition) > 0 {
 		cond := strings.Join(condition, " and ")
 		query += fmt.Sprintf(` where %s`, cond)
 	}
-
-	if len(s.Sort) > 0 {
-		query += fmt.Sprintf(` order by %s desc`, s.Sort)
-	}
-
-	return query, params
+	query += k.orderBy()
+	return query, params, nil
 }
 
-func buildRelatedVideoQuery(videoId string, tags []string, fields []string) (string, []interface{}) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
-
-	var finalsql string
-	var condition []string
-	var params []interface{}
-	i := 1
-
-	finalsql = fmt.Sprintf(`select %s from video`, strings.Join(fields, ","))
-
-	if len(tags) == 1 {
-		finalsql += fmt.Sprintf(` where id <> $%d and $%d = any (tags)`, i, i+1)
-		params = append(params, videoId, tags[0])
-	} else {
-		finalsql += fmt.Sprintf(` where id <> $%d`, i)
-		params = append(params, videoId)
-		i++
-		for _, value := range tags {
-			params = append(params, value)
-			condition = append(condition, fmt.Sprintf(`$%d = any (tags)`, i))
-			i++
-		}
-		if len(condition) > 0 {
-			cond := strings.Join(condition, " or ")
-			finalsql += fmt.Sprintf(` and (%s)`, cond)
+// buildRelatedVideoQuery reads at most video.RelatedCandidates videos for each signal.
+func buildRelatedVideoQuery(seed video.Video, unavailable bool) (string, []interface{}) {
+	params := []interface{}{seed.Id}
+	where := `id <> $1`
+	if !unavailable {
+		where += ` and ` + listed("")
+	}
+	var selects []string
+	signal := func(condition string, value interface{}) {
+		params = append(params, value)
+		selects = append(selects, fmt.Sprintf(`(select %s from video where %s and %s order by publishedAt desc nulls last, id limit %d)`,
+			strings.Join(video.RelatedFields, ","), where, fmt.Sprintf(condition, len(params)), video.RelatedCandidates))
+	}
+	if len(seed.Tags) > 0 {
+		signal(`tags && $%d`, pq.Array(seed.Tags))
+	}
+	if len(seed.ChannelId) > 0 {
+		signal(`channelId = $%d`, seed.ChannelId)
+	}
+	if len(seed.CategoryId) > 0 {
+		signal(`categoryId = $%d`, seed.CategoryId)
+	}
+	if terms := video.TitleTerms(seed.Title); len(terms) > 0 {
+		patterns := make([]string, len(terms))
+		for j, term := range terms {
+			patterns[j] = "%" + likeEscaper.Replace(term) + "%"
 		}
+		signal(`lower(title) like any ($%d)`, pq.Array(patterns))
 	}
-
-	return finalsql, params
+	if len(selects) == 0 {
+		return fmt.Sprintf(`select %s from video where false`, strings.Join(video.RelatedFields, ",")), nil
+	}
+	return strings.Join(selects, " union "), params
 }
 
-func buildPopularVideoQuery(regionCode string, categoryId string, fields []string) (string, []interface{}) {
-	if len(fields) <= 0 {
-		fields = append(fields, "*")
-	}
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
-	query := fmt.Sprintf(`select %s from video`, strings.Join(fields, ","))
+func buildPopularVideoQuery(regionCode string, categoryId string, unavailable bool, fields []string, k keyset, c *cursor.Cursor) (string, []interface{}, error) {
+	query := fmt.Sprintf(`select %s from video`, strings.Join(k.project(fields), ","))
 	var condition []string
 	var params []interface{}
 	i := 1
@@ -689,48 +1063,78 @@ func buildPopularVideoQuery(regionCode string, categoryId string, fields []strin
 		i++
 	}
 	if len(regionCode) > 0 {
-		params = append(params, regionCode)
-		condition = append(condition, fmt.Sprintf(`blockedRegions is null or $%d <> ALL (blockedRegions)`, i))
+		params = append(params, video.RegionCode(regionCode))
+		condition = append(condition, available("", i))
 		i++
 	}
+	if !unavailable {
+		condition = append(condition, listed(""))
+	}
+	if c != nil {
+		cond, values, err := k.where(c, i)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, values...)
+		condition = append(condition, cond)
+	}
 
 	if len(condition) > 0 {
 		cond := strings.Join(condition, " and ")
 		query += fmt.Sprintf(` where %s`, cond)
 	}
-
-	return query, params
+	query += k.orderBy()
+	return query, params, nil
 }
 
-func buildSearchUnionQuery(searchType string, s video.ItemSM, fields []string) (string, []interface{}) {
-	channelString := `id, title, description, publishedAt`
-	playlistString := `id, channelId, channelTitle, title, description, count, publishedAt`
-	videoString := "id, channelId, channelTitle, title, description, duration, publishedAt"
-
-	if len(fields) <= 0 && searchType == "channel" {
-		fields = append(fields, channelString)
-	} else if len(fields) <= 0 && searchType == "playlist" {
-		fields = append(fields, playlistString)
-	} else if len(fields) <= 0 && searchType == "video" {
-		fields = append(fields, videoString)
+// buildTrendingVideoQuery reads the velocity back as the rank column.
+func buildTrendingVideoQuery(regionCode string, categoryId string, unavailable bool, since time.Time, fields []string, c *cursor.Cursor) (string, []interface{}, error) {
+	if len(fields) <= 0 {
+		fields = append(fields, "*")
+	} else {
+		fields = append(fields, "id")
+	}
+	params := []interface{}{since}
+	fromNew := `v.publishedAt >= $1 and v.publishedAt < f.timestamp`
+	from := fmt.Sprintf(`case when %s then v.publishedAt else f.timestamp end`, fromNew)
+	views := fmt.Sprintf(`case when %s then 0 else f.viewCount end`, fromNew)
+	snapshot := `select distinct on (videoId) videoId, timestamp, viewCount from videoStatistics where timestamp >= $1 and viewCount is not null order by videoId, timestamp`
+	condition := []string{fmt.Sprintf(`l.timestamp > %s`, from)}
+	i := 2
+	if len(categoryId) > 0 {
+		params = append(params, categoryId)
+		condition = append(condition, fmt.Sprintf(`v.categoryId = $%d`, i))
+		i++
 	}
-
-	query := fmt.Sprintf(`select %s from %s`, strings.Join(fields, ","), searchType)
-	var condition []string
-	var params []interface{}
-	i := 1
-
-	if len(s.Q) > 0 {
-		q := "%" + s.Q + "%"
-		params = append(params, q, q)
-		condition = append(condition, fmt.Sprintf(`title ilike $%d or description ilike $%d`, i, i+1))
+	if len(regionCode) > 0 {
+		params = append(params, video.RegionCode(regionCode))
+		condition = append(condition, available("v.", i))
 		i++
 	}
-
-	if len(condition) > 0 {
-		cond := strings.Join(condition, " and ")
-		query += fmt.Sprintf(` where %s`, cond)
+	if !unavailable {
+		condition = append(condition, listed("v."))
+	}
+	query := fmt.Sprintf(`with t as (select v.*, cast((l.viewCount - %s) * 3600 / extract(epoch from l.timestamp - %s) as double precision) as velocity from video v join (%s) f on f.videoId = v.id join (%s desc) l on l.videoId = v.id where %s) select %s, velocity as rank from t`,
+		views, from, snapshot, snapshot, strings.Join(condition, " and "), strings.Join(fields, ","))
+	if c != nil {
+		if len(c.Values) != 1 {
+			return "", nil, cursor.ErrInvalid
+		}
+		v, err := cursor.Parse(c.Values[0], float64Type)
+		if v == nil || err != nil {
+			return "", nil, cursor.ErrInvalid
+		}
+		params = append(params, v, c.Id)
+		query += fmt.Sprintf(` where velocity < $%d or (velocity = $%d and %s > $%d)`, i, i, byteId, i+1)
 	}
+	query += ` order by velocity desc, ` + byteId
+	return query, params, nil
+}
+
+func available(prefix string, i int) string {
+	return fmt.Sprintf(`(case when cardinality(%sallowedRegions) > 0 then $%d = any(%sallowedRegions) else not coalesce($%d = any(%sblockedRegions), false) end)`, prefix, i, prefix, i, prefix)
+}
 
-	return query, params
+func listed(prefix string) string {
+	return fmt.Sprintf(`coalesce(%sstatus, '') = ''`, prefix)
 }
@@ -0,0 +1,114 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/category"
+	"github.com/core-go/video/cursor"
+	initpg "github.com/core-go/video/init-pg"
+	syncpg "github.com/core-go/video/sync-pg"
+	"github.com/core-go/video/videotest"
+)
+
+func init() {
+	cursor.SetKey([]byte("test key"))
+}
+
+// testSchema is dropped and created again for each test.
+const testSchema = "videotest"
+
+// openTestDB connects to the database VIDEO_TEST_POSTGRES names, with testSchema empty and migrated, or skips the test.
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("VIDEO_TEST_POSTGRES")
+	if len(dsn) == 0 {
+		t.Skip("VIDEO_TEST_POSTGRES is not set")
+	}
+	ctx := context.Background()
+	db, er0 := sql.Open("postgres", dsn)
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	t.Cleanup(func() { db.Close() })
+	// one connection, so the search_path set below applies to every query
+	db.SetMaxOpenConns(1)
+	if _, er1 := db.ExecContext(ctx, "drop schema if exists "+testSchema+" cascade"); er1 != nil {
+		t.Fatal(er1)
+	}
+	if _, er2 := initpg.Initialize(ctx, db, testSchema); er2 != nil {
+		t.Fatal(er2)
+	}
+	if _, er3 := db.ExecContext(ctx, "set search_path to "+testSchema); er3 != nil {
+		t.Fatal(er3)
+	}
+	return db
+}
+
+func newTestBackend(t *testing.T) (*sql.DB, *PostgreVideoService, *syncpg.PostgreVideoRepository) {
+	db := openTestDB(t)
+	service, er0 := NewPostgreVideoService(db, category.CategorySyncClient{})
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	repository, er1 := syncpg.NewPostgreVideoRepository(db)
+	if er1 != nil {
+		t.Fatal(er1)
+	}
+	return db, service, repository
+}
+
+func TestVideoService(t *testing.T) {
+	videotest.RunVideoServiceSuite(t, func(t *testing.T) (video.VideoService, video.SyncRepository) {
+		_, service, repository := newTestBackend(t)
+		return service, repository
+	})
+}
+
+func TestSyncRepository(t *testing.T) {
+	videotest.RunSyncRepositorySuite(t, func(t *testing.T) video.SyncRepository {
+		_, _, repository := newTestBackend(t)
+		return repository
+	})
+}
+
+func TestBackend(t *testing.T) {
+	videotest.RunBackendSuite(t, func(t *testing.T) videotest.Backend {
+		db, service, repository := newTestBackend(t)
+		playlistItems, er0 := syncpg.NewPostgrePlaylistItemRepository(db)
+		if er0 != nil {
+			t.Fatal(er0)
+		}
+		checkpoints, er1 := syncpg.NewPostgreCheckpointRepository(db)
+		if er1 != nil {
+			t.Fatal(er1)
+		}
+		return videotest.Backend{
+			Service:       service,
+			Repository:    repository,
+			Statistics:    syncpg.NewPostgreStatisticsRepository(db),
+			Subscriptions: syncpg.NewPostgreSubscriptionRepository(db),
+			PlaylistItems: playlistItems,
+			Tombstones:    syncpg.NewPostgreTombstoneRepository(db),
+			Checkpoints:   checkpoints,
+		}
+	})
+}
+
+func TestBuildRelatedVideoQuery(t *testing.T) {
+	seed := video.Video{Id: "vid1", Tags: []string{"go"}, ChannelId: "chan1", CategoryId: "28", Title: "Go tutorial"}
+	query, params := buildRelatedVideoQuery(seed, false)
+	if n := strings.Count(query, fmt.Sprintf("limit %d", video.RelatedCandidates)); n != 4 {
+		t.Errorf("%d signals limited; want 4 in %s", n, query)
+	}
+	if len(params) != 5 {
+		t.Errorf("%d params; want 5", len(params))
+	}
+	if escaped := likeEscaper.Replace(`50%_off\`); escaped != `50\%\_off\\` {
+		t.Errorf("escaped = %s", escaped)
+	}
+}
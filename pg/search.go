@@ -0,0 +1,95 @@
+package pg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/core-go/video"
+	syncpg "github.com/core-go/video/sync-pg"
+)
+
+// textSearch parses q with websearch_to_tsquery.
+type textSearch struct {
+	q      string
+	config string
+}
+
+func newTextSearch(q string, language string) textSearch {
+	return textSearch{q: q, config: syncpg.SearchConfig(language)}
+}
+
+// simple is for channels and playlists, whose vectors are built with "simple".
+func (t textSearch) simple() textSearch {
+	t.config = "simple"
+	return t
+}
+
+func (t textSearch) query() string {
+	return fmt.Sprintf(`websearch_to_tsquery(%s, %s)`, pq.QuoteLiteral(t.config), pq.QuoteLiteral(t.q))
+}
+
+func (t textSearch) match() string {
+	return `searchVector @@ ` + t.query()
+}
+
+func (t textSearch) rank() string {
+	return fmt.Sprintf(`ts_rank(searchVector, %s)`, t.query())
+}
+
+func (t textSearch) headline() string {
+	text := `coalesce(title, '') || ' ' || coalesce(description, '')`
+	return fmt.Sprintf(`ts_headline(%s, %s, %s, 'StartSel=<b>, StopSel=</b>, MaxFragments=2')`, pq.QuoteLiteral(t.config), text, t.query())
+}
+
+type searchRow struct {
+	result video.SearchResult
+	values []interface{}
+	id     string
+}
+
+func searchResult(kind string, row reflect.Value) video.SearchResult {
+	result := video.SearchResult{Kind: kind}
+	switch item := row.Interface().(type) {
+	case video.Channel:
+		result.Channel = &item
+	case video.Playlist:
+		result.Playlist = &item
+	case video.Video:
+		result.Video = &item
+	}
+	return result
+}
+
+func checkSearchFields(fields []string, fieldsIndexes ...map[string]int) error {
+	for _, field := range fields {
+		found := false
+		for _, fieldsIndex := range fieldsIndexes {
+			if _, ok := fieldsIndex[strings.ToLower(field)]; ok {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s", video.ErrInvalidField, field)
+		}
+	}
+	return nil
+}
+
+func searchFields(fields []string, fieldsIndex map[string]int) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	res := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := fieldsIndex[strings.ToLower(field)]; ok {
+			res = append(res, field)
+		}
+	}
+	if len(res) == 0 {
+		res = append(res, "id")
+	}
+	return res
+}
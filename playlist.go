@@ -18,6 +18,7 @@ type Playlist struct {
 	Title                string     `mapstructure:"title" json:"title,omitempty" gorm:"column:title" bson:"title,omitempty" dynamodbav:"title,omitempty" firestore:"title,omitempty"`
 	Count                *int       `mapstructure:"count" json:"count,omitempty" gorm:"column:count" bson:"count,omitempty" dynamodbav:"count,omitempty" firestore:"count,omitempty"`
 	ItemCount            *int       `mapstructure:"itemCount" json:"itemCount,omitempty" gorm:"column:itemCount" bson:"itemCount,omitempty" dynamodbav:"itemCount,omitempty" firestore:"itemCount,omitempty"`
+	Highlight            string     `mapstructure:"highlight" json:"highlight,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-"`
 }
 
 type ListResultPlaylist struct {
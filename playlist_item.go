@@ -0,0 +1,239 @@
+package video
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/core-go/video/cursor"
+)
+
+// PlaylistItem keeps the items removed from the playlist, with RemovedAt.
+type PlaylistItem struct {
+	Id                     string     `mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	PlaylistId             string     `mapstructure:"playlistId" json:"playlistId,omitempty" gorm:"column:playlistId" bson:"playlistId,omitempty" dynamodbav:"playlistId,omitempty" firestore:"playlistId,omitempty"`
+	VideoId                string     `mapstructure:"videoId" json:"videoId,omitempty" gorm:"column:videoId" bson:"videoId,omitempty" dynamodbav:"videoId,omitempty" firestore:"videoId,omitempty"`
+	Position               int        `mapstructure:"position" json:"position,omitempty" gorm:"column:position" bson:"position,omitempty" dynamodbav:"position,omitempty" firestore:"position,omitempty"`
+	VideoOwnerChannelId    string     `mapstructure:"videoOwnerChannelId" json:"videoOwnerChannelId,omitempty" gorm:"column:videoOwnerChannelId" bson:"videoOwnerChannelId,omitempty" dynamodbav:"videoOwnerChannelId,omitempty" firestore:"videoOwnerChannelId,omitempty"`
+	VideoOwnerChannelTitle string     `mapstructure:"videoOwnerChannelTitle" json:"videoOwnerChannelTitle,omitempty" gorm:"column:videoOwnerChannelTitle" bson:"videoOwnerChannelTitle,omitempty" dynamodbav:"videoOwnerChannelTitle,omitempty" firestore:"videoOwnerChannelTitle,omitempty"`
+	AddedAt                *time.Time `mapstructure:"addedAt" json:"addedAt,omitempty" gorm:"column:addedAt" bson:"addedAt,omitempty" dynamodbav:"addedAt,omitempty" firestore:"addedAt,omitempty"`
+	UpdatedAt              *time.Time `mapstructure:"updatedAt" json:"updatedAt,omitempty" gorm:"column:updatedAt" bson:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+	RemovedAt              *time.Time `mapstructure:"removedAt" json:"removedAt,omitempty" gorm:"column:removedAt" bson:"removedAt,omitempty" dynamodbav:"removedAt,omitempty" firestore:"removedAt,omitempty"`
+}
+
+type PlaylistItemRepository interface {
+	GetPlaylistItems(ctx context.Context, playlistId string) ([]PlaylistItem, error)
+	SavePlaylistItems(ctx context.Context, items []PlaylistItem) (int, error)
+}
+
+// PlaylistChanges does not count as moved a video shifted by an addition or removal.
+type PlaylistChanges struct {
+	Added   []string `mapstructure:"added" json:"added,omitempty" gorm:"column:added" bson:"added,omitempty" dynamodbav:"added,omitempty" firestore:"added,omitempty"`
+	Removed []string `mapstructure:"removed" json:"removed,omitempty" gorm:"column:removed" bson:"removed,omitempty" dynamodbav:"removed,omitempty" firestore:"removed,omitempty"`
+	Moved   []string `mapstructure:"moved" json:"moved,omitempty" gorm:"column:moved" bson:"moved,omitempty" dynamodbav:"moved,omitempty" firestore:"moved,omitempty"`
+}
+
+func PlaylistItemId(playlistId string, videoId string) string {
+	return playlistId + "/" + videoId
+}
+
+func NewPlaylistItems(playlistId string, ids []string, videos []PlaylistVideo) []PlaylistItem {
+	details := make(map[string]PlaylistVideo, len(videos))
+	for _, v := range videos {
+		details[v.Id] = v
+	}
+	seen := make(map[string]bool, len(ids))
+	items := make([]PlaylistItem, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		item := PlaylistItem{Id: PlaylistItemId(playlistId, id), PlaylistId: playlistId, VideoId: id, Position: len(items)}
+		if v, ok := details[id]; ok {
+			item.VideoOwnerChannelId = v.VideoOwnerChannelId
+			item.VideoOwnerChannelTitle = v.VideoOwnerChannelTitle
+			item.AddedAt = v.PublishedAt
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// DiffPlaylistItems returns the items to save, stamped with now, and the changes.
+func DiffPlaylistItems(previous []PlaylistItem, current []PlaylistItem, now time.Time) ([]PlaylistItem, PlaylistChanges) {
+	var changes PlaylistChanges
+	var save []PlaylistItem
+	stored := make(map[string]PlaylistItem, len(previous))
+	for _, item := range previous {
+		stored[item.VideoId] = item
+	}
+	previousPosition := make(map[string]int, len(previous))
+	for _, item := range previous {
+		if item.RemovedAt == nil {
+			previousPosition[item.VideoId] = item.Position
+		}
+	}
+	for _, item := range current {
+		old, ok := stored[item.VideoId]
+		if ok {
+			delete(stored, item.VideoId)
+			if len(item.VideoOwnerChannelId) == 0 {
+				item.VideoOwnerChannelId = old.VideoOwnerChannelId
+				item.VideoOwnerChannelTitle = old.VideoOwnerChannelTitle
+			}
+			if item.AddedAt == nil {
+				item.AddedAt = old.AddedAt
+			}
+		}
+		if !ok || old.RemovedAt != nil {
+			changes.Added = append(changes.Added, item.VideoId)
+		} else if old.Position == item.Position && samePlaylistItem(old, item) {
+			continue
+		}
+		item.UpdatedAt = &now
+		save = append(save, item)
+	}
+	for _, i := range movedItems(current, previousPosition) {
+		changes.Moved = append(changes.Moved, current[i].VideoId)
+	}
+	removed := make([]PlaylistItem, 0, len(stored))
+	for _, item := range stored {
+		if item.RemovedAt == nil {
+			removed = append(removed, item)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Position < removed[j].Position })
+	for _, item := range removed {
+		item.RemovedAt = &now
+		item.UpdatedAt = &now
+		changes.Removed = append(changes.Removed, item.VideoId)
+		save = append(save, item)
+	}
+	return save, changes
+}
+
+func samePlaylistItem(a PlaylistItem, b PlaylistItem) bool {
+	return a.VideoOwnerChannelId == b.VideoOwnerChannelId && a.VideoOwnerChannelTitle == b.VideoOwnerChannelTitle &&
+		(a.AddedAt == nil) == (b.AddedAt == nil) && (a.AddedAt == nil || a.AddedAt.Equal(*b.AddedAt))
+}
+
+// movedItems returns the items out of the longest increasing run of previous positions.
+func movedItems(current []PlaylistItem, previousPosition map[string]int) []int {
+	var indexes, positions []int
+	for i, item := range current {
+		if p, ok := previousPosition[item.VideoId]; ok {
+			indexes = append(indexes, i)
+			positions = append(positions, p)
+		}
+	}
+	// tails[k] is the index in positions of the smallest last position of an increasing run of k+1
+	var tails []int
+	parent := make([]int, len(positions))
+	for i, p := range positions {
+		k := sort.Search(len(tails), func(j int) bool { return positions[tails[j]] >= p })
+		if k > 0 {
+			parent[i] = tails[k-1]
+		} else {
+			parent[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	inOrder := make([]bool, len(positions))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = parent[i] {
+			inOrder[i] = true
+		}
+	}
+	var moved []int
+	for i, ok := range inOrder {
+		if !ok {
+			moved = append(moved, indexes[i])
+		}
+	}
+	return moved
+}
+
+func PlaylistOrder(items []PlaylistItem) []PlaylistItem {
+	active := make([]PlaylistItem, 0, len(items))
+	for _, item := range items {
+		if item.RemovedAt == nil {
+			active = append(active, item)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Position == active[j].Position {
+			return active[i].VideoId < active[j].VideoId
+		}
+		return active[i].Position < active[j].Position
+	})
+	return active
+}
+
+// PagePlaylist pages by position and id, so a page after a sync that moved items goes on where the last one ended.
+func PagePlaylist(items []PlaylistItem, max int, nextPageToken string, load func(ids []string) ([]Video, error)) (*ListResultVideos, error) {
+	c, er0 := cursor.Decode(nextPageToken, "playlist")
+	if er0 != nil {
+		return nil, er0
+	}
+	start := 0
+	if c != nil {
+		if len(c.Values) != 1 {
+			return nil, cursor.ErrInvalid
+		}
+		v, err := cursor.Parse(c.Values[0], reflect.TypeOf(0))
+		if v == nil || err != nil {
+			return nil, cursor.ErrInvalid
+		}
+		start = playlistAfter(items, int(v.(int64)), c.Id)
+	}
+	res := ListResultVideos{Limit: max}
+	var indexes []int
+	for i := start; i < len(items) && len(res.List) <= max; {
+		end := i + max + 1 - len(res.List)
+		if end > len(items) {
+			end = len(items)
+		}
+		ids := make([]string, 0, end-i)
+		for _, item := range items[i:end] {
+			ids = append(ids, item.VideoId)
+		}
+		loaded, er1 := load(ids)
+		if er1 != nil {
+			return nil, er1
+		}
+		byId := make(map[string]Video, len(loaded))
+		for _, v := range loaded {
+			byId[v.Id] = v
+		}
+		for j := i; j < end; j++ {
+			if v, ok := byId[items[j].VideoId]; ok {
+				res.List = append(res.List, v)
+				indexes = append(indexes, j)
+			}
+		}
+		i = end
+	}
+	if len(res.List) > max {
+		res.List = res.List[:max]
+		last := items[indexes[max-1]]
+		token, err := cursor.Encode(cursor.Cursor{Sort: "playlist", Values: []interface{}{cursor.Value(reflect.ValueOf(last.Position))}, Id: last.VideoId})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	res.Total = len(items)
+	return &res, nil
+}
+
+func playlistAfter(items []PlaylistItem, position int, videoId string) int {
+	return sort.Search(len(items), func(i int) bool {
+		return items[i].Position > position || items[i].Position == position && items[i].VideoId > videoId
+	})
+}
@@ -32,4 +32,5 @@ type PlaylistVideo struct {
 	Position               int        `mapstructure:"position" json:"position,omitempty" gorm:"column:position" bson:"position,omitempty" dynamodbav:"position,omitempty" firestore:"position,omitempty"`
 	VideoOwnerChannelId    string     `mapstructure:"videoOwnerChannelId" json:"videoOwnerChannelId,omitempty" gorm:"column:videoOwnerChannelId" bson:"videoOwnerChannelId,omitempty" dynamodbav:"videoOwnerChannelId,omitempty" firestore:"videoOwnerChannelId,omitempty"`
 	VideoOwnerChannelTitle string     `mapstructure:"videoOwnerChannelTitle" json:"videoOwnerChannelTitle,omitempty" gorm:"column:videoOwnerChannelTitle" bson:"videoOwnerChannelTitle,omitempty" dynamodbav:"videoOwnerChannelTitle,omitempty" firestore:"videoOwnerChannelTitle,omitempty"`
+	PrivacyStatus          string     `mapstructure:"privacyStatus" json:"privacyStatus,omitempty" gorm:"column:privacyStatus" bson:"privacyStatus,omitempty" dynamodbav:"privacyStatus,omitempty" firestore:"privacyStatus,omitempty"`
 }
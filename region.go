@@ -0,0 +1,28 @@
+package video
+
+import "strings"
+
+// Available lets allowed regions win over blocked ones. An empty regionCode matches every video.
+func Available(v Video, regionCode string) bool {
+	if len(regionCode) == 0 {
+		return true
+	}
+	if len(v.AllowedRegions) > 0 {
+		return hasRegion(v.AllowedRegions, regionCode)
+	}
+	return !hasRegion(v.BlockedRegions, regionCode)
+}
+
+// RegionCode returns regionCode in the upper case the regions of a video are stored in.
+func RegionCode(regionCode string) string {
+	return strings.ToUpper(strings.TrimSpace(regionCode))
+}
+
+func hasRegion(regions []string, regionCode string) bool {
+	for _, region := range regions {
+		if strings.EqualFold(region, regionCode) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package video
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RelatedWeights weigh recency by Recency / (1 + age/RecencyScale).
+type RelatedWeights struct {
+	Tags         float64
+	Channel      float64
+	Category     float64
+	Title        float64
+	Recency      float64
+	RecencyScale time.Duration
+}
+
+var DefaultRelatedWeights = RelatedWeights{Tags: 4, Channel: 2, Category: 1, Title: 2, Recency: 1, RecencyScale: 30 * 24 * time.Hour}
+
+// RelatedCandidates caps the videos each signal of a related query reads, the newest first.
+var RelatedCandidates = 200
+
+var RelatedFields = []string{"id", "tags", "channelId", "categoryId", "title", "publishedAt"}
+
+type RelatedVideo struct {
+	Video Video
+	Score float64
+}
+
+var stopTerms = map[string]bool{"an": true, "and": true, "the": true, "of": true, "in": true, "on": true, "for": true, "to": true, "with": true, "is": true}
+
+// TitleTerms leaves out words of one letter and stop words.
+func TitleTerms(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopTerms[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+func Jaccard(a []string, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	union := len(set)
+	intersection := 0
+	counted := make(map[string]bool, len(b))
+	for _, s := range b {
+		if counted[s] {
+			continue
+		}
+		counted[s] = true
+		if set[s] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// IsRelated ignores recency.
+func IsRelated(seed Video, candidate Video) bool {
+	if candidate.Id == seed.Id {
+		return false
+	}
+	return Jaccard(seed.Tags, candidate.Tags) > 0 ||
+		len(seed.ChannelId) > 0 && candidate.ChannelId == seed.ChannelId ||
+		len(seed.CategoryId) > 0 && candidate.CategoryId == seed.CategoryId ||
+		Jaccard(TitleTerms(seed.Title), TitleTerms(candidate.Title)) > 0
+}
+
+// RelatedScore depends only on its arguments, so the pages of a list ranked at the same now agree.
+func RelatedScore(seed Video, candidate Video, now time.Time, w RelatedWeights) float64 {
+	score := w.Tags*Jaccard(seed.Tags, candidate.Tags) + w.Title*Jaccard(TitleTerms(seed.Title), TitleTerms(candidate.Title))
+	if len(seed.ChannelId) > 0 && candidate.ChannelId == seed.ChannelId {
+		score += w.Channel
+	}
+	if len(seed.CategoryId) > 0 && candidate.CategoryId == seed.CategoryId {
+		score += w.Category
+	}
+	if candidate.PublishedAt != nil && w.RecencyScale > 0 {
+		age := now.Sub(*candidate.PublishedAt)
+		if age < 0 {
+			age = 0
+		}
+		score += w.Recency / (1 + float64(age)/float64(w.RecencyScale))
+	}
+	return score
+}
+
+func RankRelated(seed Video, candidates []Video, now time.Time, w RelatedWeights) []RelatedVideo {
+	var ranked []RelatedVideo
+	for _, candidate := range candidates {
+		if IsRelated(seed, candidate) {
+			ranked = append(ranked, RelatedVideo{Video: candidate, Score: RelatedScore(seed, candidate, now, w)})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score == ranked[j].Score {
+			return ranked[i].Video.Id < ranked[j].Video.Id
+		}
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+func RelatedAfter(ranked []RelatedVideo, score float64, id string) int {
+	return sort.Search(len(ranked), func(i int) bool {
+		return ranked[i].Score < score || ranked[i].Score == score && ranked[i].Video.Id > id
+	})
+}
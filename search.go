@@ -27,15 +27,23 @@ type PlaylistSM struct {
 	SafeSearch        string     `mapstructure:"safeSearch" json:"safeSearch,omitempty" gorm:"column:safeSearch" bson:"safeSearch,omitempty" dynamodbav:"safeSearch,omitempty" firestore:"safeSearch"`
 }
 
+const AgeRestricted = "ytAgeRestricted"
+
+var VideoTypeCategories = map[string]string{
+	"movie":   "30",
+	"episode": "43",
+}
+
+// ItemSM filters take the values of the YouTube search API; an empty filter, or "any", does not filter.
 type ItemSM struct {
 	Q                 string     `mapstructure:"q" json:"q,omitempty" gorm:"column:q" bson:"q,omitempty" dynamodbav:"q,omitempty" firestore:"q"`
 	Kind              string     `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind"`
 	Duration          string     `mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"durationomitempty" firestore:"duration"`
 	Sort              string     `mapstructure:"sort" json:"sort,omitempty" gorm:"column:sort" bson:"sort,omitempty" dynamodbav:"sort,omitempty" firestore:"sort"`
 	RelatedToVideoId  string     `mapstructure:"relatedToVideoId" json:"relatedToVideoId,omitempty" gorm:"column:relatedToVideoId" bson:"relatedToVideoId,omitempty" dynamodbav:"relatedToVideoId,omitempty" firestore:"relatedToVideoId"`
-	ForMine           bool       `mapstructure:"forMine" json:"forMine,omitempty" gorm:"column:forMine" bson:"forMine,omitempty" dynamodbav:"forMine,omitempty" firestore:"forMine"`
+	ForMine           bool       `mapstructure:"forMine" json:"forMine,omitempty" gorm:"column:forMine" bson:"forMine,omitempty" dynamodbav:"forMine,omitempty" firestore:"forMine"` // Deprecated: needs the OAuth user of the YouTube API, ignored.
 	ChannelId         string     `mapstructure:"channelId" json:"channelId,omitempty" gorm:"column:channelId" bson:"channelId,omitempty" dynamodbav:"channelId,omitempty" firestore:"channelId"`
-	ChannelType       string     `mapstructure:"channelType" json:"channelType,omitempty" gorm:"column:channelType" bson:"channelType,omitempty" dynamodbav:"channelType,omitempty" firestore:"channelType"`
+	ChannelType       string     `mapstructure:"channelType" json:"channelType,omitempty" gorm:"column:channelType" bson:"channelType,omitempty" dynamodbav:"channelType,omitempty" firestore:"channelType"` // Deprecated: not stored by sync, ignored.
 	EventType         string     `mapstructure:"eventType" json:"eventType,omitempty" gorm:"column:eventType" bson:"eventType,omitempty" dynamodbav:"eventType,omitempty" firestore:"eventType"`
 	PublishedAfter    *time.Time `mapstructure:"publishedAfter" json:"publishedAfter,omitempty" gorm:"column:publishedAfter" bson:"publishedAfter,omitempty" dynamodbav:"publishedAfter,omitempty" firestore:"publishedAfter"`
 	PublishedBefore   *time.Time `mapstructure:"publishedBefore" json:"publishedBefore,omitempty" gorm:"column:publishedBefore" bson:"publishedBefore,omitempty" dynamodbav:"publishedBefore,omitempty" firestore:"publishedBefore"`
@@ -47,8 +55,8 @@ type ItemSM struct {
 	Caption           string     `mapstructure:"caption" json:"caption,omitempty" gorm:"column:caption" bson:"caption,omitempty" dynamodbav:"caption,omitempty" firestore:"caption"`
 	Definition        string     `mapstructure:"definition" json:"definition,omitempty" gorm:"column:definition" bson:"definition,omitempty" dynamodbav:"definition,omitempty" firestore:"definition"`
 	Dimension         string     `mapstructure:"dimension" json:"dimension,omitempty" gorm:"column:dimension" bson:"dimension,omitempty" dynamodbav:"dimension,omitempty" firestore:"dimension"`
-	Embeddable        string     `mapstructure:"embeddable" json:"embeddable,omitempty" gorm:"embeddable:caption" bson:"embeddable,omitempty" dynamodbav:"embeddable,omitempty" firestore:"embeddable"`
+	Embeddable        string     `mapstructure:"embeddable" json:"embeddable,omitempty" gorm:"column:embeddable" bson:"embeddable,omitempty" dynamodbav:"embeddable,omitempty" firestore:"embeddable"`
 	License           string     `mapstructure:"license" json:"license,omitempty" gorm:"column:license" bson:"license,omitempty" dynamodbav:"license,omitempty" firestore:"license"`
-	Syndicated        string     `mapstructure:"syndicated" json:"syndicated,omitempty" gorm:"column:syndicated" bson:"syndicated,omitempty" dynamodbav:"syndicated,omitempty" firestore:"syndicated"`
+	Syndicated        string     `mapstructure:"syndicated" json:"syndicated,omitempty" gorm:"column:syndicated" bson:"syndicated,omitempty" dynamodbav:"syndicated,omitempty" firestore:"syndicated"` // Deprecated: not stored by sync, ignored.
 	VideoType         string     `mapstructure:"videoType" json:"videoType,omitempty" gorm:"column:videoType" bson:"videoType,omitempty" dynamodbav:"videoType,omitempty" firestore:"videoType"`
 }
@@ -0,0 +1,91 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	KindChannel  = "channel"
+	KindPlaylist = "playlist"
+	KindVideo    = "video"
+)
+
+var ErrInvalidKind = errors.New("invalid kind")
+
+var Kinds = []string{KindChannel, KindPlaylist, KindVideo}
+
+type SearchResult struct {
+	Kind     string    `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind,omitempty"`
+	Channel  *Channel  `mapstructure:"channel" json:"channel,omitempty" gorm:"-" bson:"channel,omitempty" dynamodbav:"channel,omitempty" firestore:"channel,omitempty"`
+	Playlist *Playlist `mapstructure:"playlist" json:"playlist,omitempty" gorm:"-" bson:"playlist,omitempty" dynamodbav:"playlist,omitempty" firestore:"playlist,omitempty"`
+	Video    *Video    `mapstructure:"video" json:"video,omitempty" gorm:"-" bson:"video,omitempty" dynamodbav:"video,omitempty" firestore:"video,omitempty"`
+}
+
+func (r SearchResult) Id() string {
+	switch {
+	case r.Channel != nil:
+		return r.Channel.Id
+	case r.Playlist != nil:
+		return r.Playlist.Id
+	case r.Video != nil:
+		return r.Video.Id
+	}
+	return ""
+}
+
+type ListResultSearch struct {
+	List          []SearchResult `mapstructure:"list" json:"list,omitempty" gorm:"column:list" bson:"list,omitempty" dynamodbav:"list,omitempty" firestore:"list,omitempty"`
+	Total         int            `mapstructure:"total" json:"total,omitempty" gorm:"column:total" bson:"total,omitempty" dynamodbav:"total,omitempty" firestore:"total,omitempty"`
+	Limit         int            `mapstructure:"limit" json:"limit,omitempty" gorm:"column:limit" bson:"limit,omitempty" dynamodbav:"limit,omitempty" firestore:"limit,omitempty"`
+	NextPageToken string         `mapstructure:"nextPageToken" json:"nextPageToken,omitempty" gorm:"column:nextPageToken" bson:"nextPageToken,omitempty" dynamodbav:"nextPageToken,omitempty" firestore:"nextPageToken,omitempty"`
+}
+
+var SearchSortable = Sortable{
+	"date":        VideoSortable["date"],
+	"publishedAt": VideoSortable["publishedAt"],
+	"title":       VideoSortable["title"],
+	"relevance":   VideoSortable["relevance"],
+}
+
+// SearchKinds leaves channels and playlists out when s has a filter only videos have.
+func SearchKinds(s ItemSM) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, kind := range strings.Split(s.Kind, ",") {
+		kind = strings.TrimSpace(kind)
+		if len(kind) == 0 {
+			continue
+		}
+		if kind != KindChannel && kind != KindPlaylist && kind != KindVideo {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKind, kind)
+		}
+		selected[kind] = true
+	}
+	videosOnly := hasVideoFilter(s)
+	var kinds []string
+	for _, kind := range Kinds {
+		if (len(selected) == 0 || selected[kind]) && (!videosOnly || kind == KindVideo) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+func hasVideoFilter(s ItemSM) bool {
+	filters := []string{s.CategoryId, s.Caption, s.Definition, s.Dimension, s.Duration, s.Embeddable, s.EventType, s.License, s.RelatedToVideoId, s.TopicId, s.VideoType}
+	for _, filter := range filters {
+		if len(filter) > 0 && filter != "any" {
+			return true
+		}
+	}
+	return false
+}
+
+func ToChannelSM(s ItemSM) ChannelSM {
+	return ChannelSM{Q: s.Q, Sort: s.Sort, ChannelId: s.ChannelId, PublishedAfter: s.PublishedAfter, PublishedBefore: s.PublishedBefore}
+}
+
+func ToPlaylistSM(s ItemSM) PlaylistSM {
+	return PlaylistSM{Q: s.Q, Sort: s.Sort, ChannelId: s.ChannelId, PublishedAfter: s.PublishedAfter, PublishedBefore: s.PublishedBefore}
+}
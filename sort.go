@@ -0,0 +1,116 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const Relevance = "relevance"
+
+var (
+	ErrInvalidSort  = errors.New("invalid sort")
+	ErrInvalidField = errors.New("invalid field")
+)
+
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+type Sortable map[string]SortKey
+
+var VideoSortable = Sortable{
+	"date":         {Field: "publishedAt", Desc: true},
+	"publishedAt":  {Field: "publishedAt", Desc: true},
+	"title":        {Field: "title"},
+	"rating":       {Field: "likeCount", Desc: true},
+	"likeCount":    {Field: "likeCount", Desc: true},
+	"viewCount":    {Field: "viewCount", Desc: true},
+	"commentCount": {Field: "commentCount", Desc: true},
+	"duration":     {Field: "duration", Desc: true},
+	"relevance":    {Field: Relevance, Desc: true},
+}
+
+var ChannelSortable = Sortable{
+	"date":            {Field: "publishedAt", Desc: true},
+	"publishedAt":     {Field: "publishedAt", Desc: true},
+	"title":           {Field: "title"},
+	"viewCount":       {Field: "viewCount", Desc: true},
+	"subscriberCount": {Field: "subscriberCount", Desc: true},
+	"videoCount":      {Field: "videoCount", Desc: true},
+	"relevance":       {Field: Relevance, Desc: true},
+}
+
+var PlaylistSortable = Sortable{
+	"date":        {Field: "publishedAt", Desc: true},
+	"publishedAt": {Field: "publishedAt", Desc: true},
+	"title":       {Field: "title"},
+	"videoCount":  {Field: "itemCount", Desc: true},
+	"itemCount":   {Field: "itemCount", Desc: true},
+	"relevance":   {Field: Relevance, Desc: true},
+}
+
+// ParseSort reads names such as "viewCount,-date" or "+title". Ties are ordered by publishedAt desc, then id.
+func ParseSort(sort string, sortable Sortable, q string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(sort, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+		direction := name[0]
+		if direction == '-' || direction == '+' {
+			name = name[1:]
+		}
+		key, ok := sortable[name]
+		if !ok || seen[key.Field] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSort, name)
+		}
+		seen[key.Field] = true
+		if direction == '-' {
+			key.Desc = true
+		} else if direction == '+' {
+			key.Desc = false
+		}
+		if key.Field == Relevance {
+			if len(seen) > 1 {
+				return nil, fmt.Errorf("%w: %s must be the first key", ErrInvalidSort, name)
+			}
+			if len(q) == 0 {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	if !seen["publishedAt"] {
+		keys = append(keys, SortKey{Field: "publishedAt", Desc: true})
+	}
+	return keys, nil
+}
+
+func FormatSort(keys []SortKey) string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		if key.Desc {
+			names[i] = "-" + key.Field
+		} else {
+			names[i] = key.Field
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func SortTitle(title string) string {
+	return strings.ToLower(title)
+}
+
+// Score counts the occurrences of q in the title twice and in the description once.
+func Score(q string, title string, description string) int64 {
+	if len(q) == 0 {
+		return 0
+	}
+	q = strings.ToLower(q)
+	return int64(2*strings.Count(strings.ToLower(title), q) + strings.Count(strings.ToLower(description), q))
+}
@@ -0,0 +1,60 @@
+package video
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Subscription keeps the title of ChannelId, so a channel not synced yet can still be listed.
+type Subscription struct {
+	Id           string     `mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	SubscriberId string     `mapstructure:"subscriberId" json:"subscriberId,omitempty" gorm:"column:subscriberId" bson:"subscriberId,omitempty" dynamodbav:"subscriberId,omitempty" firestore:"subscriberId,omitempty"`
+	ChannelId    string     `mapstructure:"channelId" json:"channelId,omitempty" gorm:"column:channelId" bson:"channelId,omitempty" dynamodbav:"channelId,omitempty" firestore:"channelId,omitempty"`
+	Title        string     `mapstructure:"title" json:"title,omitempty" gorm:"column:title" bson:"title,omitempty" dynamodbav:"title,omitempty" firestore:"title,omitempty"`
+	Thumbnail    *string    `mapstructure:"thumbnail" json:"thumbnail,omitempty" gorm:"column:thumbnail" bson:"thumbnail,omitempty" dynamodbav:"thumbnail,omitempty" firestore:"thumbnail,omitempty"`
+	PublishedAt  *time.Time `mapstructure:"publishedAt" json:"publishedAt,omitempty" gorm:"column:publishedAt" bson:"publishedAt,omitempty" dynamodbav:"publishedAt,omitempty" firestore:"publishedAt,omitempty"`
+}
+
+// SaveSubscriptions replaces the subscriptions of subscriberId.
+type SubscriptionRepository interface {
+	SaveSubscriptions(ctx context.Context, subscriberId string, subscriptions []Subscription) (int, error)
+}
+
+func NewSubscription(subscriberId string, channel Channel) Subscription {
+	return Subscription{
+		Id:           SubscriptionId(subscriberId, channel.Id),
+		SubscriberId: subscriberId,
+		ChannelId:    channel.Id,
+		Title:        channel.Title,
+		Thumbnail:    channel.Thumbnail,
+		PublishedAt:  channel.PublishedAt,
+	}
+}
+
+func SubscriptionId(subscriberId string, channelId string) string {
+	return subscriberId + "/" + channelId
+}
+
+func SubscriptionChannel(s Subscription, fields []string) Channel {
+	channel := Channel{Id: s.ChannelId}
+	if hasField(fields, "title") {
+		channel.Title = s.Title
+	}
+	if hasField(fields, "thumbnail") {
+		channel.Thumbnail = s.Thumbnail
+	}
+	return channel
+}
+
+func hasField(fields []string, field string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
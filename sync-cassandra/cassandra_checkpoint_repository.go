@@ -0,0 +1,56 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/core-go/video"
+	"github.com/gocql/gocql"
+)
+
+type CassandraCheckpointRepository struct {
+	session          *gocql.Session
+	checkpointSchema *Schema
+	indexField       map[string]int
+}
+
+func NewCassandraCheckpointRepository(session *gocql.Session) (*CassandraCheckpointRepository, error) {
+	var checkpoint SyncCheckpoint
+	modelType := reflect.TypeOf(checkpoint)
+	indexField, er0 := GetColumnIndexes(modelType)
+	if er0 != nil {
+		return nil, er0
+	}
+	schema := CreateSchema(modelType)
+	return &CassandraCheckpointRepository{session: session, checkpointSchema: schema, indexField: indexField}, nil
+}
+
+func (s *CassandraCheckpointRepository) GetCheckpoint(ctx context.Context, id string) (*SyncCheckpoint, error) {
+	var res []SyncCheckpoint
+	query := `select * from syncCheckpoint where id = ?`
+	err := Query(s.session, s.indexField, &res, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return &res[0], nil
+}
+
+func (s *CassandraCheckpointRepository) SaveCheckpoint(ctx context.Context, checkpoint SyncCheckpoint) (int, error) {
+	query, params := BuildToSave("syncCheckpoint", checkpoint, s.checkpointSchema)
+	res, err := Exec(s.session, query, params...)
+	if err != nil {
+		return -1, err
+	}
+	return int(res), nil
+}
+
+func (s *CassandraCheckpointRepository) DeleteCheckpoint(ctx context.Context, id string) (int, error) {
+	res, err := Exec(s.session, `delete from syncCheckpoint where id = ?`, id)
+	if err != nil {
+		return -1, err
+	}
+	return int(res), nil
+}
@@ -0,0 +1,48 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/core-go/video"
+	"github.com/gocql/gocql"
+)
+
+type CassandraJobRepository struct {
+	session    *gocql.Session
+	jobSchema  *Schema
+	indexField map[string]int
+}
+
+func NewCassandraJobRepository(session *gocql.Session) (*CassandraJobRepository, error) {
+	var job SyncJob
+	modelType := reflect.TypeOf(job)
+	indexField, er0 := GetColumnIndexes(modelType)
+	if er0 != nil {
+		return nil, er0
+	}
+	schema := CreateSchema(modelType)
+	return &CassandraJobRepository{session: session, jobSchema: schema, indexField: indexField}, nil
+}
+
+func (s *CassandraJobRepository) GetJob(ctx context.Context, id string) (*SyncJob, error) {
+	var res []SyncJob
+	query := `select * from syncJob where id = ?`
+	err := Query(s.session, s.indexField, &res, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return &res[0], nil
+}
+
+func (s *CassandraJobRepository) SaveJob(ctx context.Context, job SyncJob) (int, error) {
+	query, params := BuildToSave("syncJob", job, s.jobSchema)
+	res, err := Exec(s.session, query, params...)
+	if err != nil {
+		return -1, err
+	}
+	return int(res), nil
+}
@@ -0,0 +1,48 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/core-go/video"
+	"github.com/gocql/gocql"
+)
+
+type CassandraPlaylistItemRepository struct {
+	session            *gocql.Session
+	playlistItemSchema *Schema
+	indexField         map[string]int
+}
+
+func NewCassandraPlaylistItemRepository(session *gocql.Session) (*CassandraPlaylistItemRepository, error) {
+	var item PlaylistItem
+	modelType := reflect.TypeOf(item)
+	indexField, er0 := GetColumnIndexes(modelType)
+	if er0 != nil {
+		return nil, er0
+	}
+	schema := CreateSchema(modelType)
+	return &CassandraPlaylistItemRepository{session: session, playlistItemSchema: schema, indexField: indexField}, nil
+}
+
+func (s *CassandraPlaylistItemRepository) GetPlaylistItems(ctx context.Context, playlistId string) ([]PlaylistItem, error) {
+	var res []PlaylistItem
+	query := `select * from playlistItem where playlistId = ?`
+	err := Query(s.session, s.indexField, &res, query, playlistId)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *CassandraPlaylistItemRepository) SavePlaylistItems(ctx context.Context, items []PlaylistItem) (int, error) {
+	statements, er0 := BuildToInsertOrUpdateBatch("playlistItem", items, true, s.playlistItemSchema)
+	if er0 != nil {
+		return -1, er0
+	}
+	_, er1 := ExecuteAll(ctx, s.session, statements...)
+	if er1 != nil {
+		return -1, er1
+	}
+	return len(items), nil
+}
@@ -0,0 +1,32 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/core-go/video"
+	"github.com/gocql/gocql"
+)
+
+type CassandraStatisticsRepository struct {
+	session          *gocql.Session
+	statisticsSchema *Schema
+}
+
+func NewCassandraStatisticsRepository(session *gocql.Session) *CassandraStatisticsRepository {
+	var statistics VideoStatistics
+	schema := CreateSchema(reflect.TypeOf(statistics))
+	return &CassandraStatisticsRepository{session: session, statisticsSchema: schema}
+}
+
+func (s *CassandraStatisticsRepository) SaveStatistics(ctx context.Context, statistics []VideoStatistics) (int, error) {
+	statements, err := BuildToInsertOrUpdateBatch("videoStatistics", statistics, true, s.statisticsSchema)
+	if err != nil {
+		return -1, err
+	}
+	res, err := ExecuteAll(ctx, s.session, statements...)
+	if err != nil {
+		return -1, err
+	}
+	return int(res), nil
+}
@@ -0,0 +1,47 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/core-go/video"
+	"github.com/gocql/gocql"
+)
+
+type CassandraSubscriptionRepository struct {
+	session            *gocql.Session
+	subscriptionSchema *Schema
+}
+
+func NewCassandraSubscriptionRepository(session *gocql.Session) *CassandraSubscriptionRepository {
+	var subscription Subscription
+	schema := CreateSchema(reflect.TypeOf(subscription))
+	return &CassandraSubscriptionRepository{session: session, subscriptionSchema: schema}
+}
+
+// SaveSubscriptions inserts subscriptions and deletes the other subscriptions of subscriberId, which it reads first.
+func (s *CassandraSubscriptionRepository) SaveSubscriptions(ctx context.Context, subscriberId string, subscriptions []Subscription) (int, error) {
+	statements, err := BuildToInsertOrUpdateBatch("subscription", subscriptions, true, s.subscriptionSchema)
+	if err != nil {
+		return -1, err
+	}
+	subscribed := make(map[string]bool)
+	for _, subscription := range subscriptions {
+		subscribed[subscription.ChannelId] = true
+	}
+	iter := s.session.Query("select channelId from subscription where subscriberId = ?", subscriberId).WithContext(ctx).Iter()
+	var channelId string
+	for iter.Scan(&channelId) {
+		if !subscribed[channelId] {
+			statements = append(statements, Statement{Query: "delete from subscription where subscriberId = ? and channelId = ?", Params: []interface{}{subscriberId, channelId}})
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return -1, err
+	}
+	_, err = ExecuteAll(ctx, s.session, statements...)
+	if err != nil {
+		return -1, err
+	}
+	return len(subscriptions), nil
+}
@@ -79,6 +79,16 @@ func (s *CassandraVideoRepository) GetChannelSync(ctx context.Context, channelId
 	return &channelSync[0], err
 }
 
+func (s *CassandraVideoRepository) GetChannelSyncs(ctx context.Context) ([]ChannelSync, error) {
+	var channelSync []ChannelSync
+	query := `select * from channelSync`
+	err := Query(s.session, s.indexFieldChannelSync, &channelSync, query)
+	if err != nil {
+		return nil, err
+	}
+	return channelSync, nil
+}
+
 func (s *CassandraVideoRepository) SaveChannel(ctx context.Context, channel Channel) (int64, error) {
 	query, params := BuildToSave("channel", channel, s.channelSchema)
 	res, err := Exec(s.session, query, params...)
@@ -89,6 +99,9 @@ func (s *CassandraVideoRepository) SaveChannel(ctx context.Context, channel Chan
 }
 
 func (s *CassandraVideoRepository) GetVideoIds(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 	var video []Video
 	var result []string
 	var question []string
@@ -103,7 +116,9 @@ func (s *CassandraVideoRepository) GetVideoIds(ctx context.Context, ids []string
 		return nil, err
 	}
 	for i, _ := range video {
-		result = append(result, video[i].Id)
+		if !Tombstoned(video[i]) {
+			result = append(result, video[i].Id)
+		}
 	}
 	return result, nil
 }
@@ -0,0 +1,80 @@
+package cassandra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type CassandraTombstoneRepository struct {
+	session *gocql.Session
+}
+
+func NewCassandraTombstoneRepository(session *gocql.Session) *CassandraTombstoneRepository {
+	return &CassandraTombstoneRepository{session: session}
+}
+
+// TombstoneVideos reads the status of the videos first, as Cassandra updates a row only by its key.
+func (s *CassandraTombstoneRepository) TombstoneVideos(ctx context.Context, ids []string, status string, at time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	question := make([]string, len(ids))
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		question[i] = "?"
+		values[i] = id
+	}
+	query := fmt.Sprintf(`select id, status, tombstonedAt from video where id in (%s)`, strings.Join(question, ","))
+	iter := s.session.Query(query, values...).WithContext(ctx).Iter()
+	var statements []Statement
+	var id, current string
+	var tombstonedAt time.Time
+	for iter.Scan(&id, &current, &tombstonedAt) {
+		if current == status {
+			continue
+		}
+		since := at
+		if !tombstonedAt.IsZero() {
+			since = tombstonedAt
+		}
+		statements = append(statements, Statement{Query: "update video set status = ?, tombstonedAt = ? where id = ?", Params: []interface{}{status, since, id}})
+	}
+	if err := iter.Close(); err != nil {
+		return -1, err
+	}
+	if _, err := ExecuteAll(ctx, s.session, statements...); err != nil {
+		return -1, err
+	}
+	return len(statements), nil
+}
+
+// PurgeVideos finds the videos with the Lucene index of video, which indexes status and tombstonedAt.
+func (s *CassandraTombstoneRepository) PurgeVideos(ctx context.Context, status string, before time.Time) (int, error) {
+	filter, err := json.Marshal(map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"type": "match", "field": "status", "value": status},
+			map[string]interface{}{"type": "range", "field": "tombstonedat", "upper": before.UTC().Format("2006-01-02 15:04:05"), "include_upper": false},
+		},
+	})
+	if err != nil {
+		return -1, err
+	}
+	iter := s.session.Query(`select id from video where expr(video_index, ?)`, string(filter)).WithContext(ctx).Iter()
+	var statements []Statement
+	var id string
+	for iter.Scan(&id) {
+		statements = append(statements, Statement{Query: "delete from video where id = ?", Params: []interface{}{id}})
+	}
+	if err := iter.Close(); err != nil {
+		return -1, err
+	}
+	if _, err := ExecuteAll(ctx, s.session, statements...); err != nil {
+		return -1, err
+	}
+	return len(statements), nil
+}
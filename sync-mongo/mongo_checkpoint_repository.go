@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/core-go/video"
+)
+
+type MongoCheckpointRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoCheckpointRepository(db *mongo.Database, collectionName string) *MongoCheckpointRepository {
+	return &MongoCheckpointRepository{Collection: db.Collection(collectionName)}
+}
+
+func (m *MongoCheckpointRepository) GetCheckpoint(ctx context.Context, id string) (*SyncCheckpoint, error) {
+	result := m.Collection.FindOne(ctx, bson.M{"_id": id})
+	if result.Err() != nil {
+		if strings.Contains(result.Err().Error(), "mongo: no documents in result") {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+	checkpoint := SyncCheckpoint{}
+	err := result.Decode(&checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (m *MongoCheckpointRepository) SaveCheckpoint(ctx context.Context, checkpoint SyncCheckpoint) (int, error) {
+	opts := options.Replace().SetUpsert(true)
+	result, err := m.Collection.ReplaceOne(ctx, bson.M{"_id": checkpoint.Id}, checkpoint, opts)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount + result.UpsertedCount), nil
+}
+
+func (m *MongoCheckpointRepository) DeleteCheckpoint(ctx context.Context, id string) (int, error) {
+	result, err := m.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/core-go/video"
+)
+
+type MongoJobRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoJobRepository(db *mongo.Database, collectionName string) *MongoJobRepository {
+	return &MongoJobRepository{Collection: db.Collection(collectionName)}
+}
+
+func (m *MongoJobRepository) GetJob(ctx context.Context, id string) (*SyncJob, error) {
+	result := m.Collection.FindOne(ctx, bson.M{"_id": id})
+	if result.Err() != nil {
+		if strings.Contains(result.Err().Error(), "mongo: no documents in result") {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+	job := SyncJob{}
+	err := result.Decode(&job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (m *MongoJobRepository) SaveJob(ctx context.Context, job SyncJob) (int, error) {
+	opts := options.Replace().SetUpsert(true)
+	result, err := m.Collection.ReplaceOne(ctx, bson.M{"_id": job.Id}, job, opts)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount + result.UpsertedCount), nil
+}
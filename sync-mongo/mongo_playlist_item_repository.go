@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/core-go/video"
+)
+
+type MongoPlaylistItemRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoPlaylistItemRepository(db *mongo.Database, collectionName string) *MongoPlaylistItemRepository {
+	return &MongoPlaylistItemRepository{Collection: db.Collection(collectionName)}
+}
+
+func (m *MongoPlaylistItemRepository) GetPlaylistItems(ctx context.Context, playlistId string) ([]PlaylistItem, error) {
+	cur, er0 := m.Collection.Find(ctx, bson.M{"playlistId": playlistId}, options.Find().SetSort(bson.D{{"position", 1}, {"videoId", 1}}))
+	if er0 != nil {
+		return nil, er0
+	}
+	var items []PlaylistItem
+	if er1 := cur.All(ctx, &items); er1 != nil {
+		return nil, er1
+	}
+	return items, nil
+}
+
+// SavePlaylistItems replaces items by Id, so an item added back to its playlist loses its removedAt.
+func (m *MongoPlaylistItemRepository) SavePlaylistItems(ctx context.Context, items []PlaylistItem) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	models := make([]mongo.WriteModel, 0, len(items))
+	for _, item := range items {
+		models = append(models, mongo.NewReplaceOneModel().SetUpsert(true).SetFilter(bson.M{"_id": item.Id}).SetReplacement(item))
+	}
+	result, err := m.Collection.BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.UpsertedCount + result.ModifiedCount), nil
+}
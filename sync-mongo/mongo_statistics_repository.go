@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/core-go/video"
+)
+
+type MongoStatisticsRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoStatisticsRepository(db *mongo.Database, collectionName string) *MongoStatisticsRepository {
+	return &MongoStatisticsRepository{Collection: db.Collection(collectionName)}
+}
+
+func (m *MongoStatisticsRepository) SaveStatistics(ctx context.Context, statistics []VideoStatistics) (int, error) {
+	if len(statistics) == 0 {
+		return 0, nil
+	}
+	models := make([]mongo.WriteModel, 0, len(statistics))
+	for _, s := range statistics {
+		filter := bson.M{"videoId": s.VideoId, "timestamp": s.Timestamp}
+		models = append(models, mongo.NewReplaceOneModel().SetUpsert(true).SetFilter(filter).SetReplacement(s))
+	}
+	result, err := m.Collection.BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.UpsertedCount + result.ModifiedCount), nil
+}
@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/core-go/video"
+)
+
+type MongoSubscriptionRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoSubscriptionRepository(db *mongo.Database, collectionName string) *MongoSubscriptionRepository {
+	return &MongoSubscriptionRepository{Collection: db.Collection(collectionName)}
+}
+
+// SaveSubscriptions upserts subscriptions, then deletes the other subscriptions of subscriberId.
+func (m *MongoSubscriptionRepository) SaveSubscriptions(ctx context.Context, subscriberId string, subscriptions []Subscription) (int, error) {
+	ids := make([]string, 0, len(subscriptions))
+	models := make([]mongo.WriteModel, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.Id)
+		models = append(models, mongo.NewReplaceOneModel().SetUpsert(true).SetFilter(bson.M{"_id": s.Id}).SetReplacement(s))
+	}
+	count := 0
+	if len(models) > 0 {
+		result, er1 := m.Collection.BulkWrite(ctx, models)
+		if er1 != nil {
+			return 0, er1
+		}
+		count = int(result.UpsertedCount + result.ModifiedCount)
+	}
+	_, er2 := m.Collection.DeleteMany(ctx, bson.M{"subscriberId": subscriberId, "_id": bson.M{"$nin": ids}})
+	if er2 != nil {
+		return count, er2
+	}
+	return count, nil
+}
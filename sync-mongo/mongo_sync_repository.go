@@ -55,6 +55,20 @@ func (m *MongoVideoRepository) GetChannelSync(ctx context.Context, channelId str
 	return &channelSync, nil
 }
 
+func (m *MongoVideoRepository) GetChannelSyncs(ctx context.Context) ([]ChannelSync, error) {
+	cursor, er0 := m.ChannelSyncCollection.Find(ctx, bson.M{})
+	if er0 != nil {
+		return nil, er0
+	}
+	defer cursor.Close(ctx)
+	var channelSyncs []ChannelSync
+	er1 := cursor.All(ctx, &channelSyncs)
+	if er1 != nil {
+		return nil, er1
+	}
+	return channelSyncs, nil
+}
+
 func (m *MongoVideoRepository) SaveChannel(ctx context.Context, channel Channel) (int64, error) {
 	_, er1 := m.ChannelCollection.InsertOne(ctx, channel)
 	if er1 != nil {
@@ -80,7 +94,7 @@ func (m *MongoVideoRepository) SaveChannel(ctx context.Context, channel Channel)
 }
 
 func (m *MongoVideoRepository) GetVideoIds(ctx context.Context, ids []string) ([]string, error) {
-	query := bson.M{"_id": bson.M{"$in": ids}}
+	query := bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$in": bson.A{nil, ""}}}
 	optionsFind := options.Find()
 	optionsFind.SetProjection(bson.M{"_id": 1})
 	result, er0 := m.VideoCollection.Find(ctx, query, optionsFind)
@@ -90,15 +104,11 @@ func (m *MongoVideoRepository) GetVideoIds(ctx context.Context, ids []string) ([
 	defer result.Close(ctx)
 	var res []string
 	for result.Next(ctx) {
-		var id string
-		if err := result.Decode(&id); err != nil {
-			if strings.Contains(err.Error(), "cannot decode invalid into") {
-				break
-			} else {
-				return nil, err
-			}
+		var v Video
+		if err := result.Decode(&v); err != nil {
+			return nil, err
 		}
-		res = append(res, id)
+		res = append(res, v.Id)
 	}
 	return res, nil
 }
@@ -0,0 +1,40 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoTombstoneRepository struct {
+	VideoCollection *mongo.Collection
+}
+
+func NewMongoTombstoneRepository(db *mongo.Database, videoCollectionName string) *MongoTombstoneRepository {
+	return &MongoTombstoneRepository{VideoCollection: db.Collection(videoCollectionName)}
+}
+
+func (m *MongoTombstoneRepository) TombstoneVideos(ctx context.Context, ids []string, status string, at time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	_, er0 := m.VideoCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "tombstonedAt": nil}, bson.M{"$set": bson.M{"tombstonedAt": at}})
+	if er0 != nil {
+		return 0, er0
+	}
+	res, er1 := m.VideoCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$ne": status}}, bson.M{"$set": bson.M{"status": status}})
+	if er1 != nil {
+		return 0, er1
+	}
+	return int(res.ModifiedCount), nil
+}
+
+func (m *MongoTombstoneRepository) PurgeVideos(ctx context.Context, status string, before time.Time) (int, error) {
+	res, err := m.VideoCollection.DeleteMany(ctx, bson.M{"status": status, "tombstonedAt": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
@@ -6,6 +6,11 @@ import (
 )
 
 func ExecuteAll(ctx context.Context, db *sql.DB, stmts ...Statement) (int64, error) {
+	return ExecuteAllAndThen(ctx, db, stmts)
+}
+
+// ExecuteAllAndThen does not count the rows after affects.
+func ExecuteAllAndThen(ctx context.Context, db *sql.DB, stmts []Statement, after ...Statement) (int64, error) {
 	if stmts == nil || len(stmts) == 0 {
 		return 0, nil
 	}
@@ -31,7 +36,12 @@ func ExecuteAll(ctx context.Context, db *sql.DB, stmts ...Statement) (int64, err
 		}
 		count = count + a2
 	}
+	for _, stmt := range after {
+		if _, er3 := tx.ExecContext(ctx, stmt.Query, stmt.Params...); er3 != nil {
+			tx.Rollback()
+			return count, er3
+		}
+	}
 	er6 := tx.Commit()
 	return count, er6
 }
-
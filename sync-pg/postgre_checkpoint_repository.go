@@ -0,0 +1,61 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/core-go/video"
+	"github.com/lib/pq"
+)
+
+type PostgreCheckpointRepository struct {
+	DB               *sql.DB
+	fieldsIndex      map[string]int
+	checkpointSchema *Schema
+}
+
+func NewPostgreCheckpointRepository(db *sql.DB) (*PostgreCheckpointRepository, error) {
+	var checkpoint video.SyncCheckpoint
+	modelType := reflect.TypeOf(checkpoint)
+	fieldsIndex, er1 := GetColumnIndexes(modelType)
+	if er1 != nil {
+		return nil, er1
+	}
+	schema := CreateSchema(modelType)
+	return &PostgreCheckpointRepository{DB: db, fieldsIndex: fieldsIndex, checkpointSchema: schema}, nil
+}
+
+func (s *PostgreCheckpointRepository) GetCheckpoint(ctx context.Context, id string) (*video.SyncCheckpoint, error) {
+	query := "select * from syncCheckpoint where id = $1 limit 1"
+	var res []video.SyncCheckpoint
+	err := QueryWithMapAndArray(ctx, s.DB, s.fieldsIndex, &res, pq.Array, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return &res[0], nil
+}
+
+func (s *PostgreCheckpointRepository) SaveCheckpoint(ctx context.Context, checkpoint video.SyncCheckpoint) (int, error) {
+	query, args, er1 := BuildToSaveWithArray("syncCheckpoint", checkpoint, DriverPostgres, pq.Array, s.checkpointSchema)
+	if er1 != nil {
+		return 0, er1
+	}
+	_, er2 := s.DB.ExecContext(ctx, query, args...)
+	if er2 != nil {
+		return 0, er2
+	}
+	return 1, nil
+}
+
+func (s *PostgreCheckpointRepository) DeleteCheckpoint(ctx context.Context, id string) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from syncCheckpoint where id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,52 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/core-go/video"
+	"github.com/lib/pq"
+)
+
+type PostgreJobRepository struct {
+	DB          *sql.DB
+	fieldsIndex map[string]int
+	jobSchema   *Schema
+}
+
+func NewPostgreJobRepository(db *sql.DB) (*PostgreJobRepository, error) {
+	var job video.SyncJob
+	modelType := reflect.TypeOf(job)
+	fieldsIndex, er1 := GetColumnIndexes(modelType)
+	if er1 != nil {
+		return nil, er1
+	}
+	schema := CreateSchema(modelType)
+	return &PostgreJobRepository{DB: db, fieldsIndex: fieldsIndex, jobSchema: schema}, nil
+}
+
+func (s *PostgreJobRepository) GetJob(ctx context.Context, id string) (*video.SyncJob, error) {
+	query := "select * from syncJob where id = $1 limit 1"
+	var res []video.SyncJob
+	err := QueryWithMapAndArray(ctx, s.DB, s.fieldsIndex, &res, pq.Array, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return &res[0], nil
+}
+
+func (s *PostgreJobRepository) SaveJob(ctx context.Context, job video.SyncJob) (int, error) {
+	query, args, er1 := BuildToSaveWithArray("syncJob", job, DriverPostgres, pq.Array, s.jobSchema)
+	if er1 != nil {
+		return 0, er1
+	}
+	_, er2 := s.DB.ExecContext(ctx, query, args...)
+	if er2 != nil {
+		return 0, er2
+	}
+	return 1, nil
+}
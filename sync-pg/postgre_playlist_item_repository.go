@@ -0,0 +1,52 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/core-go/video"
+	"github.com/lib/pq"
+)
+
+type PostgrePlaylistItemRepository struct {
+	DB                 *sql.DB
+	fieldsIndex        map[string]int
+	playlistItemSchema *Schema
+}
+
+func NewPostgrePlaylistItemRepository(db *sql.DB) (*PostgrePlaylistItemRepository, error) {
+	var item video.PlaylistItem
+	modelType := reflect.TypeOf(item)
+	fieldsIndex, er1 := GetColumnIndexes(modelType)
+	if er1 != nil {
+		return nil, er1
+	}
+	schema := CreateSchema(modelType)
+	return &PostgrePlaylistItemRepository{DB: db, fieldsIndex: fieldsIndex, playlistItemSchema: schema}, nil
+}
+
+func (s *PostgrePlaylistItemRepository) GetPlaylistItems(ctx context.Context, playlistId string) ([]video.PlaylistItem, error) {
+	query := "select * from playlistItem where playlistId = $1 order by position, videoId"
+	var res []video.PlaylistItem
+	err := QueryWithMapAndArray(ctx, s.DB, s.fieldsIndex, &res, pq.Array, query, playlistId)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *PostgrePlaylistItemRepository) SavePlaylistItems(ctx context.Context, items []video.PlaylistItem) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	statements, er0 := BuildToSaveBatchWithArray("playlistItem", items, DriverPostgres, pq.Array, s.playlistItemSchema)
+	if er0 != nil {
+		return 0, er0
+	}
+	result, er1 := ExecuteAll(ctx, s.DB, statements...)
+	if er1 != nil {
+		return 0, er1
+	}
+	return int(result), nil
+}
@@ -0,0 +1,33 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/core-go/video"
+	"github.com/lib/pq"
+)
+
+type PostgreStatisticsRepository struct {
+	DB               *sql.DB
+	statisticsSchema *Schema
+}
+
+func NewPostgreStatisticsRepository(db *sql.DB) *PostgreStatisticsRepository {
+	var statistics video.VideoStatistics
+	schema := CreateSchema(reflect.TypeOf(statistics))
+	return &PostgreStatisticsRepository{DB: db, statisticsSchema: schema}
+}
+
+func (s *PostgreStatisticsRepository) SaveStatistics(ctx context.Context, statistics []video.VideoStatistics) (int, error) {
+	statements, er0 := BuildToSaveBatchWithArray("videoStatistics", statistics, DriverPostgres, pq.Array, s.statisticsSchema)
+	if er0 != nil {
+		return 0, er0
+	}
+	result, er1 := ExecuteAll(ctx, s.DB, statements...)
+	if er1 != nil {
+		return 0, er1
+	}
+	return int(result), nil
+}
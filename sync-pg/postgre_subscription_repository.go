@@ -0,0 +1,46 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/core-go/video"
+	"github.com/lib/pq"
+)
+
+type PostgreSubscriptionRepository struct {
+	DB                 *sql.DB
+	subscriptionSchema *Schema
+}
+
+func NewPostgreSubscriptionRepository(db *sql.DB) *PostgreSubscriptionRepository {
+	var subscription video.Subscription
+	schema := CreateSchema(reflect.TypeOf(subscription))
+	return &PostgreSubscriptionRepository{DB: db, subscriptionSchema: schema}
+}
+
+// SaveSubscriptions upserts subscriptions, then deletes the other subscriptions of subscriberId, in one transaction.
+func (s *PostgreSubscriptionRepository) SaveSubscriptions(ctx context.Context, subscriberId string, subscriptions []video.Subscription) (int, error) {
+	channelIds := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		channelIds[i] = subscription.ChannelId
+	}
+	stale := Statement{
+		Query:  "delete from subscription where subscriberId = $1 and not (channelId = any($2))",
+		Params: []interface{}{subscriberId, pq.Array(channelIds)},
+	}
+	if len(subscriptions) == 0 {
+		_, er0 := ExecuteAll(ctx, s.DB, stale)
+		return 0, er0
+	}
+	statements, er1 := BuildToSaveBatchWithArray("subscription", subscriptions, DriverPostgres, pq.Array, s.subscriptionSchema)
+	if er1 != nil {
+		return 0, er1
+	}
+	result, er2 := ExecuteAllAndThen(ctx, s.DB, statements, stale)
+	if er2 != nil {
+		return 0, er2
+	}
+	return int(result), nil
+}
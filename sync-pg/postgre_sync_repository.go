@@ -74,12 +74,22 @@ func (s *PostgreVideoRepository) GetChannelSync(ctx context.Context, channelId s
 	return &channelSyncRes[0], nil
 }
 
+func (s *PostgreVideoRepository) GetChannelSyncs(ctx context.Context) ([]video.ChannelSync, error) {
+	query := "select * from channelSync"
+	var channelSyncRes []video.ChannelSync
+	err := QueryWithMap(ctx, s.DB, s.fieldsIndexChannelSync, &channelSyncRes, query)
+	if err != nil {
+		return nil, err
+	}
+	return channelSyncRes, nil
+}
+
 func (s *PostgreVideoRepository) SaveChannel(ctx context.Context, channel video.Channel) (int64, error) {
 	query, args, err1 := BuildToSaveWithArray("channel", channel, DriverPostgres, pq.Array, s.channelSchema)
 	if err1 != nil {
 		return 0, err1
 	}
-	_, err2 := s.DB.Exec(query, args...)
+	_, err2 := ExecuteAllAndThen(ctx, s.DB, []Statement{{Query: query, Params: args}}, channelSearch.statement(channel.Id))
 	if err2 != nil {
 		return 0, err2
 	}
@@ -87,13 +97,16 @@ func (s *PostgreVideoRepository) SaveChannel(ctx context.Context, channel video.
 }
 
 func (s *PostgreVideoRepository) GetVideoIds(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 	var question []string
 	var cc []interface{}
 	for i, v := range ids {
 		question = append(question, fmt.Sprintf("$%d", i+1))
 		cc = append(cc, v)
 	}
-	query := fmt.Sprintf(`select id from video where id in (%s)`, strings.Join(question, ","))
+	query := fmt.Sprintf(`select id from video where id in (%s) and coalesce(status, '') = ''`, strings.Join(question, ","))
 
 	rows, err := s.DB.Query(query, cc...)
 	if err != nil {
@@ -118,7 +131,11 @@ func (s *PostgreVideoRepository) SaveVideos(ctx context.Context, videos []video.
 		return 0, err0
 	}
 
-	result, err := ExecuteAll(ctx, s.DB, statements...)
+	ids := make([]string, len(videos))
+	for i, v := range videos {
+		ids[i] = v.Id
+	}
+	result, err := ExecuteAllAndThen(ctx, s.DB, statements, videoSearch.statement(ids...))
 	if err != nil {
 		return 0, err
 	}
@@ -132,7 +149,11 @@ func (s *PostgreVideoRepository) SavePlaylists(ctx context.Context, playlists []
 		return 0, err
 	}
 
-	result, err := ExecuteAll(ctx, s.DB, statements...)
+	ids := make([]string, len(playlists))
+	for i, playlist := range playlists {
+		ids[i] = playlist.Id
+	}
+	result, err := ExecuteAllAndThen(ctx, s.DB, statements, playlistSearch.statement(ids...))
 	if err != nil {
 		return 0, err
 	}
@@ -169,12 +190,12 @@ func (s *PostgreVideoRepository) SaveChannelSync(ctx context.Context, channel vi
 }
 
 func (s *PostgreVideoRepository) SavePlaylist(ctx context.Context, playlist video.Playlist) (int, error) {
-	statementss, err0 := BuildToSaveBatchWithArray("playlist", playlist, DriverPostgres, pq.Array, s.playlistSchema)
+	query, args, err0 := BuildToSaveWithArray("playlist", playlist, DriverPostgres, pq.Array, s.playlistSchema)
 	if err0 != nil {
 		return 0, err0
 	}
 
-	result, err := ExecuteAll(ctx, s.DB, statementss...)
+	result, err := ExecuteAllAndThen(ctx, s.DB, []Statement{{Query: query, Params: args}}, playlistSearch.statement(playlist.Id))
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,39 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PostgreTombstoneRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgreTombstoneRepository(db *sql.DB) *PostgreTombstoneRepository {
+	return &PostgreTombstoneRepository{DB: db}
+}
+
+func (s *PostgreTombstoneRepository) TombstoneVideos(ctx context.Context, ids []string, status string, at time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query := `update video set status = $1, tombstonedAt = coalesce(tombstonedAt, $2) where id = any($3) and status is distinct from $1`
+	res, err := s.DB.ExecContext(ctx, query, status, at, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *PostgreTombstoneRepository) PurgeVideos(ctx context.Context, status string, before time.Time) (int, error) {
+	res, err := s.DB.ExecContext(ctx, `delete from video where status = $1 and tombstonedAt < $2`, status, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
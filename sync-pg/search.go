@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var searchConfigs = map[string]string{
+	"da": "danish",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nb": "norwegian",
+	"nl": "dutch",
+	"nn": "norwegian",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+// SearchConfig returns "simple" for a language PostgreSQL has no configuration for.
+func SearchConfig(language string) string {
+	if config, ok := searchConfigs[strings.ToLower(strings.Split(language, "-")[0])]; ok {
+		return config
+	}
+	return "simple"
+}
+
+func searchConfig(column string) string {
+	languages := make([]string, 0, len(searchConfigs))
+	for language := range searchConfigs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	cases := make([]string, len(languages))
+	for i, language := range languages {
+		cases[i] = fmt.Sprintf(`when '%s' then '%s'`, language, searchConfigs[language])
+	}
+	return fmt.Sprintf(`(case lower(split_part(%s, '-', 1)) %s else 'simple' end)::regconfig`, column, strings.Join(cases, " "))
+}
+
+// searchTable also indexes the words unstemmed, so a query parsed with "simple" matches any language.
+type searchTable struct {
+	name    string
+	config  string
+	columns []string
+	weights string
+}
+
+const simpleConfig = `'simple'::regconfig`
+
+var (
+	channelSearch  = searchTable{name: "channel", config: simpleConfig, columns: []string{"title", "description"}, weights: "AC"}
+	playlistSearch = searchTable{name: "playlist", config: simpleConfig, columns: []string{"title", "description"}, weights: "AC"}
+	videoSearch    = searchTable{name: "video", config: searchConfig("defaultLanguage"), columns: []string{"title", "tags", "description"}, weights: "ABC"}
+)
+
+func (t searchTable) update() string {
+	vectors := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		text := fmt.Sprintf(`coalesce(%s, '')`, column)
+		if column == "tags" {
+			text = `coalesce(array_to_string(tags, ' '), '')`
+		}
+		vector := fmt.Sprintf(`to_tsvector(%s, %s)`, t.config, text)
+		if t.config != simpleConfig {
+			vector += fmt.Sprintf(` || to_tsvector(%s, %s)`, simpleConfig, text)
+		}
+		vectors[i] = fmt.Sprintf(`setweight(%s, '%c')`, vector, t.weights[i])
+	}
+	return fmt.Sprintf(`update %s set searchVector = %s`, t.name, strings.Join(vectors, " || "))
+}
+
+func (t searchTable) statement(ids ...string) Statement {
+	return Statement{Query: t.update() + ` where id = any($1)`, Params: []interface{}{pq.Array(ids)}}
+}
+
+// SearchSchema statements can be run again.
+func SearchSchema() []string {
+	var stmts []string
+	for _, t := range []searchTable{channelSearch, playlistSearch, videoSearch} {
+		stmts = append(stmts,
+			fmt.Sprintf(`alter table %s add column if not exists searchVector tsvector`, t.name),
+			fmt.Sprintf(`create index if not exists %s_searchVector on %s using gin (searchVector)`, t.name, t.name),
+			t.update()+` where searchVector is null`,
+		)
+	}
+	return stmts
+}
+
+func CreateSearchSchema(ctx context.Context, db *sql.DB) error {
+	for _, stmt := range SearchSchema() {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/core-go/video"
+)
+
+// ChannelPublisher drops and counts the events when Events is full.
+type ChannelPublisher struct {
+	Events  chan video.SyncEvent
+	dropped int64
+}
+
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{Events: make(chan video.SyncEvent, size)}
+}
+
+func (p *ChannelPublisher) Publish(ctx context.Context, events []video.SyncEvent) error {
+	for _, event := range events {
+		select {
+		case p.Events <- event:
+		default:
+			atomic.AddInt64(&p.dropped, 1)
+		}
+	}
+	return nil
+}
+
+// Dropped returns the number of events dropped because Events was full.
+func (p *ChannelPublisher) Dropped() int64 {
+	return atomic.LoadInt64(&p.dropped)
+}
@@ -0,0 +1,225 @@
+package sync
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+type JobRegistry interface {
+	Start(ctx context.Context, job video.SyncJob, run func(ctx context.Context) (int, error)) (*video.SyncJob, error)
+	GetJob(ctx context.Context, id string) (*video.SyncJob, error)
+	CancelJob(ctx context.Context, id string) (*video.SyncJob, error)
+}
+
+type runningJob struct {
+	job      video.SyncJob
+	progress *Progress
+	cancel   context.CancelFunc
+	finished *video.SyncJob
+}
+
+// DefaultJobRegistry keeps in memory a finished job it cannot save.
+type DefaultJobRegistry struct {
+	Repository   video.SyncJobRepository
+	Timeout      time.Duration
+	SaveInterval time.Duration
+	OnError      func(err error)
+	mutex        sync.Mutex
+	running      map[string]*runningJob
+}
+
+func NewJobRegistry(options ...video.SyncJobRepository) *DefaultJobRegistry {
+	var repository video.SyncJobRepository
+	if len(options) > 0 && options[0] != nil {
+		repository = options[0]
+	} else {
+		repository = NewMemoryJobRepository()
+	}
+	return &DefaultJobRegistry{Repository: repository, SaveInterval: 30 * time.Second, running: make(map[string]*runningJob)}
+}
+
+// Recover fails the running jobs not saved for stale, which must exceed the SaveInterval of every instance.
+func (g *DefaultJobRegistry) Recover(ctx context.Context, stale time.Duration) (int, error) {
+	jobs, er0 := g.Repository.GetJobs(ctx, video.SyncJobRunning)
+	if er0 != nil {
+		return 0, er0
+	}
+	now := time.Now()
+	count := 0
+	for _, job := range jobs {
+		g.mutex.Lock()
+		_, ok := g.running[job.Id]
+		g.mutex.Unlock()
+		updatedAt := job.UpdatedAt
+		if updatedAt == nil {
+			updatedAt = job.StartedAt
+		}
+		if ok || updatedAt != nil && now.Sub(*updatedAt) < stale {
+			continue
+		}
+		job.Status = video.SyncJobFailed
+		job.Errors = append(job.Errors, "interrupted")
+		job.EndedAt = &now
+		job.UpdatedAt = &now
+		if _, er1 := g.Repository.SaveJob(ctx, job); er1 != nil {
+			return count, er1
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (g *DefaultJobRegistry) Start(ctx context.Context, job video.SyncJob, run func(ctx context.Context) (int, error)) (*video.SyncJob, error) {
+	id, er0 := newJobId()
+	if er0 != nil {
+		return nil, er0
+	}
+	now := time.Now()
+	job.Id = id
+	job.Status = video.SyncJobRunning
+	job.CreatedAt = &now
+	job.StartedAt = &now
+	job.UpdatedAt = &now
+	_, er1 := g.Repository.SaveJob(ctx, job)
+	if er1 != nil {
+		return nil, er1
+	}
+	progress := &Progress{}
+	var jobCtx context.Context
+	var cancel context.CancelFunc
+	if g.Timeout > 0 {
+		jobCtx, cancel = context.WithTimeout(WithProgress(context.Background(), progress), g.Timeout)
+	} else {
+		jobCtx, cancel = context.WithCancel(WithProgress(context.Background(), progress))
+	}
+	r := &runningJob{job: job, progress: progress, cancel: cancel}
+	g.mutex.Lock()
+	g.running[id] = r
+	g.mutex.Unlock()
+	go func() {
+		defer cancel()
+		stop := make(chan struct{})
+		stopped := make(chan struct{})
+		go g.saveProgress(r, stop, stopped)
+		res, err := run(jobCtx)
+		close(stop)
+		<-stopped
+		end := time.Now()
+		finished := snapshot(r)
+		finished.Result = res
+		finished.EndedAt = &end
+		finished.UpdatedAt = &end
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				finished.Status = video.SyncJobCanceled
+			} else {
+				finished.Status = video.SyncJobFailed
+				finished.Errors = append(finished.Errors, err.Error())
+			}
+		} else {
+			finished.Status = video.SyncJobCompleted
+		}
+		_, er2 := g.Repository.SaveJob(context.Background(), finished)
+		if er2 != nil {
+			g.report(er2)
+		}
+		g.mutex.Lock()
+		if er2 != nil {
+			r.finished = &finished
+		} else {
+			delete(g.running, id)
+		}
+		g.mutex.Unlock()
+	}()
+	return &job, nil
+}
+
+func (g *DefaultJobRegistry) saveProgress(r *runningJob, stop chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+	if g.SaveInterval <= 0 {
+		<-stop
+		return
+	}
+	ticker := time.NewTicker(g.SaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			job := snapshot(r)
+			now := time.Now()
+			job.UpdatedAt = &now
+			if _, err := g.Repository.SaveJob(context.Background(), job); err != nil {
+				g.report(err)
+			}
+		}
+	}
+}
+
+func (g *DefaultJobRegistry) report(err error) {
+	if g.OnError != nil {
+		g.OnError(err)
+	}
+}
+
+func (g *DefaultJobRegistry) GetJob(ctx context.Context, id string) (*video.SyncJob, error) {
+	g.mutex.Lock()
+	r, ok := g.running[id]
+	var finished *video.SyncJob
+	if ok {
+		finished = r.finished
+	}
+	g.mutex.Unlock()
+	if finished != nil {
+		job := *finished
+		return &job, nil
+	}
+	if ok {
+		job := snapshot(r)
+		return &job, nil
+	}
+	return g.Repository.GetJob(ctx, id)
+}
+
+func (g *DefaultJobRegistry) CancelJob(ctx context.Context, id string) (*video.SyncJob, error) {
+	g.mutex.Lock()
+	r, ok := g.running[id]
+	var finished *video.SyncJob
+	if ok {
+		finished = r.finished
+	}
+	g.mutex.Unlock()
+	if !ok {
+		return g.Repository.GetJob(ctx, id)
+	}
+	if finished != nil {
+		job := *finished
+		return &job, nil
+	}
+	r.cancel()
+	job := snapshot(r)
+	job.Status = video.SyncJobCanceled
+	return &job, nil
+}
+
+func snapshot(r *runningJob) video.SyncJob {
+	job := r.job
+	job.Pages, job.Videos, job.Playlists, job.Errors = r.progress.Snapshot()
+	return job
+}
+
+func newJobId() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,168 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+type failingJobRepository struct {
+	*MemoryJobRepository
+	mutex sync.Mutex
+	fail  func(job video.SyncJob) bool
+	saves []video.SyncJob
+}
+
+func (r *failingJobRepository) SaveJob(ctx context.Context, job video.SyncJob) (int, error) {
+	r.mutex.Lock()
+	r.saves = append(r.saves, job)
+	fail := r.fail != nil && r.fail(job)
+	r.mutex.Unlock()
+	if fail {
+		return 0, errors.New("save failed")
+	}
+	return r.MemoryJobRepository.SaveJob(ctx, job)
+}
+
+func (r *failingJobRepository) statuses() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var statuses []string
+	for _, job := range r.saves {
+		statuses = append(statuses, job.Status)
+	}
+	return statuses
+}
+
+func TestJobRegistry(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		fail     func(job video.SyncJob) bool
+		run      func(ctx context.Context) (int, error)
+		status   string
+		reported int
+	}{
+		{
+			name: "completed",
+			run: func(ctx context.Context) (int, error) {
+				addPages(ctx, 3)
+				time.Sleep(30 * time.Millisecond)
+				return 5, nil
+			},
+			status: video.SyncJobCompleted,
+		},
+		{
+			name:   "failed",
+			run:    func(ctx context.Context) (int, error) { return 0, errors.New("quota") },
+			status: video.SyncJobFailed,
+		},
+		{
+			name:     "finished job not saved",
+			fail:     func(job video.SyncJob) bool { return job.Status != video.SyncJobRunning },
+			run:      func(ctx context.Context) (int, error) { return 2, nil },
+			status:   video.SyncJobCompleted,
+			reported: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &failingJobRepository{MemoryJobRepository: NewMemoryJobRepository(), fail: tc.fail}
+			registry := NewJobRegistry(repository)
+			registry.SaveInterval = 5 * time.Millisecond
+			var mutex sync.Mutex
+			reported := 0
+			registry.OnError = func(err error) {
+				mutex.Lock()
+				reported++
+				mutex.Unlock()
+			}
+			job, err := registry.Start(ctx, video.SyncJob{Type: "channel", TargetId: "chan1"}, tc.run)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res *video.SyncJob
+			for i := 0; i < 100; i++ {
+				if res, err = registry.GetJob(ctx, job.Id); err != nil {
+					t.Fatal(err)
+				}
+				if res != nil && res.Status != video.SyncJobRunning {
+					break
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if res == nil || res.Status != tc.status {
+				t.Fatalf("job = %+v; want %s", res, tc.status)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if reported != tc.reported {
+				t.Errorf("reported %d errors; want %d", reported, tc.reported)
+			}
+			statuses := repository.statuses()
+			if statuses[len(statuses)-1] != tc.status {
+				t.Errorf("last save %v; want %s", statuses, tc.status)
+			}
+		})
+	}
+}
+
+func TestJobRegistrySavesProgress(t *testing.T) {
+	ctx := context.Background()
+	repository := &failingJobRepository{MemoryJobRepository: NewMemoryJobRepository()}
+	registry := NewJobRegistry(repository)
+	registry.SaveInterval = 5 * time.Millisecond
+	done := make(chan struct{})
+	job, err := registry.Start(ctx, video.SyncJob{Type: "channel"}, func(ctx context.Context) (int, error) {
+		addPages(ctx, 2)
+		<-done
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored *video.SyncJob
+	for i := 0; i < 100; i++ {
+		stored, _ = repository.MemoryJobRepository.GetJob(ctx, job.Id)
+		if stored.Pages == 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(done)
+	if stored.Pages != 2 || stored.Status != video.SyncJobRunning {
+		t.Errorf("stored = %+v; want 2 pages while running", stored)
+	}
+}
+
+func TestJobRegistryRecover(t *testing.T) {
+	ctx := context.Background()
+	repository := NewMemoryJobRepository()
+	old := time.Now().Add(-time.Hour)
+	recent := time.Now()
+	jobs := []video.SyncJob{
+		{Id: "orphan", Status: video.SyncJobRunning, StartedAt: &old, UpdatedAt: &old},
+		{Id: "alive", Status: video.SyncJobRunning, StartedAt: &old, UpdatedAt: &recent},
+		{Id: "done", Status: video.SyncJobCompleted, StartedAt: &old, UpdatedAt: &old},
+	}
+	for _, job := range jobs {
+		repository.SaveJob(ctx, job)
+	}
+	res, err := NewJobRegistry(repository).Recover(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != 1 {
+		t.Errorf("recovered %d jobs; want 1", res)
+	}
+	for id, status := range map[string]string{"orphan": video.SyncJobFailed, "alive": video.SyncJobRunning, "done": video.SyncJobCompleted} {
+		job, _ := repository.GetJob(ctx, id)
+		if job.Status != status {
+			t.Errorf("%s = %s; want %s", id, job.Status, status)
+		}
+	}
+}
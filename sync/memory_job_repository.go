@@ -0,0 +1,46 @@
+package sync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/core-go/video"
+)
+
+type MemoryJobRepository struct {
+	mutex sync.RWMutex
+	jobs  map[string]video.SyncJob
+}
+
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{jobs: make(map[string]video.SyncJob)}
+}
+
+func (m *MemoryJobRepository) GetJob(ctx context.Context, id string) (*video.SyncJob, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (m *MemoryJobRepository) GetJobs(ctx context.Context, status string) ([]video.SyncJob, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	var jobs []video.SyncJob
+	for _, job := range m.jobs {
+		if job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *MemoryJobRepository) SaveJob(ctx context.Context, job video.SyncJob) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.jobs[job.Id] = job
+	return 1, nil
+}
@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/core-go/video"
+)
+
+// NDJSONPublisher writes the events to Writer as newline delimited JSON, an event a line.
+type NDJSONPublisher struct {
+	mutex  sync.Mutex
+	Writer io.Writer
+}
+
+func NewNDJSONPublisher(writer io.Writer) *NDJSONPublisher {
+	return &NDJSONPublisher{Writer: writer}
+}
+
+// NewNDJSONFilePublisher appends the events to the file of path, which it creates if it does not exist.
+func NewNDJSONFilePublisher(path string) (*NDJSONPublisher, error) {
+	file, er0 := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if er0 != nil {
+		return nil, er0
+	}
+	return NewNDJSONPublisher(file), nil
+}
+
+// Publish writes the events of a call together, so the lines of events published at the same time do not mix.
+func (p *NDJSONPublisher) Publish(ctx context.Context, events []video.SyncEvent) error {
+	var data []byte
+	for _, event := range events {
+		line, er0 := json.Marshal(event)
+		if er0 != nil {
+			return er0
+		}
+		data = append(append(data, line...), '\n')
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	_, er1 := p.Writer.Write(data)
+	return er1
+}
+
+// Close closes Writer when it is a file or another io.Closer.
+func (p *NDJSONPublisher) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if closer, ok := p.Writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
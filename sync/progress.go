@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"context"
+	"sync"
+)
+
+type progressKey struct{}
+
+type Progress struct {
+	mutex     sync.Mutex
+	Pages     int
+	Videos    int
+	Playlists int
+	Errors    []string
+}
+
+func WithProgress(ctx context.Context, progress *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+func GetProgress(ctx context.Context) *Progress {
+	progress, _ := ctx.Value(progressKey{}).(*Progress)
+	return progress
+}
+
+func (p *Progress) Snapshot() (int, int, int, []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	errors := make([]string, len(p.Errors))
+	copy(errors, p.Errors)
+	return p.Pages, p.Videos, p.Playlists, errors
+}
+
+func (p *Progress) AddError(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.Errors = append(p.Errors, err.Error())
+}
+
+func addPages(ctx context.Context, n int) {
+	if p := GetProgress(ctx); p != nil {
+		p.mutex.Lock()
+		p.Pages = p.Pages + n
+		p.mutex.Unlock()
+	}
+}
+
+func addVideos(ctx context.Context, n int) {
+	if p := GetProgress(ctx); p != nil {
+		p.mutex.Lock()
+		p.Videos = p.Videos + n
+		p.mutex.Unlock()
+	}
+}
+
+func addPlaylists(ctx context.Context, n int) {
+	if p := GetProgress(ctx); p != nil {
+		p.mutex.Lock()
+		p.Playlists = p.Playlists + n
+		p.mutex.Unlock()
+	}
+}
@@ -0,0 +1,150 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/youtube"
+)
+
+// Scheduler calls OnError with the errors of each run, quota exhaustion included.
+type Scheduler struct {
+	Service         video.SyncService
+	Repository      video.SyncRepository
+	Intervals       map[int]time.Duration
+	DefaultInterval time.Duration
+	Tick            time.Duration
+	Concurrency     int
+	OnError         func(err error)
+	mutex           sync.Mutex
+	cancel          context.CancelFunc
+	done            chan struct{}
+}
+
+func NewScheduler(service video.SyncService, repository video.SyncRepository, intervals map[int]time.Duration, tick time.Duration, concurrency int) *Scheduler {
+	if tick <= 0 {
+		tick = 5 * time.Minute
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Scheduler{
+		Service:         service,
+		Repository:      repository,
+		Intervals:       intervals,
+		DefaultInterval: 24 * time.Hour,
+		Tick:            tick,
+		Concurrency:     concurrency,
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called or ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		if err := s.Run(runCtx); err != nil && !errors.Is(err, context.Canceled) {
+			s.report(err)
+		}
+	}(s.done)
+}
+
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	cancel := s.cancel
+	done := s.done
+	s.cancel = nil
+	s.done = nil
+	s.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Run blocks until ctx is done, so it can be used as the main loop of a standalone worker.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Tick)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			s.report(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) report(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	channelSyncs, er0 := s.Repository.GetChannelSyncs(ctx)
+	if er0 != nil {
+		return 0, er0
+	}
+	channelIds := s.Due(channelSyncs, time.Now())
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errSync error
+	sum := 0
+	sem := make(chan struct{}, s.Concurrency)
+	for _, channelId := range channelIds {
+		mutex.Lock()
+		exhausted := youtube.IsQuotaExceeded(errSync)
+		mutex.Unlock()
+		if exhausted {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return sum, ctx.Err()
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(channelId string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := s.Service.SyncChannel(ctx, channelId)
+			mutex.Lock()
+			sum = sum + res
+			if err != nil {
+				errSync = err
+			}
+			mutex.Unlock()
+		}(channelId)
+	}
+	wg.Wait()
+	return sum, errSync
+}
+
+func (s *Scheduler) Due(channelSyncs []video.ChannelSync, now time.Time) []string {
+	var channelIds []string
+	for _, v := range channelSyncs {
+		interval, ok := s.Intervals[v.Level]
+		if !ok {
+			interval = s.DefaultInterval
+		}
+		if v.Synctime == nil || now.Sub(*v.Synctime) >= interval {
+			channelIds = append(channelIds, v.Id)
+		}
+	}
+	return channelIds
+}
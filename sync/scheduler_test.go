@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/youtube"
+)
+
+type channelSyncRepository struct {
+	video.SyncRepository
+	channelSyncs []video.ChannelSync
+	err          error
+}
+
+func (r *channelSyncRepository) GetChannelSyncs(ctx context.Context) ([]video.ChannelSync, error) {
+	return r.channelSyncs, r.err
+}
+
+type channelSyncService struct {
+	video.SyncService
+	err error
+}
+
+func (s *channelSyncService) SyncChannel(ctx context.Context, channelId string) (int, error) {
+	return 1, s.err
+}
+
+func TestSchedulerReportsErrors(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	due := []video.ChannelSync{{Id: "chan1", Synctime: &old}}
+	tests := []struct {
+		name       string
+		repository *channelSyncRepository
+		service    *channelSyncService
+		reported   bool
+	}{
+		{"idle", &channelSyncRepository{}, &channelSyncService{}, false},
+		{"synced", &channelSyncRepository{channelSyncs: due}, &channelSyncService{}, false},
+		{"repository error", &channelSyncRepository{err: errors.New("connection refused")}, &channelSyncService{}, true},
+		{"quota exceeded", &channelSyncRepository{channelSyncs: due}, &channelSyncService{err: &youtube.ErrQuotaExceeded{Endpoint: "channels"}}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheduler := NewScheduler(tc.service, tc.repository, nil, time.Hour, 1)
+			var mutex sync.Mutex
+			var errs []error
+			scheduler.OnError = func(err error) {
+				mutex.Lock()
+				errs = append(errs, err)
+				mutex.Unlock()
+			}
+			scheduler.Start(context.Background())
+			time.Sleep(20 * time.Millisecond)
+			scheduler.Stop()
+			mutex.Lock()
+			defer mutex.Unlock()
+			if (len(errs) > 0) != tc.reported {
+				t.Errorf("reported %v; want reported %v", errs, tc.reported)
+			}
+		})
+	}
+}
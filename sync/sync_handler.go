@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,11 +12,19 @@ import (
 
 type SyncHandler struct {
 	sync SyncService
+	jobs JobRegistry
 }
 
 type ChannelId struct {
+	ChannelId string `json:"channelId,omitempty"`
+	Level     int    `json:"level,omitempty"` // Deprecated: a channel is synced whole, a level is rejected; see Subscriptions.
+}
+
+// Subscriptions starts a crawl of the subscriptions of ChannelId, Level subscriptions deep and of at most Max channels.
+type Subscriptions struct {
 	ChannelId string `json:"channelId,omitempty"`
 	Level     int    `json:"level,omitempty"`
+	Max       int    `json:"max,omitempty"`
 }
 
 type PlaylistId struct {
@@ -23,8 +32,14 @@ type PlaylistId struct {
 	Level      int    `json:"level,omitempty"`
 }
 
-func NewSyncHandler(syncService SyncService) *SyncHandler {
-	return &SyncHandler{sync: syncService}
+func NewSyncHandler(syncService SyncService, options ...JobRegistry) *SyncHandler {
+	var jobs JobRegistry
+	if len(options) > 0 && options[0] != nil {
+		jobs = options[0]
+	} else {
+		jobs = NewJobRegistry()
+	}
+	return &SyncHandler{sync: syncService, jobs: jobs}
 }
 
 func (h *SyncHandler) SyncChannel(w http.ResponseWriter, r *http.Request) {
@@ -34,18 +49,23 @@ func (h *SyncHandler) SyncChannel(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, er1.Error(), http.StatusBadRequest)
 		return
 	}
-	resultChannel, er2 := h.sync.SyncChannel(r.Context(), channelId.ChannelId)
-	if er2 != nil {
-		http.Error(w, er2.Error(), http.StatusBadRequest)
+	if len(channelId.ChannelId) == 0 {
+		http.Error(w, "channelId cannot be empty", http.StatusBadRequest)
 		return
 	}
-	result := ""
-	if resultChannel > 0 {
-		result = fmt.Sprintf(`Sync %d channel successfully`, resultChannel)
-	} else {
-		result = "Invalid channel to sync"
+	if channelId.Level != 0 {
+		http.Error(w, "level is not supported for a channel, sync its subscriptions instead", http.StatusBadRequest)
+		return
+	}
+	job := SyncJob{Type: "channel", TargetId: channelId.ChannelId}
+	result, er2 := h.jobs.Start(r.Context(), job, func(ctx context.Context) (int, error) {
+		return h.sync.SyncChannel(ctx, channelId.ChannelId)
+	})
+	if er2 != nil {
+		http.Error(w, er2.Error(), http.StatusInternalServerError)
+		return
 	}
-	respond(w, result)
+	respondWithStatus(w, http.StatusAccepted, result)
 }
 
 func (h *SyncHandler) SyncPlaylist(w http.ResponseWriter, r *http.Request) {
@@ -55,18 +75,55 @@ func (h *SyncHandler) SyncPlaylist(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, er1.Error(), http.StatusBadRequest)
 		return
 	}
-	resultChannel, er2 := h.sync.SyncPlaylist(r.Context(), playlistId.PlaylistId, &playlistId.Level)
+	if len(playlistId.PlaylistId) == 0 {
+		http.Error(w, "playlistId cannot be empty", http.StatusBadRequest)
+		return
+	}
+	job := SyncJob{Type: "playlist", TargetId: playlistId.PlaylistId, Level: playlistId.Level}
+	result, er2 := h.jobs.Start(r.Context(), job, func(ctx context.Context) (int, error) {
+		return h.sync.SyncPlaylist(ctx, playlistId.PlaylistId, &playlistId.Level)
+	})
 	if er2 != nil {
-		http.Error(w, er2.Error(), http.StatusBadRequest)
+		http.Error(w, er2.Error(), http.StatusInternalServerError)
 		return
 	}
-	result := ""
-	if resultChannel > 0 {
-		result = fmt.Sprintf("Sync playlist successfully")
-	} else {
-		result = "Invalid playlist to sync"
+	respondWithStatus(w, http.StatusAccepted, result)
+}
+
+func (h *SyncHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id := GetParam(r, 0)
+	if len(id) <= 0 {
+		http.Error(w, "Id cannot empty", http.StatusBadRequest)
+		return
+	}
+	job, err := h.jobs.GetJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, fmt.Sprintf("job %s not found", id), http.StatusNotFound)
+		return
 	}
-	respond(w, result)
+	respond(w, job)
+}
+
+func (h *SyncHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id := GetParam(r, 0)
+	if len(id) <= 0 {
+		http.Error(w, "Id cannot empty", http.StatusBadRequest)
+		return
+	}
+	job, err := h.jobs.CancelJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, fmt.Sprintf("job %s not found", id), http.StatusNotFound)
+		return
+	}
+	respond(w, job)
 }
 
 func (h *SyncHandler) SyncSubscription(w http.ResponseWriter, r *http.Request) {
@@ -83,20 +140,49 @@ func (h *SyncHandler) SyncSubscription(w http.ResponseWriter, r *http.Request) {
 	respond(w, resultChannel)
 }
 
+func (h *SyncHandler) SyncSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var subscriptions Subscriptions
+	er1 := json.NewDecoder(r.Body).Decode(&subscriptions)
+	if er1 != nil {
+		http.Error(w, er1.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(subscriptions.ChannelId) == 0 {
+		http.Error(w, "channelId cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if subscriptions.Level < 0 || subscriptions.Max < 0 {
+		http.Error(w, "level and max cannot be negative", http.StatusBadRequest)
+		return
+	}
+	job := SyncJob{Type: "subscriptions", TargetId: subscriptions.ChannelId, Level: subscriptions.Level}
+	result, er2 := h.jobs.Start(r.Context(), job, func(ctx context.Context) (int, error) {
+		return h.sync.SyncSubscriptions(ctx, subscriptions.ChannelId, subscriptions.Level, subscriptions.Max)
+	})
+	if er2 != nil {
+		http.Error(w, er2.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondWithStatus(w, http.StatusAccepted, result)
+}
+
 func respond(w http.ResponseWriter, result interface{}) error {
+	return respondWithStatus(w, http.StatusOK, result)
+}
+func respondWithStatus(w http.ResponseWriter, status int, result interface{}) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(result)
 	return err
 }
-func GetParam(r *http.Request, options... int) string {
+func GetParam(r *http.Request, options ...int) string {
 	offset := 0
 	if len(options) > 0 && options[0] > 0 {
 		offset = options[0]
 	}
 	s := r.URL.Path
 	params := strings.Split(s, "/")
-	i := len(params)-1-offset
+	i := len(params) - 1 - offset
 	if i >= 0 {
 		return params[i]
 	} else {
@@ -6,20 +6,33 @@ import (
 	"time"
 
 	"github.com/core-go/video"
-	"github.com/core-go/video/youtube"
 )
 
+// DefaultSyncService uses its optional repositories when they are set. VideoUpdated events need Videos.
 type DefaultSyncService struct {
-	Client     *youtube.YoutubeSyncClient
-	Repository video.SyncRepository
+	Client        video.ContextSyncClient
+	Repository    video.SyncRepository
+	Checkpoint    video.SyncCheckpointRepository
+	Statistics    video.VideoStatisticsRepository
+	Subscriptions video.SubscriptionRepository
+	PlaylistItems video.PlaylistItemRepository
+	Tombstones    video.TombstoneRepository
+	Events        video.SyncEventPublisher
+	Videos        video.VideoService
+	Rescan        bool
 }
 
-func NewDefaultSyncService(client *youtube.YoutubeSyncClient, repository video.SyncRepository) *DefaultSyncService {
-	return &DefaultSyncService{Client: client, Repository: repository}
+func NewDefaultSyncService(client video.ContextSyncClient, repository video.SyncRepository, options ...video.SyncCheckpointRepository) *DefaultSyncService {
+	var checkpoint video.SyncCheckpointRepository
+	if len(options) > 0 && options[0] != nil {
+		checkpoint = options[0]
+	}
+	return &DefaultSyncService{Client: client, Repository: repository, Checkpoint: checkpoint}
 }
 
 func (d *DefaultSyncService) SyncChannel(ctx context.Context, channelId string) (int, error) {
-	return syncChannel(ctx, d, channelId)
+	res, _, err := syncChannel(ctx, d, channelId)
+	return res, err
 }
 
 func (d *DefaultSyncService) SyncChannels(ctx context.Context, channelIds []string) (int, error) {
@@ -34,6 +47,9 @@ func (d *DefaultSyncService) SyncChannels(ctx context.Context, channelIds []stri
 			tam += resC
 			if err != nil {
 				errSync = err
+				if p := GetProgress(ctx); p != nil {
+					p.AddError(err)
+				}
 			}
 		}(&wg)
 	}
@@ -66,6 +82,9 @@ func (d *DefaultSyncService) SyncPlaylists(ctx context.Context, playlistIds []st
 			tam += resC
 			if err != nil {
 				errSync = err
+				if p := GetProgress(ctx); p != nil {
+					p.AddError(err)
+				}
 			}
 		}(&wg)
 	}
@@ -82,10 +101,14 @@ func (d *DefaultSyncService) GetSubscriptions(ctx context.Context, channelId str
 	flag := true
 	mine := ""
 	for flag {
-		subscriptions, er0 := d.Client.GetSubscriptions(channelId, mine, 50, nextPageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		subscriptions, er0 := d.Client.GetSubscriptions(ctx, channelId, mine, 50, nextPageToken)
 		if er0 != nil {
 			return nil, er0
 		}
+		addPages(ctx, 1)
 		nextPageToken = subscriptions.NextPageToken
 		if len(nextPageToken) <= 0 {
 			flag = false
@@ -95,7 +118,74 @@ func (d *DefaultSyncService) GetSubscriptions(ctx context.Context, channelId str
 	return channels, nil
 }
 
-func syncChannel(ctx context.Context, d *DefaultSyncService, channelId string) (int, error) {
+// SyncSubscriptions syncs channelId and its subscriptions breadth first, down to level, at most max when positive.
+func (d *DefaultSyncService) SyncSubscriptions(ctx context.Context, channelId string, level int, max int) (int, error) {
+	visited := map[string]bool{channelId: true}
+	channelIds := []string{channelId}
+	count := 0
+	for depth := 0; len(channelIds) > 0; depth++ {
+		var next []string
+		for _, id := range channelIds {
+			if max > 0 && count >= max {
+				return count, nil
+			}
+			if err := ctx.Err(); err != nil {
+				return count, err
+			}
+			_, subscriptions, err := syncChannel(ctx, d, id)
+			if err != nil {
+				if id == channelId {
+					return 0, err
+				}
+				if p := GetProgress(ctx); p != nil {
+					p.AddError(err)
+				}
+				continue
+			}
+			count++
+			if depth >= level {
+				continue
+			}
+			for _, subscription := range subscriptions {
+				if !visited[subscription] {
+					visited[subscription] = true
+					next = append(next, subscription)
+				}
+			}
+		}
+		channelIds = next
+	}
+	return count, nil
+}
+
+// Purge uses DefaultPurgePolicy when policy is nil.
+func (d *DefaultSyncService) Purge(ctx context.Context, policy video.PurgePolicy) (int, error) {
+	if d.Tombstones == nil {
+		return 0, nil
+	}
+	if policy == nil {
+		policy = video.DefaultPurgePolicy
+	}
+	now := time.Now()
+	count := 0
+	for _, status := range video.VideoStatuses {
+		retention, ok := policy[status]
+		if !ok || retention <= 0 {
+			continue
+		}
+		res, err := d.Tombstones.PurgeVideos(ctx, status, now.Add(-retention))
+		if err != nil {
+			return count, err
+		}
+		count = count + res
+	}
+	return count, nil
+}
+
+// syncChannel also returns the ids of the channels channelId subscribes to.
+func syncChannel(ctx context.Context, d *DefaultSyncService, channelId string) (int, []string, error) {
+	ctx, cancel := context.WithCancel(withRun(ctx))
+	defer cancel()
 	channelSync := make(chan *video.ChannelSync)
 	errChannelSync := make(chan error)
 	Channel := make(chan *video.Channel)
@@ -106,25 +196,28 @@ func syncChannel(ctx context.Context, d *DefaultSyncService, channelId string) (
 		errChannelSync <- err
 	}()
 	go func() {
-		result, err := d.Client.GetChannel(channelId)
+		result, err := d.Client.GetChannel(ctx, channelId)
 		Channel <- result
 		errChannel <- err
 	}()
 	resultChannelSync := <-channelSync
-	resultChannel := <-Channel
 	er0 := <-errChannelSync
+	if er0 != nil {
+		cancel()
+	}
+	resultChannel := <-Channel
 	er1 := <-errChannel
 	if er0 != nil {
-		return 0, er0
+		return 0, nil, er0
 	}
 	if er1 != nil {
-		return 0, er1
+		return 0, nil, er1
 	}
 	result, er2 := checkAndSyncUpload(ctx, resultChannelSync, resultChannel, d)
 	if er2 != nil {
-		return 0, er2
+		return 0, nil, er2
 	}
-	return result, er2
+	return result, resultChannel.ChannelList, er2
 }
 
 func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, channel *video.Channel, d *DefaultSyncService) (int, error) {
@@ -135,7 +228,7 @@ func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, cha
 		var syncVideos bool
 		var syncCollection bool
 		var timestamp *time.Time
-		if channelSync != nil {
+		if channelSync != nil && !d.Rescan {
 			timestamp = channelSync.Synctime
 		} else {
 			timestamp = nil
@@ -156,8 +249,10 @@ func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, cha
 		er2Chan := make(chan error)
 		resSub := make(chan []video.Channel)
 		er3Chan := make(chan error)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
 		go func() {
-			res, err := syncUploads(ctx, channel.Uploads, d, timestamp)
+			res, err := syncUploads(ctx, channel.Id, channel.Uploads, d, timestamp)
 			rChan <- res
 			er1Chan <- err
 		}()
@@ -173,8 +268,14 @@ func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, cha
 		}()
 		r := <-rChan
 		er1 := <-er1Chan
+		if er1 != nil {
+			cancel()
+		}
 		result := <-resultChan
 		er2 := <-er2Chan
+		if er2 != nil {
+			cancel()
+		}
 		subChan := <-resSub
 		er3 := <-er3Chan
 		if er1 != nil {
@@ -192,16 +293,30 @@ func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, cha
 		for _, v := range subChan {
 			channel.ChannelList = append(channel.ChannelList, v.Id)
 		}
+		if d.Subscriptions != nil {
+			subscriptions := make([]video.Subscription, len(subChan))
+			for i, v := range subChan {
+				subscriptions[i] = video.NewSubscription(channel.Id, v)
+			}
+			if _, err := d.Subscriptions.SaveSubscriptions(ctx, channel.Id, subscriptions); err != nil {
+				return 0, err
+			}
+		}
 		if syncCollection {
 			channel.PlaylistCount = &result.Count
 			channel.PlaylistItemCount = &result.All
 			channel.PlaylistVideoCount = &result.VideoCount
 			channel.PlaylistVideoItemCount = &result.AllVideoCount
 		}
+		level := 0
+		if channelSync != nil {
+			level = channelSync.Level
+		}
 		channelSync := video.ChannelSync{
 			Id:       channel.Id,
 			Synctime: &date,
 			Uploads:  channel.Uploads,
+			Level:    level,
 		}
 		er4Chan := make(chan error)
 		go func() {
@@ -216,21 +331,30 @@ func checkAndSyncUpload(ctx context.Context, channelSync *video.ChannelSync, cha
 		if er5 != nil {
 			return 0, er5
 		}
+		publish(ctx, d, video.SyncEvent{Type: video.ChannelSynced, ChannelId: channel.Id, Count: r.Count})
 		return res, nil
 	}
 }
 
 func syncChannelPlaylists(ctx context.Context, channelId string, syncVideos bool, saveCollection bool, d *DefaultSyncService) (*video.PlaylistResult, error) {
-	nextPageToken := ""
+	checkpoint, er := getCheckpoint(ctx, d, channelId, channelId)
+	if er != nil {
+		return nil, er
+	}
+	nextPageToken := checkpoint.PageToken
 	flag := true
-	count := 0
-	all := 0
-	allVideoCount := 0
+	count := checkpoint.Count
+	all := checkpoint.Total
+	allVideoCount := checkpoint.VideoCount
 	for flag {
-		channelPlaylists, er0 := d.Client.GetChannelPlaylists(channelId, 50, nextPageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		channelPlaylists, er0 := d.Client.GetChannelPlaylists(ctx, channelId, 50, nextPageToken)
 		if er0 != nil {
 			return nil, er0
 		}
+		addPages(ctx, 1)
 		all = channelPlaylists.Total
 		count = count + len(channelPlaylists.List)
 		var playlistIds []string
@@ -249,18 +373,32 @@ func syncChannelPlaylists(ctx context.Context, channelId string, syncVideos bool
 			er1Chan <- err
 		}()
 		go func() {
-			_, err := syncVideosOfPlaylists(ctx, playlistIds, syncVideos, saveCollection, d)
+			_, err := syncVideosOfPlaylists(ctx, channelId, playlistIds, syncVideos, saveCollection, d)
 			er2Chan <- err
 		}()
 		//_,er2 := syncVideosOfPlaylists(ctx, playlistIds, syncVideos, saveCollection, d)
 		er1 := <-er1Chan
+		er2 := <-er2Chan
 		if er1 != nil {
 			return nil, er1
 		}
-		er2 := <-er2Chan
 		if er2 != nil {
 			return nil, er2
 		}
+		if flag {
+			checkpoint.PageToken = nextPageToken
+			checkpoint.Count = count
+			checkpoint.Total = all
+			checkpoint.VideoCount = allVideoCount
+			_, er3 := saveCheckpoint(ctx, d, *checkpoint)
+			if er3 != nil {
+				return nil, er3
+			}
+		}
+	}
+	_, er4 := deleteCheckpoint(ctx, d, channelId)
+	if er4 != nil {
+		return nil, er4
 	}
 	return &video.PlaylistResult{
 		Count:         count,
@@ -269,24 +407,39 @@ func syncChannelPlaylists(ctx context.Context, channelId string, syncVideos bool
 	}, nil
 }
 
-func syncUploads(ctx context.Context, uploads string, d *DefaultSyncService, timestamp *time.Time) (*video.VideoResult, error) {
-	nextPageToken := ""
+func syncUploads(ctx context.Context, channelId string, uploads string, d *DefaultSyncService, timestamp *time.Time) (*video.VideoResult, error) {
+	checkpoint, er0 := getCheckpoint(ctx, d, uploads, channelId)
+	if er0 != nil {
+		return nil, er0
+	}
+	nextPageToken := checkpoint.PageToken
 	flag := true
-	success := 0
-	count := 0
-	all := 0
+	success := checkpoint.Success
+	count := checkpoint.Count
+	all := checkpoint.Total
 	videoResult := video.VideoResult{}
-	var last *time.Time
+	last := checkpoint.LastUpload
+	complete := timestamp == nil && len(nextPageToken) == 0
+	var ids []string
+	var items []video.PlaylistVideo
 	for flag {
-		playlistVideos, er1 := d.Client.GetPlaylistVideos(uploads, 50, nextPageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		playlistVideos, er1 := d.Client.GetPlaylistVideos(ctx, uploads, 50, nextPageToken)
 		if er1 != nil {
 			return nil, er1
 		}
+		addPages(ctx, 1)
 		all = playlistVideos.Total
 		count = count + len(playlistVideos.List)
 		if last == nil && len(playlistVideos.List) > 0 {
 			last = playlistVideos.List[0].PublishedAt
 		}
+		for _, v := range playlistVideos.List {
+			ids = append(ids, v.Id)
+		}
+		items = append(items, playlistVideos.List...)
 		newVideos := getNewVideos(playlistVideos.List, timestamp)
 		if len(playlistVideos.List) > len(newVideos) {
 			nextPageToken = ""
@@ -296,11 +449,34 @@ func syncUploads(ctx context.Context, uploads string, d *DefaultSyncService, tim
 		if nextPageToken == "" {
 			flag = false
 		}
+		if d.Statistics != nil {
+			newVideos = playlistVideos.List
+		}
 		r, er2 := saveVideos(ctx, newVideos, d)
 		if er2 != nil {
 			return nil, er2
 		}
 		success = success + r
+		if flag {
+			checkpoint.PageToken = nextPageToken
+			checkpoint.Count = count
+			checkpoint.Total = all
+			checkpoint.Success = success
+			checkpoint.LastUpload = last
+			_, er3 := saveCheckpoint(ctx, d, *checkpoint)
+			if er3 != nil {
+				return nil, er3
+			}
+		}
+	}
+	_, er4 := deleteCheckpoint(ctx, d, uploads)
+	if er4 != nil {
+		return nil, er4
+	}
+	if complete {
+		if er5 := savePlaylistItems(ctx, d, uploads, ids, items); er5 != nil {
+			return nil, er5
+		}
 	}
 	videoResult.Count = success
 	videoResult.All = all
@@ -330,52 +506,219 @@ func addSeconds(date *time.Time, number int) *time.Time {
 	return &newDate
 }
 
+// saveVideos tombstones the deleted and private videos instead of saving them.
 func saveVideos(ctx context.Context, newVideos []video.PlaylistVideo, d *DefaultSyncService) (int, error) {
-	if len(newVideos) == 0 || newVideos == nil {
-		return 0, nil
-	} else {
-		if d == nil {
-			return len(newVideos), nil
+	if len(newVideos) == 0 || d == nil {
+		return len(newVideos), nil
+	}
+	var videoIds []string
+	statuses := make(map[string]string)
+	for _, v := range newVideos {
+		if status := video.PlaylistVideoStatus(v); len(status) > 0 {
+			statuses[v.Id] = status
 		} else {
-			if d == nil {
-				return len(newVideos), nil
-			} else {
-				var videoIds []string
-				for _, v := range newVideos {
-					videoIds = append(videoIds, v.Id)
-				}
-				ids, er0 := d.Repository.GetVideoIds(ctx, videoIds)
-				if er0 != nil {
-					return 0, er0
-				}
-				newIds := notIn(videoIds, ids)
-				if len(newIds) == 0 {
-					return 0, nil
-				} else {
-					videos, er1 := d.Client.GetVideos(newIds)
-					if er1 != nil {
-						return 0, er1
-					}
-					if videos != nil && len(videos.List) > 0 {
-						res, er2 := d.Repository.SaveVideos(ctx, videos.List)
-						if er2 != nil {
-							return 0, er2
-						}
-						return res, nil
-					} else {
-						return 0, nil
-					}
+			videoIds = append(videoIds, v.Id)
+		}
+	}
+	if er0 := tombstoneVideos(ctx, d, nil, nil, statuses); er0 != nil {
+		return 0, er0
+	}
+	if len(videoIds) == 0 {
+		return 0, nil
+	}
+	ids, er1 := d.Repository.GetVideoIds(ctx, videoIds)
+	if er1 != nil {
+		return 0, er1
+	}
+	newIds := notIn(videoIds, ids)
+	fetchIds := newIds
+	if d.Statistics != nil {
+		fetchIds = videoIds
+	}
+	if len(fetchIds) == 0 {
+		return 0, nil
+	}
+	videos, er2 := d.Client.GetVideos(ctx, fetchIds)
+	if er2 != nil {
+		return 0, er2
+	}
+	var list []video.Video
+	if videos != nil {
+		list = videos.List
+	}
+	stored, er3 := getStoredVideos(ctx, d, ids)
+	if er3 != nil {
+		return 0, er3
+	}
+	if er4 := tombstoneVideos(ctx, d, fetchIds, list, nil); er4 != nil {
+		return 0, er4
+	}
+	var available []video.Video
+	for _, v := range list {
+		if !video.Tombstoned(v) {
+			available = append(available, v)
+		}
+	}
+	if len(available) == 0 {
+		return 0, nil
+	}
+	res, er5 := d.Repository.SaveVideos(ctx, available)
+	if er5 != nil {
+		return 0, er5
+	}
+	if d.Events != nil {
+		added := make(map[string]bool, len(newIds))
+		for _, id := range newIds {
+			added[id] = true
+		}
+		var addedIds, updatedIds []string
+		diffs := make(map[string][]string)
+		for _, v := range available {
+			if added[v.Id] {
+				addedIds = append(addedIds, v.Id)
+			} else if previous, ok := stored[v.Id]; ok {
+				if fields := video.DiffVideo(previous, v); len(fields) > 0 {
+					updatedIds = append(updatedIds, v.Id)
+					diffs[v.Id] = fields
 				}
 			}
 		}
+		addedIds = unpublished(ctx, video.VideoAdded, addedIds)
+		updatedIds = unpublished(ctx, video.VideoUpdated, updatedIds)
+		var events []video.SyncEvent
+		if len(addedIds) > 0 {
+			events = append(events, video.SyncEvent{Type: video.VideoAdded, VideoIds: addedIds})
+		}
+		if len(updatedIds) > 0 {
+			changed := make(map[string][]string, len(updatedIds))
+			for _, id := range updatedIds {
+				changed[id] = diffs[id]
+			}
+			events = append(events, video.SyncEvent{Type: video.VideoUpdated, VideoIds: updatedIds, Diffs: changed})
+		}
+		publish(ctx, d, events...)
+	}
+	count := len(available)
+	if d.Statistics != nil {
+		if _, er6 := d.Statistics.SaveStatistics(ctx, toStatistics(available, time.Now())); er6 != nil {
+			return 0, er6
+		}
+		res = len(newIds)
+		count = res
+	}
+	addVideos(ctx, count)
+	return res, nil
+}
+
+func getStoredVideos(ctx context.Context, d *DefaultSyncService, ids []string) (map[string]video.Video, error) {
+	stored := make(map[string]video.Video)
+	if d.Events == nil || d.Videos == nil || len(ids) == 0 {
+		return stored, nil
+	}
+	videos, err := d.Videos.GetVideos(ctx, ids, nil)
+	if err != nil || videos == nil {
+		return stored, err
+	}
+	for _, v := range *videos {
+		stored[v.Id] = v
+	}
+	return stored, nil
+}
+
+// tombstoneVideos tombstones the videos of ids YouTube did not return or returned private.
+func tombstoneVideos(ctx context.Context, d *DefaultSyncService, ids []string, videos []video.Video, statuses map[string]string) error {
+	if d.Tombstones == nil {
+		return nil
+	}
+	gone := make(map[string][]string)
+	for id, status := range statuses {
+		gone[status] = append(gone[status], id)
+	}
+	returned := make(map[string]bool, len(videos))
+	for _, v := range videos {
+		returned[v.Id] = true
+		if video.Tombstoned(v) {
+			gone[v.Status] = append(gone[v.Status], v.Id)
+		}
+	}
+	for _, id := range ids {
+		if !returned[id] {
+			gone[video.VideoUnavailable] = append(gone[video.VideoUnavailable], id)
+		}
+	}
+	now := time.Now()
+	for _, status := range video.VideoStatuses {
+		if len(gone[status]) == 0 {
+			continue
+		}
+		var listed []string
+		if d.Events != nil {
+			ids, er0 := d.Repository.GetVideoIds(ctx, gone[status])
+			if er0 != nil {
+				return er0
+			}
+			listed = ids
+		}
+		if _, er1 := d.Tombstones.TombstoneVideos(ctx, gone[status], status, now); er1 != nil {
+			return er1
+		}
+		if len(listed) > 0 {
+			publish(ctx, d, video.SyncEvent{Type: video.VideoRemoved, VideoIds: listed, Status: status})
+		}
 	}
+	return nil
 }
 
-func syncVideosOfPlaylists(ctx context.Context, playlistIds []string, syncVideos bool, saveCollection bool, d *DefaultSyncService) (int, error) {
+func checkVideos(ctx context.Context, d *DefaultSyncService, ids []string) error {
+	if d.Tombstones == nil || len(ids) == 0 {
+		return nil
+	}
+	stored, er0 := d.Repository.GetVideoIds(ctx, ids)
+	if er0 != nil {
+		return er0
+	}
+	for start := 0; start < len(stored); start += 50 {
+		end := start + 50
+		if end > len(stored) {
+			end = len(stored)
+		}
+		videos, er1 := d.Client.GetVideos(ctx, stored[start:end])
+		if er1 != nil {
+			return er1
+		}
+		var list []video.Video
+		if videos != nil {
+			list = videos.List
+		}
+		if er2 := tombstoneVideos(ctx, d, stored[start:end], list, nil); er2 != nil {
+			return er2
+		}
+	}
+	return nil
+}
+
+func toStatistics(videos []video.Video, now time.Time) []video.VideoStatistics {
+	statistics := make([]video.VideoStatistics, 0, len(videos))
+	for _, v := range videos {
+		if v.ViewCount == nil && v.LikeCount == nil && v.CommentCount == nil {
+			continue
+		}
+		statistics = append(statistics, video.VideoStatistics{
+			VideoId:      v.Id,
+			Timestamp:    &now,
+			ViewCount:    v.ViewCount,
+			LikeCount:    v.LikeCount,
+			CommentCount: v.CommentCount,
+		})
+	}
+	return statistics
+}
+
+func syncVideosOfPlaylists(ctx context.Context, channelId string, playlistIds []string, syncVideos bool, saveCollection bool, d *DefaultSyncService) (int, error) {
 	sum := 0
 	if saveCollection {
 		for _, v := range playlistIds {
-			resPlaylistVideos, er0 := syncPlaylistVideos(ctx, v, syncVideos, d)
+			resPlaylistVideos, items, er0 := syncPlaylistVideos(ctx, channelId, v, syncVideos, d)
 			if er0 != nil {
 				return 0, er0
 			}
@@ -383,12 +726,15 @@ func syncVideosOfPlaylists(ctx context.Context, playlistIds []string, syncVideos
 			if er1 != nil {
 				return 0, er1
 			}
+			if er2 := savePlaylistItems(ctx, d, v, resPlaylistVideos.Videos, items); er2 != nil {
+				return 0, er2
+			}
 			sum = sum + res
 		}
 		return sum, nil
 	} else {
 		for _, v := range playlistIds {
-			resPlaylistVideos, er0 := syncPlaylistVideos(ctx, v, syncVideos, d)
+			resPlaylistVideos, _, er0 := syncPlaylistVideos(ctx, channelId, v, syncVideos, d)
 			if er0 != nil {
 				return 0, er0
 			}
@@ -398,23 +744,34 @@ func syncVideosOfPlaylists(ctx context.Context, playlistIds []string, syncVideos
 	}
 }
 
-func syncPlaylistVideos(ctx context.Context, playlistId string, syncVideos bool, d *DefaultSyncService) (*video.VideoResult, error) {
-	nextPageToken := ""
+// syncPlaylistVideos also returns the items it fetched.
+func syncPlaylistVideos(ctx context.Context, channelId string, playlistId string, syncVideos bool, d *DefaultSyncService) (*video.VideoResult, []video.PlaylistVideo, error) {
+	checkpoint, er0 := getCheckpoint(ctx, d, playlistId, channelId)
+	if er0 != nil {
+		return nil, nil, er0
+	}
+	nextPageToken := checkpoint.PageToken
 	flag := true
-	success := 0
-	count := 0
-	var newVideoIds []string
+	success := checkpoint.Success
+	count := checkpoint.Count
+	newVideoIds := checkpoint.Videos
+	var items []video.PlaylistVideo
 	for flag {
-		playlistVideos, err := d.Client.GetPlaylistVideos(playlistId, 50, nextPageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		playlistVideos, err := d.Client.GetPlaylistVideos(ctx, playlistId, 50, nextPageToken)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		addPages(ctx, 1)
 		count = count + len(playlistVideos.List)
 		var videoIds []string
 		for _, v := range playlistVideos.List {
 			videoIds = append(videoIds, v.Id)
 		}
 		newVideoIds = append(newVideoIds, videoIds...)
+		items = append(items, playlistVideos.List...)
 		var def *DefaultSyncService
 		if syncVideos {
 			def = d
@@ -423,43 +780,61 @@ func syncPlaylistVideos(ctx context.Context, playlistId string, syncVideos bool,
 		}
 		r, er1 := saveVideos(ctx, playlistVideos.List, def)
 		if er1 != nil {
-			return nil, er1
+			return nil, nil, er1
 		}
 		success = success + r
 		nextPageToken = playlistVideos.NextPageToken
 		if nextPageToken == "" {
 			flag = false
+		} else {
+			checkpoint.PageToken = nextPageToken
+			checkpoint.Count = count
+			checkpoint.Success = success
+			checkpoint.Videos = newVideoIds
+			_, er2 := saveCheckpoint(ctx, d, *checkpoint)
+			if er2 != nil {
+				return nil, nil, er2
+			}
 		}
 	}
+	_, er3 := deleteCheckpoint(ctx, d, playlistId)
+	if er3 != nil {
+		return nil, nil, er3
+	}
+	addPlaylists(ctx, 1)
 	return &video.VideoResult{
 		Success: success,
 		Count:   count,
 		Videos:  newVideoIds,
-	}, nil
+	}, items, nil
 }
 
 func syncPlaylist(ctx context.Context, playlistId string, syncVideos bool, d *DefaultSyncService) (int, error) {
+	ctx = withRun(ctx)
 	resChan := make(chan *video.VideoResult)
+	itemsChan := make(chan []video.PlaylistVideo)
 	er0Chan := make(chan error)
 	playlistChan := make(chan *video.Playlist)
 	er1Chan := make(chan error)
 	go func() {
-		res, err := syncPlaylistVideos(ctx, playlistId, syncVideos, d)
+		res, items, err := syncPlaylistVideos(ctx, "", playlistId, syncVideos, d)
 		resChan <- res
+		itemsChan <- items
 		er0Chan <- err
 	}()
 	go func() {
-		playlist, err := d.Client.GetPlaylist(playlistId)
+		playlist, err := d.Client.GetPlaylist(ctx, playlistId)
 		playlistChan <- playlist
 		er1Chan <- err
 	}()
 	res := <-resChan
+	items := <-itemsChan
 	er0 := <-er0Chan
+	playlist := <-playlistChan
+	er1 := <-er1Chan
 	if er0 != nil {
 		return 0, er0
 	}
-	playlist := <-playlistChan
-	er1 := <-er1Chan
 	if er1 != nil {
 		return 0, er1
 	}
@@ -484,9 +859,114 @@ func syncPlaylist(ctx context.Context, playlistId string, syncVideos bool, d *De
 	if er3 != nil {
 		return 0, er3
 	}
+	if er4 := savePlaylistItems(ctx, d, playlist.Id, res.Videos, items); er4 != nil {
+		return 0, er4
+	}
 	return res.Success, nil
 }
 
+func savePlaylistItems(ctx context.Context, d *DefaultSyncService, playlistId string, ids []string, videos []video.PlaylistVideo) error {
+	// a sync resumed from a checkpoint lacks the earlier pages
+	if d.PlaylistItems == nil || len(videos) < len(ids) {
+		return nil
+	}
+	previous, er0 := d.PlaylistItems.GetPlaylistItems(ctx, playlistId)
+	if er0 != nil {
+		return er0
+	}
+	items, changes := video.DiffPlaylistItems(previous, video.NewPlaylistItems(playlistId, ids, videos), time.Now())
+	if len(items) == 0 {
+		return nil
+	}
+	if _, er1 := d.PlaylistItems.SavePlaylistItems(ctx, items); er1 != nil {
+		return er1
+	}
+	if len(changes.Added) > 0 || len(changes.Removed) > 0 || len(changes.Moved) > 0 {
+		publish(ctx, d, video.SyncEvent{Type: video.PlaylistChanged, PlaylistId: playlistId, Changes: &changes})
+	}
+	return checkVideos(ctx, d, changes.Removed)
+}
+
+// publish reports a failed publish to the progress of ctx instead of failing the sync.
+func publish(ctx context.Context, d *DefaultSyncService, events ...video.SyncEvent) {
+	if d.Events == nil || len(events) == 0 {
+		return
+	}
+	now := time.Now()
+	for i := range events {
+		if events[i].Time == nil {
+			events[i].Time = &now
+		}
+	}
+	if err := d.Events.Publish(ctx, events); err != nil {
+		if p := GetProgress(ctx); p != nil {
+			p.AddError(err)
+		}
+	}
+}
+
+type runKey struct{}
+
+// run holds what a sync published, as its uploads and playlists may share videos.
+type run struct {
+	mutex     sync.Mutex
+	published map[string]bool
+}
+
+func withRun(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(runKey{}).(*run); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, runKey{}, &run{published: make(map[string]bool)})
+}
+
+func unpublished(ctx context.Context, eventType string, ids []string) []string {
+	r, ok := ctx.Value(runKey{}).(*run)
+	if !ok || len(ids) == 0 {
+		return ids
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var res []string
+	for _, id := range ids {
+		key := eventType + "/" + id
+		if !r.published[key] {
+			r.published[key] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func getCheckpoint(ctx context.Context, d *DefaultSyncService, id string, channelId string) (*video.SyncCheckpoint, error) {
+	if d.Checkpoint != nil {
+		checkpoint, err := d.Checkpoint.GetCheckpoint(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			return checkpoint, nil
+		}
+	}
+	return &video.SyncCheckpoint{Id: id, ChannelId: channelId}, nil
+}
+
+func saveCheckpoint(ctx context.Context, d *DefaultSyncService, checkpoint video.SyncCheckpoint) (int, error) {
+	if d.Checkpoint == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	checkpoint.UpdatedAt = &now
+	return d.Checkpoint.SaveCheckpoint(ctx, checkpoint)
+}
+
+func deleteCheckpoint(ctx context.Context, d *DefaultSyncService, id string) (int, error) {
+	if d.Checkpoint == nil {
+		return 0, nil
+	}
+	return d.Checkpoint.DeleteCheckpoint(ctx, id)
+}
+
 func notIn(ids []string, subIds []string) []string {
 	var newIds []string
 	if len(subIds) == 0 {
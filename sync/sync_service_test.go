@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+func TestPublishers(t *testing.T) {
+	ctx := context.Background()
+	events := []video.SyncEvent{{Type: video.VideoAdded}, {Type: video.VideoAdded}, {Type: video.VideoAdded}}
+	t.Run("channel full", func(t *testing.T) {
+		publisher := NewChannelPublisher(2)
+		if err := publisher.Publish(ctx, events); err != nil {
+			t.Fatal(err)
+		}
+		if len(publisher.Events) != 2 || publisher.Dropped() != 1 {
+			t.Errorf("received %d and dropped %d events; want 2 and 1", len(publisher.Events), publisher.Dropped())
+		}
+	})
+	t.Run("webhook timeout", func(t *testing.T) {
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer server.Close()
+		defer close(done)
+		publisher := NewWebhookPublisher(server.URL, "secret")
+		publisher.Client.Timeout = 20 * time.Millisecond
+		if err := publisher.Publish(ctx, events); err == nil {
+			t.Error("published to a webhook that does not respond; want a timeout")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+// SignatureHeader holds "sha256=" and the hex HMAC SHA-256 of the body.
+const SignatureHeader = "X-Signature-256"
+
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookPublisher posts the events of each call as a JSON array, signed with Secret.
+type WebhookPublisher struct {
+	Client *http.Client
+	Url    string
+	Secret []byte
+}
+
+func NewWebhookPublisher(url string, secret string, options ...*http.Client) *WebhookPublisher {
+	client := &http.Client{Timeout: DefaultWebhookTimeout}
+	if len(options) > 0 && options[0] != nil {
+		client = options[0]
+	}
+	return &WebhookPublisher{Client: client, Url: url, Secret: []byte(secret)}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, events []video.SyncEvent) error {
+	body, er0 := json.Marshal(events)
+	if er0 != nil {
+		return er0
+	}
+	req, er1 := http.NewRequestWithContext(ctx, http.MethodPost, p.Url, bytes.NewReader(body))
+	if er1 != nil {
+		return er1
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(p.Secret, body))
+	res, er2 := p.Client.Do(req)
+	if er2 != nil {
+		return er2
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", p.Url, res.Status)
+	}
+	return nil
+}
+
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifySignature(secret []byte, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
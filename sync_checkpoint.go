@@ -0,0 +1,16 @@
+package video
+
+import "time"
+
+type SyncCheckpoint struct {
+	Id         string     `mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	ChannelId  string     `mapstructure:"channelId" json:"channelId,omitempty" gorm:"column:channelId" bson:"channelId,omitempty" dynamodbav:"channelId,omitempty" firestore:"channelId,omitempty"`
+	PageToken  string     `mapstructure:"pageToken" json:"pageToken,omitempty" gorm:"column:pageToken" bson:"pageToken,omitempty" dynamodbav:"pageToken,omitempty" firestore:"pageToken,omitempty"`
+	Count      int        `mapstructure:"count" json:"count,omitempty" gorm:"column:count" bson:"count,omitempty" dynamodbav:"count,omitempty" firestore:"count,omitempty"`
+	Total      int        `mapstructure:"total" json:"total,omitempty" gorm:"column:total" bson:"total,omitempty" dynamodbav:"total,omitempty" firestore:"total,omitempty"`
+	Success    int        `mapstructure:"success" json:"success,omitempty" gorm:"column:success" bson:"success,omitempty" dynamodbav:"success,omitempty" firestore:"success,omitempty"`
+	VideoCount int        `mapstructure:"videoCount" json:"videoCount,omitempty" gorm:"column:videoCount" bson:"videoCount,omitempty" dynamodbav:"videoCount,omitempty" firestore:"videoCount,omitempty"`
+	Videos     []string   `mapstructure:"videos" json:"videos,omitempty" gorm:"column:videos" bson:"videos,omitempty" dynamodbav:"videos,omitempty" firestore:"videos,omitempty"`
+	LastUpload *time.Time `mapstructure:"lastUpload" json:"lastUpload,omitempty" gorm:"column:lastUpload" bson:"lastUpload,omitempty" dynamodbav:"lastUpload,omitempty" firestore:"lastUpload,omitempty"`
+	UpdatedAt  *time.Time `mapstructure:"updatedAt" json:"updatedAt,omitempty" gorm:"column:updatedAt" bson:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+}
@@ -0,0 +1,9 @@
+package video
+
+import "context"
+
+type SyncCheckpointRepository interface {
+	GetCheckpoint(ctx context.Context, id string) (*SyncCheckpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint SyncCheckpoint) (int, error)
+	DeleteCheckpoint(ctx context.Context, id string) (int, error)
+}
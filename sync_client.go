@@ -1,5 +1,7 @@
 package video
 
+import "context"
+
 type SyncClient interface {
 	GetChannel(id string) (*Channel, error)
 	GetChannels(ids []string) (*[]Channel, error)
@@ -10,3 +12,14 @@ type SyncClient interface {
 	GetVideos(ids []string) (*ListResultVideos, error)
 	GetSubscriptions(channelId string, mine string, max int, nextPageToken string) (*ListResultChannel, error)
 }
+
+type ContextSyncClient interface {
+	GetChannel(ctx context.Context, id string) (*Channel, error)
+	GetChannels(ctx context.Context, ids []string) (*[]Channel, error)
+	GetPlaylist(ctx context.Context, id string) (*Playlist, error)
+	GetPlaylists(ctx context.Context, ids []string) (*[]Playlist, error)
+	GetChannelPlaylists(ctx context.Context, channelId string, max int16, nextPageToken string) (*ListResultPlaylist, error)
+	GetPlaylistVideos(ctx context.Context, playlistId string, max int16, nextPageToken string) (*ListResultPlaylistVideo, error)
+	GetVideos(ctx context.Context, ids []string) (*ListResultVideos, error)
+	GetSubscriptions(ctx context.Context, channelId string, mine string, max int, nextPageToken string) (*ListResultChannel, error)
+}
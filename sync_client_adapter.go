@@ -0,0 +1,100 @@
+package video
+
+import "context"
+
+// SyncClientAdapter honors cancellation between calls only.
+type SyncClientAdapter struct {
+	Client SyncClient
+}
+
+func NewSyncClientAdapter(client SyncClient) *SyncClientAdapter {
+	return &SyncClientAdapter{Client: client}
+}
+
+func (a *SyncClientAdapter) GetChannel(ctx context.Context, id string) (*Channel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetChannel(id)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetChannels(ctx context.Context, ids []string) (*[]Channel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetChannels(ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetPlaylist(ctx context.Context, id string) (*Playlist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetPlaylist(id)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetPlaylists(ctx context.Context, ids []string) (*[]Playlist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetPlaylists(ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetChannelPlaylists(ctx context.Context, channelId string, max int16, nextPageToken string) (*ListResultPlaylist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetChannelPlaylists(channelId, max, nextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetPlaylistVideos(ctx context.Context, playlistId string, max int16, nextPageToken string) (*ListResultPlaylistVideo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetPlaylistVideos(playlistId, max, nextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetVideos(ctx context.Context, ids []string) (*ListResultVideos, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetVideos(ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
+
+func (a *SyncClientAdapter) GetSubscriptions(ctx context.Context, channelId string, mine string, max int, nextPageToken string) (*ListResultChannel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := a.Client.GetSubscriptions(channelId, mine, max, nextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	return res, ctx.Err()
+}
@@ -0,0 +1,33 @@
+package video
+
+import (
+	"context"
+	"time"
+)
+
+// The types of SyncEvent.
+const (
+	ChannelSynced   = "ChannelSynced"
+	VideoAdded      = "VideoAdded"
+	VideoUpdated    = "VideoUpdated"
+	PlaylistChanged = "PlaylistChanged"
+	VideoRemoved    = "VideoRemoved"
+)
+
+// SyncEvent has the Diffs of VideoUpdated by video id, and the Status of VideoRemoved.
+type SyncEvent struct {
+	Type       string              `mapstructure:"type" json:"type,omitempty" gorm:"column:type" bson:"type,omitempty" dynamodbav:"type,omitempty" firestore:"type,omitempty"`
+	Time       *time.Time          `mapstructure:"time" json:"time,omitempty" gorm:"column:time" bson:"time,omitempty" dynamodbav:"time,omitempty" firestore:"time,omitempty"`
+	ChannelId  string              `mapstructure:"channelId" json:"channelId,omitempty" gorm:"column:channelId" bson:"channelId,omitempty" dynamodbav:"channelId,omitempty" firestore:"channelId,omitempty"`
+	PlaylistId string              `mapstructure:"playlistId" json:"playlistId,omitempty" gorm:"column:playlistId" bson:"playlistId,omitempty" dynamodbav:"playlistId,omitempty" firestore:"playlistId,omitempty"`
+	VideoIds   []string            `mapstructure:"videoIds" json:"videoIds,omitempty" gorm:"column:videoIds" bson:"videoIds,omitempty" dynamodbav:"videoIds,omitempty" firestore:"videoIds,omitempty"`
+	Status     string              `mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
+	Count      int                 `mapstructure:"count" json:"count,omitempty" gorm:"column:count" bson:"count,omitempty" dynamodbav:"count,omitempty" firestore:"count,omitempty"`
+	Changes    *PlaylistChanges    `mapstructure:"changes" json:"changes,omitempty" gorm:"column:changes" bson:"changes,omitempty" dynamodbav:"changes,omitempty" firestore:"changes,omitempty"`
+	Diffs      map[string][]string `mapstructure:"diffs" json:"diffs,omitempty" gorm:"column:diffs" bson:"diffs,omitempty" dynamodbav:"diffs,omitempty" firestore:"diffs,omitempty"`
+}
+
+// SyncEventPublisher is called concurrently.
+type SyncEventPublisher interface {
+	Publish(ctx context.Context, events []SyncEvent) error
+}
@@ -0,0 +1,28 @@
+package video
+
+import "time"
+
+const (
+	SyncJobPending   = "pending"
+	SyncJobRunning   = "running"
+	SyncJobCompleted = "completed"
+	SyncJobFailed    = "failed"
+	SyncJobCanceled  = "canceled"
+)
+
+type SyncJob struct {
+	Id        string     `mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	Type      string     `mapstructure:"type" json:"type,omitempty" gorm:"column:type" bson:"type,omitempty" dynamodbav:"type,omitempty" firestore:"type,omitempty"`
+	TargetId  string     `mapstructure:"targetId" json:"targetId,omitempty" gorm:"column:targetId" bson:"targetId,omitempty" dynamodbav:"targetId,omitempty" firestore:"targetId,omitempty"`
+	Level     int        `mapstructure:"level" json:"level,omitempty" gorm:"column:level" bson:"level,omitempty" dynamodbav:"level,omitempty" firestore:"level,omitempty"`
+	Status    string     `mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
+	Pages     int        `mapstructure:"pages" json:"pages" gorm:"column:pages" bson:"pages" dynamodbav:"pages" firestore:"pages"`
+	Videos    int        `mapstructure:"videos" json:"videos" gorm:"column:videos" bson:"videos" dynamodbav:"videos" firestore:"videos"`
+	Playlists int        `mapstructure:"playlists" json:"playlists" gorm:"column:playlists" bson:"playlists" dynamodbav:"playlists" firestore:"playlists"`
+	Errors    []string   `mapstructure:"errors" json:"errors,omitempty" gorm:"column:errors" bson:"errors,omitempty" dynamodbav:"errors,omitempty" firestore:"errors,omitempty"`
+	Result    int        `mapstructure:"result" json:"result" gorm:"column:result" bson:"result" dynamodbav:"result" firestore:"result"`
+	CreatedAt *time.Time `mapstructure:"createdAt" json:"createdAt,omitempty" gorm:"column:createdAt" bson:"createdAt,omitempty" dynamodbav:"createdAt,omitempty" firestore:"createdAt,omitempty"`
+	StartedAt *time.Time `mapstructure:"startedAt" json:"startedAt,omitempty" gorm:"column:startedAt" bson:"startedAt,omitempty" dynamodbav:"startedAt,omitempty" firestore:"startedAt,omitempty"`
+	EndedAt   *time.Time `mapstructure:"endedAt" json:"endedAt,omitempty" gorm:"column:endedAt" bson:"endedAt,omitempty" dynamodbav:"endedAt,omitempty" firestore:"endedAt,omitempty"`
+	UpdatedAt *time.Time `mapstructure:"updatedAt" json:"updatedAt,omitempty" gorm:"column:updatedAt" bson:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+}
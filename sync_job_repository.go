@@ -0,0 +1,9 @@
+package video
+
+import "context"
+
+type SyncJobRepository interface {
+	GetJob(ctx context.Context, id string) (*SyncJob, error)
+	GetJobs(ctx context.Context, status string) ([]SyncJob, error)
+	SaveJob(ctx context.Context, job SyncJob) (int, error)
+}
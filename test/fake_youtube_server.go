@@ -0,0 +1,326 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/core-go/video/youtube"
+)
+
+const (
+	defaultMaxResults = 5
+	maxMaxResults     = 50
+)
+
+type Failure struct {
+	Status  int
+	Reason  string
+	Message string
+	Times   int
+}
+
+// FakeYoutubeServer serves the subset of the YouTube Data API v3 used by this project from in-memory fixtures.
+type FakeYoutubeServer struct {
+	Fixtures  *Fixtures
+	ValidKeys []string
+	mutex     sync.Mutex
+	failures  map[string][]*Failure
+	requests  map[string]int
+}
+
+func NewFakeYoutubeServer(fixtures *Fixtures) *FakeYoutubeServer {
+	if fixtures == nil {
+		fixtures = &Fixtures{}
+	}
+	return &FakeYoutubeServer{Fixtures: fixtures, failures: make(map[string][]*Failure), requests: make(map[string]int)}
+}
+
+// Start runs the fake on a local port. Point a client at it by setting its BaseUrl to the server URL.
+func (f *FakeYoutubeServer) Start() *httptest.Server {
+	return httptest.NewServer(f)
+}
+
+func NewFakeSyncClient(server *httptest.Server) *youtube.YoutubeSyncClient {
+	client := youtube.NewYoutubeSyncClient("fake-key")
+	client.BaseUrl = server.URL
+	client.Client = server.Client()
+	return client
+}
+
+// Fail makes the next times requests to endpoint fail with the given status and reason. Use "*" to match every endpoint.
+func (f *FakeYoutubeServer) Fail(endpoint string, status int, reason string, times int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.failures[endpoint] = append(f.failures[endpoint], &Failure{Status: status, Reason: reason, Message: reason, Times: times})
+}
+
+func (f *FakeYoutubeServer) Requests(endpoint string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.requests[endpoint]
+}
+
+func (f *FakeYoutubeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	query := r.URL.Query()
+	f.mutex.Lock()
+	f.requests[endpoint]++
+	failure := f.nextFailure(endpoint)
+	f.mutex.Unlock()
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", "only GET is supported")
+		return
+	}
+	if failure != nil {
+		writeError(w, failure.Status, failure.Reason, failure.Message)
+		return
+	}
+	if !f.validKey(query.Get("key")) {
+		writeError(w, http.StatusBadRequest, "keyInvalid", "API key not valid. Please pass a valid API key.")
+		return
+	}
+	items := f.Fixtures.resources(endpoint)
+	if items == nil && !isKnownEndpoint(endpoint) {
+		writeError(w, http.StatusNotFound, "notFound", fmt.Sprintf("unknown endpoint %s", endpoint))
+		return
+	}
+	parts := splitList(query.Get("part"))
+	if len(parts) == 0 {
+		writeError(w, http.StatusBadRequest, "required", "No filter selected. Expected one of: part")
+		return
+	}
+	matched, er0 := filter(endpoint, items, query)
+	if er0 != nil {
+		writeError(w, http.StatusBadRequest, "invalidFilters", er0.Error())
+		return
+	}
+	maxResults := defaultMaxResults
+	if len(query.Get("id")) > 0 {
+		maxResults = maxMaxResults
+	}
+	if s := query.Get("maxResults"); len(s) > 0 {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxMaxResults {
+			writeError(w, http.StatusBadRequest, "invalidParameter", "Invalid value for maxResults")
+			return
+		}
+		maxResults = n
+	}
+	offset := 0
+	if token := query.Get("pageToken"); len(token) > 0 {
+		n, err := decodePageToken(token)
+		if err != nil || n > len(matched) {
+			writeError(w, http.StatusBadRequest, "invalidPageToken", "The request specifies an invalid page token.")
+			return
+		}
+		offset = n
+	}
+	end := offset + maxResults
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page := make([]map[string]interface{}, 0)
+	for _, item := range matched[offset:end] {
+		page = append(page, selectParts(item, parts))
+	}
+	response := map[string]interface{}{
+		"kind":     "youtube#" + listKinds[endpoint] + "ListResponse",
+		"etag":     "fake",
+		"pageInfo": map[string]int{"totalResults": len(matched), "resultsPerPage": maxResults},
+		"items":    page,
+	}
+	if end < len(matched) {
+		response["nextPageToken"] = encodePageToken(end)
+	}
+	if offset > 0 {
+		prev := offset - maxResults
+		if prev < 0 {
+			prev = 0
+		}
+		response["prevPageToken"] = encodePageToken(prev)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f *FakeYoutubeServer) nextFailure(endpoint string) *Failure {
+	for _, key := range []string{endpoint, "*"} {
+		for _, failure := range f.failures[key] {
+			if failure.Times > 0 {
+				failure.Times--
+				return failure
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FakeYoutubeServer) validKey(key string) bool {
+	if len(f.ValidKeys) == 0 {
+		return len(key) > 0
+	}
+	for _, k := range f.ValidKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+var listKinds = map[string]string{
+	"channels":        "channel",
+	"playlists":       "playlist",
+	"playlistItems":   "playlistItem",
+	"videos":          "video",
+	"subscriptions":   "subscription",
+	"videoCategories": "videoCategory",
+}
+
+func isKnownEndpoint(endpoint string) bool {
+	_, ok := listKinds[endpoint]
+	return ok
+}
+
+func filter(endpoint string, items []map[string]interface{}, query map[string][]string) ([]map[string]interface{}, error) {
+	get := func(key string) string {
+		if v, ok := query[key]; ok && len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	var match func(item map[string]interface{}) bool
+	if ids := splitList(get("id")); len(ids) > 0 {
+		match = func(item map[string]interface{}) bool {
+			return contains(ids, stringAt(item, "id"))
+		}
+	} else {
+		switch endpoint {
+		case "playlists":
+			channelId := get("channelId")
+			if len(channelId) == 0 {
+				return nil, fmt.Errorf("one of id or channelId is required")
+			}
+			match = func(item map[string]interface{}) bool { return stringAt(item, "snippet", "channelId") == channelId }
+		case "playlistItems":
+			playlistId := get("playlistId")
+			if len(playlistId) == 0 {
+				return nil, fmt.Errorf("one of id or playlistId is required")
+			}
+			match = func(item map[string]interface{}) bool { return stringAt(item, "snippet", "playlistId") == playlistId }
+		case "subscriptions":
+			channelId := get("channelId")
+			if len(channelId) == 0 && len(get("mine")) == 0 {
+				return nil, fmt.Errorf("one of id, channelId or mine is required")
+			}
+			match = func(item map[string]interface{}) bool { return stringAt(item, "snippet", "channelId") == channelId }
+		case "videoCategories":
+			match = func(item map[string]interface{}) bool { return true }
+		default:
+			return nil, fmt.Errorf("id is required")
+		}
+	}
+	var matched []map[string]interface{}
+	for _, item := range items {
+		if match(item) {
+			matched = append(matched, item)
+		}
+	}
+	if endpoint == "playlistItems" {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return numberAt(matched[i], "snippet", "position") < numberAt(matched[j], "snippet", "position")
+		})
+	}
+	return matched, nil
+}
+
+func selectParts(item map[string]interface{}, parts []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, key := range []string{"kind", "etag", "id"} {
+		if v, ok := item[key]; ok {
+			result[key] = v
+		}
+	}
+	for _, part := range parts {
+		if v, ok := item[part]; ok {
+			result[part] = v
+		}
+	}
+	return result
+}
+
+func stringAt(item map[string]interface{}, path ...string) string {
+	var v interface{} = item
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[key]
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func numberAt(item map[string]interface{}, path ...string) float64 {
+	var v interface{} = item
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return 0
+		}
+		v = m[key]
+	}
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	}
+	return 0
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func encodePageToken(offset int) string {
+	return "FAKE" + strconv.Itoa(offset)
+}
+
+func decodePageToken(token string) (int, error) {
+	if !strings.HasPrefix(token, "FAKE") {
+		return 0, fmt.Errorf("invalid page token")
+	}
+	return strconv.Atoi(token[4:])
+}
+
+func writeError(w http.ResponseWriter, status int, reason string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(youtube.ErrorResponse{Error: youtube.ErrorBody{
+		Code:    status,
+		Message: message,
+		Errors:  []youtube.ErrorItem{{Domain: "youtube.fake", Reason: reason, Message: message}},
+	}})
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newPlaylistItems(playlistId string, n int) *Fixtures {
+	fixtures := &Fixtures{}
+	// stored out of order, the fake sorts by position
+	for i := n - 1; i >= 0; i-- {
+		fixtures.PlaylistItems = append(fixtures.PlaylistItems, map[string]interface{}{
+			"kind":           "youtube#playlistItem",
+			"etag":           fmt.Sprintf("e%d", i),
+			"id":             fmt.Sprintf("item%d", i),
+			"snippet":        map[string]interface{}{"playlistId": playlistId, "position": i},
+			"contentDetails": map[string]interface{}{"videoId": fmt.Sprintf("vid%d", i)},
+		})
+	}
+	return fixtures
+}
+
+type listResponse struct {
+	Items         []map[string]interface{} `json:"items"`
+	NextPageToken string                   `json:"nextPageToken"`
+	PageInfo      struct {
+		TotalResults int `json:"totalResults"`
+	} `json:"pageInfo"`
+	Error struct {
+		Errors []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+func list(t *testing.T, baseUrl string, endpoint string, query url.Values) (int, listResponse) {
+	t.Helper()
+	query.Set("key", "fake-key")
+	resp, er0 := http.Get(baseUrl + "/" + endpoint + "?" + query.Encode())
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	defer resp.Body.Close()
+	var res listResponse
+	if er1 := json.NewDecoder(resp.Body).Decode(&res); er1 != nil {
+		t.Fatal(er1)
+	}
+	return resp.StatusCode, res
+}
+
+func TestFakeYoutubeServerPaging(t *testing.T) {
+	server := NewFakeYoutubeServer(newPlaylistItems("PL1", 12)).Start()
+	defer server.Close()
+	tests := []struct {
+		name       string
+		maxResults string
+		pages      []int
+	}{
+		{name: "default", pages: []int{5, 5, 2}},
+		{name: "max results", maxResults: "4", pages: []int{4, 4, 4}},
+		{name: "one page", maxResults: "50", pages: []int{12}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var pages []int
+			position := 0
+			token := ""
+			for i := 0; i < 10; i++ {
+				query := url.Values{"part": {"snippet"}, "playlistId": {"PL1"}}
+				if len(tc.maxResults) > 0 {
+					query.Set("maxResults", tc.maxResults)
+				}
+				if len(token) > 0 {
+					query.Set("pageToken", token)
+				}
+				status, res := list(t, server.URL, "playlistItems", query)
+				if status != http.StatusOK || res.PageInfo.TotalResults != 12 {
+					t.Fatalf("status %d, %d results; want 200 and 12", status, res.PageInfo.TotalResults)
+				}
+				for _, item := range res.Items {
+					if item["id"] != fmt.Sprintf("item%d", position) {
+						t.Errorf("item %v at position %d", item["id"], position)
+					}
+					position++
+				}
+				pages = append(pages, len(res.Items))
+				if token = res.NextPageToken; len(token) == 0 {
+					break
+				}
+			}
+			if fmt.Sprint(pages) != fmt.Sprint(tc.pages) {
+				t.Errorf("pages of %v; want %v", pages, tc.pages)
+			}
+		})
+	}
+	for _, query := range []url.Values{
+		{"part": {"snippet"}, "playlistId": {"PL1"}, "pageToken": {"FAKE99"}},
+		{"part": {"snippet"}, "playlistId": {"PL1"}, "pageToken": {"token"}},
+		{"part": {"snippet"}, "playlistId": {"PL1"}, "maxResults": {"51"}},
+	} {
+		status, res := list(t, server.URL, "playlistItems", query)
+		if status != http.StatusBadRequest || len(res.Error.Errors) == 0 {
+			t.Errorf("%v: status %d; want 400 with a reason", query, status)
+		}
+	}
+}
+
+func TestFakeYoutubeServerParts(t *testing.T) {
+	server := NewFakeYoutubeServer(newPlaylistItems("PL1", 1)).Start()
+	defer server.Close()
+	tests := []struct {
+		name     string
+		part     string
+		status   int
+		expected []string
+	}{
+		{name: "snippet", part: "snippet", status: http.StatusOK, expected: []string{"kind", "etag", "id", "snippet"}},
+		{name: "both", part: "snippet, contentDetails", status: http.StatusOK, expected: []string{"kind", "etag", "id", "snippet", "contentDetails"}},
+		{name: "unknown", part: "statistics", status: http.StatusOK, expected: []string{"kind", "etag", "id"}},
+		{name: "missing", status: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, res := list(t, server.URL, "playlistItems", url.Values{"part": {tc.part}, "playlistId": {"PL1"}})
+			if status != tc.status {
+				t.Fatalf("status %d; want %d", status, tc.status)
+			}
+			if status != http.StatusOK {
+				if len(res.Error.Errors) == 0 || res.Error.Errors[0].Reason != "required" {
+					t.Errorf("error = %+v; want required", res.Error)
+				}
+				return
+			}
+			if len(res.Items) != 1 || len(res.Items[0]) != len(tc.expected) {
+				t.Fatalf("items = %v; want one with %v", res.Items, tc.expected)
+			}
+			for _, key := range tc.expected {
+				if _, ok := res.Items[0][key]; !ok {
+					t.Errorf("item has no %s", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Fixtures holds YouTube Data API resources exactly as the API returns them, keyed by endpoint.
+type Fixtures struct {
+	Channels        []map[string]interface{} `json:"channels,omitempty"`
+	Playlists       []map[string]interface{} `json:"playlists,omitempty"`
+	PlaylistItems   []map[string]interface{} `json:"playlistItems,omitempty"`
+	Videos          []map[string]interface{} `json:"videos,omitempty"`
+	Subscriptions   []map[string]interface{} `json:"subscriptions,omitempty"`
+	VideoCategories []map[string]interface{} `json:"videoCategories,omitempty"`
+}
+
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, er0 := ioutil.ReadFile(path)
+	if er0 != nil {
+		return nil, er0
+	}
+	return ParseFixtures(data)
+}
+
+func ParseFixtures(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	err := json.Unmarshal(data, &fixtures)
+	if err != nil {
+		return nil, err
+	}
+	return &fixtures, nil
+}
+
+func (f *Fixtures) resources(endpoint string) []map[string]interface{} {
+	switch endpoint {
+	case "channels":
+		return f.Channels
+	case "playlists":
+		return f.Playlists
+	case "playlistItems":
+		return f.PlaylistItems
+	case "videos":
+		return f.Videos
+	case "subscriptions":
+		return f.Subscriptions
+	case "videoCategories":
+		return f.VideoCategories
+	}
+	return nil
+}
@@ -10,10 +10,10 @@ import (
 )
 
 type YoutubeHandler struct {
-	service SyncClient
+	service ContextSyncClient
 }
 
-func NewTubeHandler(syncClient SyncClient) *YoutubeHandler {
+func NewTubeHandler(syncClient ContextSyncClient) *YoutubeHandler {
 	return &YoutubeHandler{service: syncClient}
 }
 
@@ -23,7 +23,7 @@ func (t *YoutubeHandler) GetChannel(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Id cannot be empty", http.StatusBadRequest)
 		return
 	}
-	result, err := t.service.GetChannel(id)
+	result, err := t.service.GetChannel(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -38,7 +38,7 @@ func (t *YoutubeHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := strings.Split(id, ",")
-	result, err := t.service.GetChannels(s)
+	result, err := t.service.GetChannels(r.Context(), s)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -52,7 +52,7 @@ func (t *YoutubeHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Id cannot be empty", http.StatusBadRequest)
 		return
 	}
-	result, err := t.service.GetPlaylist(id)
+	result, err := t.service.GetPlaylist(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -67,7 +67,7 @@ func (t *YoutubeHandler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := strings.Split(id, ",")
-	result, err := t.service.GetPlaylists(s)
+	result, err := t.service.GetPlaylists(r.Context(), s)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -103,7 +103,7 @@ func (t *YoutubeHandler) GetChannelPlaylists(w http.ResponseWriter, r *http.Requ
 			nextPageToken = s[2]
 		}
 	}
-	result, err := t.service.GetChannelPlaylists(channelId, int16(max), nextPageToken)
+	result, err := t.service.GetChannelPlaylists(r.Context(), channelId, int16(max), nextPageToken)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -139,7 +139,7 @@ func (t *YoutubeHandler) GetPlaylistVideos(w http.ResponseWriter, r *http.Reques
 			nextPageToken = s[2]
 		}
 	}
-	result, err := t.service.GetPlaylistVideos(channelId, int16(max), nextPageToken)
+	result, err := t.service.GetPlaylistVideos(r.Context(), channelId, int16(max), nextPageToken)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -154,7 +154,7 @@ func (t *YoutubeHandler) GetVideos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := strings.Split(id, ",")
-	result, err := t.service.GetVideos(s)
+	result, err := t.service.GetVideos(r.Context(), s)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -167,14 +167,14 @@ func respond(w http.ResponseWriter, result interface{}) error {
 	err := json.NewEncoder(w).Encode(result)
 	return err
 }
-func GetParam(r *http.Request, options... int) string {
+func GetParam(r *http.Request, options ...int) string {
 	offset := 0
 	if len(options) > 0 && options[0] > 0 {
 		offset = options[0]
 	}
 	s := r.URL.Path
 	params := strings.Split(s, "/")
-	i := len(params)-1-offset
+	i := len(params) - 1 - offset
 	if i >= 0 {
 		return params[i]
 	} else {
@@ -0,0 +1,60 @@
+package video
+
+import (
+	"context"
+	"time"
+)
+
+// The Status of a video sync found gone from YouTube.
+const (
+	VideoDeleted     = "deleted"
+	VideoPrivate     = "private"
+	VideoUnavailable = "unavailable"
+)
+
+var VideoStatuses = []string{VideoDeleted, VideoPrivate, VideoUnavailable}
+
+// TombstoneVideos keeps the time a video was first tombstoned. SaveVideos untombstones a video.
+type TombstoneRepository interface {
+	TombstoneVideos(ctx context.Context, ids []string, status string, at time.Time) (int, error)
+	PurgeVideos(ctx context.Context, status string, before time.Time) (int, error)
+}
+
+// PurgePolicy keeps the statuses it has no duration for.
+type PurgePolicy map[string]time.Duration
+
+var DefaultPurgePolicy = PurgePolicy{
+	VideoDeleted:     30 * 24 * time.Hour,
+	VideoUnavailable: 90 * 24 * time.Hour,
+}
+
+func Tombstoned(v Video) bool {
+	return len(v.Status) > 0
+}
+
+type unavailableKey struct{}
+
+// WithUnavailable makes the lists and searches of a VideoService include tombstoned videos.
+func WithUnavailable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unavailableKey{}, true)
+}
+
+func IncludeUnavailable(ctx context.Context) bool {
+	include, _ := ctx.Value(unavailableKey{}).(bool)
+	return include
+}
+
+func Listed(ctx context.Context, v Video) bool {
+	return !Tombstoned(v) || IncludeUnavailable(ctx)
+}
+
+// PlaylistVideoStatus reads the "Deleted video" and "Private video" items YouTube keeps in playlists.
+func PlaylistVideoStatus(v PlaylistVideo) string {
+	switch {
+	case v.PrivacyStatus == "private" || v.Title == "Private video":
+		return VideoPrivate
+	case v.PrivacyStatus == "privacyStatusUnspecified" || v.Title == "Deleted video":
+		return VideoDeleted
+	}
+	return ""
+}
@@ -27,15 +27,30 @@ type Video struct {
 	Dimension            string     `mapstructure:"dimension" json:"dimension,omitempty" gorm:"column:dimension" bson:"dimension,omitempty" dynamodbav:"dimension,omitempty" firestore:"dimension,omitempty"`
 	Duration             int64      `mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
 	LicensedContent      *bool      `mapstructure:"licensedContent" json:"licensedContent,omitempty" gorm:"column:licensedContent" bson:"licensedContent,omitempty" dynamodbav:"licensedContent,omitempty" firestore:"licensedContent,omitempty"`
+	License              string     `mapstructure:"license" json:"license,omitempty" gorm:"column:license" bson:"license,omitempty" dynamodbav:"license,omitempty" firestore:"license,omitempty"`
+	Embeddable           *bool      `mapstructure:"embeddable" json:"embeddable,omitempty" gorm:"column:embeddable" bson:"embeddable,omitempty" dynamodbav:"embeddable,omitempty" firestore:"embeddable,omitempty"`
 	LiveBroadcastContent string     `mapstructure:"liveBroadcastContent" json:"liveBroadcastContent,omitempty" gorm:"column:liveBroadcastContent" bson:"liveBroadcastContent,omitempty" dynamodbav:"liveBroadcastContent,omitempty" firestore:"liveBroadcastContent,omitempty"`
+	LiveStatus           string     `mapstructure:"liveStatus" json:"liveStatus,omitempty" gorm:"column:liveStatus" bson:"liveStatus,omitempty" dynamodbav:"liveStatus,omitempty" firestore:"liveStatus,omitempty"`
+	ScheduledStartTime   *time.Time `mapstructure:"scheduledStartTime" json:"scheduledStartTime,omitempty" gorm:"column:scheduledStartTime" bson:"scheduledStartTime,omitempty" dynamodbav:"scheduledStartTime,omitempty" firestore:"scheduledStartTime,omitempty"`
+	ScheduledEndTime     *time.Time `mapstructure:"scheduledEndTime" json:"scheduledEndTime,omitempty" gorm:"column:scheduledEndTime" bson:"scheduledEndTime,omitempty" dynamodbav:"scheduledEndTime,omitempty" firestore:"scheduledEndTime,omitempty"`
+	ActualStartTime      *time.Time `mapstructure:"actualStartTime" json:"actualStartTime,omitempty" gorm:"column:actualStartTime" bson:"actualStartTime,omitempty" dynamodbav:"actualStartTime,omitempty" firestore:"actualStartTime,omitempty"`
+	ActualEndTime        *time.Time `mapstructure:"actualEndTime" json:"actualEndTime,omitempty" gorm:"column:actualEndTime" bson:"actualEndTime,omitempty" dynamodbav:"actualEndTime,omitempty" firestore:"actualEndTime,omitempty"`
 	LocalizedDescription string     `mapstructure:"localizedDescription" json:"localizedDescription,omitempty" gorm:"column:localizedDescription" bson:"localizedDescription,omitempty" dynamodbav:"localizedDescription,omitempty" firestore:"localizedDescription,omitempty"`
 	LocalizedTitle       string     `mapstructure:"localizedTitle" json:"localizedTitle,omitempty" gorm:"column:localizedTitle" bson:"localizedTitle,omitempty" dynamodbav:"localizedTitle,omitempty" firestore:"localizedTitle,omitempty"`
 	Projection           string     `mapstructure:"projection" json:"projection,omitempty" gorm:"column:projection" bson:"projection,omitempty" dynamodbav:"projection,omitempty" firestore:"projection,omitempty"`
+	YtRating             string     `mapstructure:"ytRating" json:"ytRating,omitempty" gorm:"column:ytRating" bson:"ytRating,omitempty" dynamodbav:"ytRating,omitempty" firestore:"ytRating,omitempty"`
 	PublishedAt          *time.Time `mapstructure:"publishedAt" json:"publishedAt,omitempty" gorm:"column:publishedAt" bson:"publishedAt,omitempty" dynamodbav:"publishedAt,omitempty" firestore:"publishedAt,omitempty"`
 	Tags                 []string   `mapstructure:"tags" json:"tags,omitempty" gorm:"column:tags" bson:"tags,omitempty" dynamodbav:"tags,omitempty" firestore:"tags,omitempty"`
+	TopicIds             []string   `mapstructure:"topicIds" json:"topicIds,omitempty" gorm:"column:topicIds" bson:"topicIds,omitempty" dynamodbav:"topicIds,omitempty" firestore:"topicIds,omitempty"`
 	Title                string     `mapstructure:"title" json:"title,omitempty" gorm:"column:title" bson:"title,omitempty" dynamodbav:"title,omitempty" firestore:"title,omitempty"`
 	BlockedRegions       []string   `mapstructure:"blockedRegions" json:"blockedRegions,omitempty" gorm:"column:blockedRegions" bson:"blockedRegions,omitempty" dynamodbav:"blockedRegions,omitempty" firestore:"blockedRegions,omitempty"`
 	AllowedRegions       []string   `mapstructure:"allowedRegions" json:"allowedRegions,omitempty" gorm:"column:allowedRegions" bson:"allowedRegions,omitempty" dynamodbav:"allowedRegions,omitempty" firestore:"allowedRegions,omitempty"`
+	ViewCount            *int64     `mapstructure:"viewCount" json:"viewCount,omitempty" gorm:"column:viewCount" bson:"viewCount,omitempty" dynamodbav:"viewCount,omitempty" firestore:"viewCount,omitempty"`
+	LikeCount            *int64     `mapstructure:"likeCount" json:"likeCount,omitempty" gorm:"column:likeCount" bson:"likeCount,omitempty" dynamodbav:"likeCount,omitempty" firestore:"likeCount,omitempty"`
+	CommentCount         *int64     `mapstructure:"commentCount" json:"commentCount,omitempty" gorm:"column:commentCount" bson:"commentCount,omitempty" dynamodbav:"commentCount,omitempty" firestore:"commentCount,omitempty"`
+	Status               string     `mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
+	TombstonedAt         *time.Time `mapstructure:"tombstonedAt" json:"tombstonedAt,omitempty" gorm:"column:tombstonedAt" bson:"tombstonedAt,omitempty" dynamodbav:"tombstonedAt,omitempty" firestore:"tombstonedAt,omitempty"`
+	Highlight            string     `mapstructure:"highlight" json:"highlight,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-"`
 }
 
 type VideoResult struct {
@@ -0,0 +1,46 @@
+package video
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// undiffed are the fields DiffVideo leaves out: the counts change on every sync and are kept as statistics snapshots.
+var undiffed = map[string]bool{"viewCount": true, "likeCount": true, "commentCount": true, "status": true, "tombstonedAt": true, "highlight": true}
+
+// DiffVideo returns the json names of the fields of current that differ from previous, the stored video.
+func DiffVideo(previous Video, current Video) []string {
+	var fields []string
+	p := reflect.ValueOf(previous)
+	c := reflect.ValueOf(current)
+	t := p.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if undiffed[name] {
+			continue
+		}
+		if !sameValue(p.Field(i), c.Field(i)) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func sameValue(a reflect.Value, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if t, ok := a.Interface().(*time.Time); ok {
+			return t.Equal(*b.Interface().(*time.Time))
+		}
+		return reflect.DeepEqual(a.Elem().Interface(), b.Elem().Interface())
+	case reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -1,6 +1,9 @@
 package video
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type VideoService interface {
 	GetChannel(ctx context.Context, channelId string, fields []string) (*Channel, error)
@@ -10,13 +13,18 @@ type VideoService interface {
 	GetVideo(ctx context.Context, id string, fields []string) (*Video, error)
 	GetVideos(ctx context.Context, ids []string, fields []string) (*[]Video, error)
 	GetChannelPlaylists(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*ListResultPlaylist, error)
-	GetChannelVideos(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
-	GetPlaylistVideos(ctx context.Context, playlistId string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
+	GetChannelVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
+	GetPlaylistVideos(ctx context.Context, playlistId string, regionCode string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
 	GetCategories(ctx context.Context, regionCode string) (*Categories, error)
 	SearchChannel(ctx context.Context, channelSM ChannelSM, max int, nextPageToken string, fields []string) (*ListResultChannel, error)
 	SearchPlaylists(ctx context.Context, playlistSM PlaylistSM, max int, nextPageToken string, fields []string) (*ListResultPlaylist, error)
 	SearchVideos(ctx context.Context, itemSM ItemSM, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
-	Search(ctx context.Context, itemSM ItemSM, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
+	Search(ctx context.Context, itemSM ItemSM, max int, nextPageToken string, fields []string) (*ListResultSearch, error)
 	GetRelatedVideos(ctx context.Context, videoId string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
 	GetPopularVideos(ctx context.Context, regionCode string, categoryId string, limit int, nextPageToken string, fields []string) (*ListResultVideos, error)
+	// GetTrendingVideos ranks by views gained per hour in the window.
+	GetTrendingVideos(ctx context.Context, regionCode string, categoryId string, window time.Duration, limit int, nextPageToken string, fields []string) (*ListResultVideos, error)
+	GetChannelSubscriptions(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*ListResultChannel, error)
+	GetChannelSubscribers(ctx context.Context, channelId string, max int, nextPageToken string, fields []string) (*ListResultChannel, error)
+	GetSubscriptionVideos(ctx context.Context, channelId string, regionCode string, max int, nextPageToken string, fields []string) (*ListResultVideos, error)
 }
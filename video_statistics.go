@@ -0,0 +1,11 @@
+package video
+
+import "time"
+
+type VideoStatistics struct {
+	VideoId      string     `mapstructure:"videoId" json:"videoId,omitempty" gorm:"column:videoId;primary_key" bson:"videoId,omitempty" dynamodbav:"videoId,omitempty" firestore:"videoId,omitempty"`
+	Timestamp    *time.Time `mapstructure:"timestamp" json:"timestamp,omitempty" gorm:"column:timestamp;primary_key" bson:"timestamp,omitempty" dynamodbav:"timestamp,omitempty" firestore:"timestamp,omitempty"`
+	ViewCount    *int64     `mapstructure:"viewCount" json:"viewCount,omitempty" gorm:"column:viewCount" bson:"viewCount,omitempty" dynamodbav:"viewCount,omitempty" firestore:"viewCount,omitempty"`
+	LikeCount    *int64     `mapstructure:"likeCount" json:"likeCount,omitempty" gorm:"column:likeCount" bson:"likeCount,omitempty" dynamodbav:"likeCount,omitempty" firestore:"likeCount,omitempty"`
+	CommentCount *int64     `mapstructure:"commentCount" json:"commentCount,omitempty" gorm:"column:commentCount" bson:"commentCount,omitempty" dynamodbav:"commentCount,omitempty" firestore:"commentCount,omitempty"`
+}
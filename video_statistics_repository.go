@@ -0,0 +1,7 @@
+package video
+
+import "context"
+
+type VideoStatisticsRepository interface {
+	SaveStatistics(ctx context.Context, statistics []VideoStatistics) (int, error)
+}
@@ -0,0 +1,57 @@
+package videotest
+
+import (
+	"testing"
+
+	"github.com/core-go/video"
+)
+
+// Backend is the service and the repositories of one store. Checkpoints may be nil, the sync cases then keep them in memory.
+type Backend struct {
+	Service       video.VideoService
+	Repository    video.SyncRepository
+	Statistics    video.VideoStatisticsRepository
+	Subscriptions video.SubscriptionRepository
+	PlaylistItems video.PlaylistItemRepository
+	Tombstones    video.TombstoneRepository
+	Checkpoints   video.SyncCheckpointRepository
+}
+
+// BackendFactory returns an empty Backend.
+type BackendFactory func(t *testing.T) Backend
+
+// RunBackendSuite checks trending, subscriptions, playlist items, tombstones and syncs from the fake YouTube server,
+// each on a new Backend.
+func RunBackendSuite(t *testing.T, factory BackendFactory) {
+	t.Run("Trending", func(t *testing.T) { testTrending(t, factory(t)) })
+	t.Run("Subscriptions", func(t *testing.T) { testSubscriptions(t, factory(t)) })
+	t.Run("PlaylistItems", func(t *testing.T) { testPlaylistItems(t, factory(t)) })
+	t.Run("Tombstones", func(t *testing.T) { testTombstones(t, factory(t)) })
+	t.Run("SyncChannel", func(t *testing.T) { testSyncChannel(t, factory) })
+	t.Run("SyncPlaylistCheckpoint", func(t *testing.T) { testSyncPlaylistCheckpoint(t, factory(t)) })
+	t.Run("SyncEvents", func(t *testing.T) { testSyncEvents(t, factory(t)) })
+}
+
+func videoPages(list func(next string) (*video.ListResultVideos, error)) func(next string) ([]string, string, error) {
+	return func(next string) ([]string, string, error) {
+		res, err := list(next)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return videoIds(res.List), res.NextPageToken, nil
+	}
+}
+
+func channelPages(list func(next string) (*video.ListResultChannel, error)) func(next string) ([]string, string, error) {
+	return func(next string) ([]string, string, error) {
+		res, err := list(next)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		ids := make([]string, 0, len(res.List))
+		for _, c := range res.List {
+			ids = append(ids, c.Id)
+		}
+		return ids, res.NextPageToken, nil
+	}
+}
@@ -0,0 +1,83 @@
+package videotest
+
+import (
+	"context"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+type Dataset struct {
+	Channels       []video.Channel
+	Playlists      []video.Playlist
+	Videos         []video.Video
+	PlaylistVideos map[string][]string
+}
+
+func NewDataset() Dataset {
+	return Dataset{
+		Channels: []video.Channel{
+			{Id: "chan1", Title: "Gopher Channel", Description: "all about gophers", Country: "US", Uploads: "upl1", PublishedAt: at(1), ViewCount: number(15500), SubscriberCount: number(320), VideoCount: number(5)},
+			{Id: "chan2", Title: "Cooking Channel", Description: "recipes for every day", Country: "VN", Uploads: "upl2", PublishedAt: at(2), ViewCount: number(950), VideoCount: number(2)},
+		},
+		Playlists: []video.Playlist{
+			{Id: "pl1", ChannelId: "chan1", ChannelTitle: "Gopher Channel", Title: "gopher basics", Description: "first steps", PublishedAt: at(10), Count: count(3), ItemCount: count(3)},
+			{Id: "pl2", ChannelId: "chan1", ChannelTitle: "Gopher Channel", Title: "gopher advanced", Description: "going further", PublishedAt: at(11), Count: count(2), ItemCount: count(2)},
+			{Id: "pl3", ChannelId: "chan2", ChannelTitle: "Cooking Channel", Title: "soups", Description: "warm soup recipes", PublishedAt: at(12), Count: count(2), ItemCount: count(2)},
+		},
+		Videos: []video.Video{
+			{Id: "vid1", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "27", Title: "gopher tour 1", Description: "a gopher video", Duration: 120, Caption: "true", Definition: 5, Dimension: "2d", License: "creativeCommon", Embeddable: flag(true), DefaultLanguage: "en", Tags: []string{"go", "tour"}, TopicIds: []string{"/m/07c1v"}, AllowedRegions: []string{"US", "DE"}, BlockedRegions: []string{"DE"}, ViewCount: number(5000), LikeCount: number(210), PublishedAt: at(20)},
+			{Id: "vid2", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "27", Title: "gopher tour 2", Description: "a gopher video", Duration: 600, Caption: "false", Definition: 4, Dimension: "2d", License: "youtube", Embeddable: flag(true), DefaultLanguage: "en-US", Tags: []string{"go", "tour"}, ViewCount: number(1200), LikeCount: number(40), PublishedAt: at(21)},
+			{Id: "vid3", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "28", Title: "gopher concurrency", Description: "a gopher video", Duration: 1800, Caption: "true", Definition: 5, Dimension: "3d", License: "youtube", Embeddable: flag(false), LiveBroadcastContent: "live", Tags: []string{"go", "concurrency"}, TopicIds: []string{"/m/07c1v"}, BlockedRegions: []string{"DE"}, ViewCount: number(9000), LikeCount: number(700), PublishedAt: at(22)},
+			{Id: "vid4", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "43", Title: "gopher generics", Description: "a gopher video", Duration: 900, Definition: 5, Dimension: "2d", License: "youtube", Embeddable: flag(true), YtRating: video.AgeRestricted, LiveBroadcastContent: "upcoming", DefaultLanguage: "vi", Tags: []string{"generics"}, AllowedRegions: []string{"VN"}, ViewCount: number(300), LikeCount: number(12), PublishedAt: at(23)},
+			{Id: "vid5", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "30", Title: "gopher news", Description: "a gopher video", Duration: 200, PublishedAt: at(24)},
+			{Id: "vid6", ChannelId: "chan2", ChannelTitle: "Cooking Channel", CategoryId: "26", Title: "tomato soup", Description: "a soup video", Duration: 300, Caption: "true", Definition: 4, Dimension: "2d", License: "creativeCommon", Embeddable: flag(true), DefaultLanguage: "vi", Tags: []string{"soup", "tomato"}, TopicIds: []string{"/m/02wbm"}, ViewCount: number(800), LikeCount: number(35), PublishedAt: at(25)},
+			{Id: "vid7", ChannelId: "chan2", ChannelTitle: "Cooking Channel", CategoryId: "26", Title: "onion soup", Description: "a soup video", Duration: 420, Tags: []string{"soup"}, ViewCount: number(150), LikeCount: number(4), PublishedAt: at(26)},
+		},
+		PlaylistVideos: map[string][]string{
+			"pl1": {"vid1", "vid2", "vid3"},
+			"pl2": {"vid4", "vid5"},
+			"pl3": {"vid6", "vid7"},
+		},
+	}
+}
+
+// Seed writes the dataset through the same SyncRepository calls a channel sync makes.
+func Seed(ctx context.Context, repository video.SyncRepository, data Dataset) error {
+	for _, channel := range data.Channels {
+		if _, err := repository.SaveChannel(ctx, channel); err != nil {
+			return err
+		}
+	}
+	if _, err := repository.SavePlaylists(ctx, data.Playlists); err != nil {
+		return err
+	}
+	if _, err := repository.SaveVideos(ctx, data.Videos); err != nil {
+		return err
+	}
+	for _, playlist := range data.Playlists {
+		if ids, ok := data.PlaylistVideos[playlist.Id]; ok {
+			if _, err := repository.SavePlaylistVideos(ctx, playlist.Id, ids); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func at(day int) *time.Time {
+	t := time.Date(2021, time.January, day, 8, 30, 0, 0, time.UTC)
+	return &t
+}
+
+func count(n int) *int {
+	return &n
+}
+
+func number(n int64) *int64 {
+	return &n
+}
+
+func flag(b bool) *bool {
+	return &b
+}
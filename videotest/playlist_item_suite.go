@@ -0,0 +1,115 @@
+package videotest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/core-go/video"
+)
+
+func testPlaylistItems(t *testing.T, b Backend) {
+	ctx := context.Background()
+	if err := Seed(ctx, b.Repository, NewDataset()); err != nil {
+		t.Fatal(err)
+	}
+	added := func(videoId string, ownerChannelId string, day int) video.PlaylistVideo {
+		return video.PlaylistVideo{Id: videoId, VideoOwnerChannelId: ownerChannelId, PublishedAt: at(day)}
+	}
+	// each sync of pl1 runs after the one before, as the sync service diffs it
+	tests := []struct {
+		name    string
+		day     int
+		ids     []string
+		details []video.PlaylistVideo
+		changes video.PlaylistChanges
+		order   []string
+		inDE    []string
+		check   func(t *testing.T, items map[string]video.PlaylistItem)
+	}{
+		{
+			name:    "added",
+			day:     30,
+			ids:     []string{"vid1", "vid2", "vid3"},
+			details: []video.PlaylistVideo{added("vid1", "chan1", 30), added("vid2", "chan1", 30), added("vid3", "chan1", 30)},
+			changes: video.PlaylistChanges{Added: []string{"vid1", "vid2", "vid3"}},
+			order:   []string{"vid1", "vid2", "vid3"},
+			inDE:    []string{"vid1", "vid2"},
+		},
+		{
+			name:    "removed and moved",
+			day:     31,
+			ids:     []string{"vid3", "vid1", "vid5"},
+			details: []video.PlaylistVideo{added("vid5", "chan2", 31)},
+			changes: video.PlaylistChanges{Added: []string{"vid5"}, Removed: []string{"vid2"}, Moved: []string{"vid3"}},
+			order:   []string{"vid3", "vid1", "vid5"},
+			inDE:    []string{"vid1", "vid5"},
+			check: func(t *testing.T, items map[string]video.PlaylistItem) {
+				if len(items) != 4 {
+					t.Errorf("stored %d items; want 4 with the removed one", len(items))
+				}
+				if removed := items["vid2"]; !sameTime(removed.RemovedAt, at(31)) {
+					t.Errorf("vid2 removed at %v; want day 31", removed.RemovedAt)
+				}
+				if item := items["vid5"]; item.Position != 2 || item.VideoOwnerChannelId != "chan2" || !sameTime(item.AddedAt, at(31)) {
+					t.Errorf("vid5 = %+v", item)
+				}
+				// the sync has not the details of vid1, which keeps its owner and time added
+				if item := items["vid1"]; item.Position != 1 || item.VideoOwnerChannelId != "chan1" || !sameTime(item.AddedAt, at(30)) || item.RemovedAt != nil {
+					t.Errorf("vid1 = %+v", item)
+				}
+			},
+		},
+		{
+			name:    "added back",
+			day:     32,
+			ids:     []string{"vid3", "vid2"},
+			changes: video.PlaylistChanges{Added: []string{"vid2"}, Removed: []string{"vid1", "vid5"}},
+			order:   []string{"vid3", "vid2"},
+			inDE:    []string{"vid2"},
+			check: func(t *testing.T, items map[string]video.PlaylistItem) {
+				if item := items["vid2"]; item.RemovedAt != nil || item.Position != 1 {
+					t.Errorf("vid2 = %+v", item)
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			previous, er0 := b.PlaylistItems.GetPlaylistItems(ctx, "pl1")
+			if er0 != nil {
+				t.Fatal(er0)
+			}
+			items, changes := video.DiffPlaylistItems(previous, video.NewPlaylistItems("pl1", tc.ids, tc.details), *at(tc.day))
+			if _, er1 := b.PlaylistItems.SavePlaylistItems(ctx, items); er1 != nil {
+				t.Fatal(er1)
+			}
+			if !reflect.DeepEqual(changes, tc.changes) {
+				t.Errorf("changes = %+v; want %+v", changes, tc.changes)
+			}
+			for regionCode, expected := range map[string][]string{"": tc.order, "DE": tc.inDE} {
+				expectOrder(t, collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+					return b.Service.GetPlaylistVideos(ctx, "pl1", regionCode, 1, next, nil)
+				})), expected...)
+			}
+			res, er2 := b.Service.GetPlaylistVideos(ctx, "pl1", "", 1, "", nil)
+			if er2 != nil {
+				t.Fatal(er2)
+			}
+			if res.Total != len(tc.ids) {
+				t.Errorf("total = %d; want %d", res.Total, len(tc.ids))
+			}
+			if tc.check != nil {
+				stored, er3 := b.PlaylistItems.GetPlaylistItems(ctx, "pl1")
+				if er3 != nil {
+					t.Fatal(er3)
+				}
+				m := make(map[string]video.PlaylistItem, len(stored))
+				for _, item := range stored {
+					m[item.VideoId] = item
+				}
+				tc.check(t, m)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package videotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+func testSubscriptions(t *testing.T, b Backend) {
+	ctx := context.Background()
+	if err := Seed(ctx, b.Repository, NewDataset()); err != nil {
+		t.Fatal(err)
+	}
+	edge := func(subscriberId string, channelId string, title string) video.Subscription {
+		return video.NewSubscription(subscriberId, video.Channel{Id: channelId, Title: title, PublishedAt: at(30)})
+	}
+	// chan1 drops chan7 on its second save; chan9 and chan8 are not synced
+	saves := []struct {
+		subscriberId  string
+		subscriptions []video.Subscription
+	}{
+		{"chan1", []video.Subscription{edge("chan1", "chan2", "Cooking Channel"), edge("chan1", "chan7", "Gone Channel")}},
+		{"chan1", []video.Subscription{edge("chan1", "chan2", "Cooking Channel"), edge("chan1", "chan9", "Unsynced Channel")}},
+		{"chan2", []video.Subscription{edge("chan2", "chan1", "Gopher Channel")}},
+		{"chan8", []video.Subscription{edge("chan8", "chan2", "Cooking Channel")}},
+	}
+	for _, s := range saves {
+		if _, err := b.Subscriptions.SaveSubscriptions(ctx, s.subscriberId, s.subscriptions); err != nil {
+			t.Fatal(err)
+		}
+	}
+	subscriptions := func(channelId string) func(next string) ([]string, string, error) {
+		return channelPages(func(next string) (*video.ListResultChannel, error) {
+			return b.Service.GetChannelSubscriptions(ctx, channelId, 1, next, nil)
+		})
+	}
+	subscribers := func(channelId string) func(next string) ([]string, string, error) {
+		return channelPages(func(next string) (*video.ListResultChannel, error) {
+			return b.Service.GetChannelSubscribers(ctx, channelId, 1, next, nil)
+		})
+	}
+	feed := func(channelId string, regionCode string) func(next string) ([]string, string, error) {
+		return videoPages(func(next string) (*video.ListResultVideos, error) {
+			return b.Service.GetSubscriptionVideos(ctx, channelId, regionCode, 2, next, nil)
+		})
+	}
+	tests := []struct {
+		name     string
+		page     func(next string) ([]string, string, error)
+		expected []string
+	}{
+		{name: "subscriptions of chan1", page: subscriptions("chan1"), expected: []string{"chan2", "chan9"}},
+		{name: "subscriptions of chan2", page: subscriptions("chan2"), expected: []string{"chan1"}},
+		{name: "subscriptions of a channel not synced", page: subscriptions("chan9")},
+		{name: "subscribers of chan2", page: subscribers("chan2"), expected: []string{"chan1"}},
+		{name: "subscribers of chan1", page: subscribers("chan1"), expected: []string{"chan2"}},
+		{name: "subscribers of a channel not synced", page: subscribers("chan9"), expected: []string{"chan1"}},
+		{name: "subscribers of a dropped subscription", page: subscribers("chan7")},
+		{name: "videos of chan1", page: feed("chan1", ""), expected: []string{"vid7", "vid6"}},
+		{name: "videos of chan2", page: feed("chan2", ""), expected: []string{"vid5", "vid4", "vid3", "vid2", "vid1"}},
+		{name: "videos of chan2 in a region", page: feed("chan2", "VN"), expected: []string{"vid5", "vid4", "vid3", "vid2"}},
+		{name: "videos of a channel not synced", page: feed("chan9", "")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expectOrder(t, collect(t, tc.page), tc.expected...)
+		})
+	}
+	t.Run("synced channels as stored", func(t *testing.T) {
+		res, err := b.Service.GetChannelSubscriptions(ctx, "chan1", 10, "", nil)
+		if err != nil || res == nil || len(res.List) != 2 {
+			t.Fatalf("GetChannelSubscriptions = %+v, %v", res, err)
+		}
+		if res.List[0].Id != "chan2" || res.List[0].Country != "VN" {
+			t.Errorf("synced channel = %+v; want chan2 as stored", res.List[0])
+		}
+		if res.List[1].Id != "chan9" || res.List[1].Title != "Unsynced Channel" {
+			t.Errorf("channel not synced = %+v; want chan9 from the subscription", res.List[1])
+		}
+	})
+	t.Run("bad token", func(t *testing.T) {
+		if _, err := b.Service.GetSubscriptionVideos(ctx, "chan1", "", 2, "bogus", nil); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("error = %v; want cursor.ErrInvalid", err)
+		}
+	})
+}
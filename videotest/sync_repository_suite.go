@@ -0,0 +1,65 @@
+package videotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+func RunSyncRepositorySuite(t *testing.T, factory func(t *testing.T) video.SyncRepository) {
+	ctx := context.Background()
+	repository := factory(t)
+	data := NewDataset()
+	if err := Seed(ctx, repository, data); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	t.Run("Seed is idempotent", func(t *testing.T) {
+		if err := Seed(ctx, repository, data); err != nil {
+			t.Fatalf("second seed: %v", err)
+		}
+	})
+	t.Run("GetVideoIds", func(t *testing.T) {
+		ids, err := repository.GetVideoIds(ctx, []string{"vid1", "vid7", "unknown"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectSet(t, ids, "vid1", "vid7")
+		ids, err = repository.GetVideoIds(ctx, []string{})
+		if err != nil || len(ids) != 0 {
+			t.Errorf("GetVideoIds([]) = %v, %v; want empty", ids, err)
+		}
+	})
+	t.Run("ChannelSync", func(t *testing.T) {
+		missing, err := repository.GetChannelSync(ctx, "unknown")
+		if err != nil || missing != nil {
+			t.Fatalf("GetChannelSync(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+		synctime := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+		if _, err := repository.SaveChannelSync(ctx, video.ChannelSync{Id: "chan1", Synctime: &synctime, Uploads: "upl1"}); err != nil {
+			t.Fatal(err)
+		}
+		updated := synctime.Add(time.Hour)
+		if _, err := repository.SaveChannelSync(ctx, video.ChannelSync{Id: "chan1", Synctime: &updated, Uploads: "upl1"}); err != nil {
+			t.Fatal(err)
+		}
+		res, err := repository.GetChannelSync(ctx, "chan1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil || res.Uploads != "upl1" || !sameTime(res.Synctime, &updated) {
+			t.Errorf("GetChannelSync(chan1) = %+v", res)
+		}
+		syncs, err := repository.GetChannelSyncs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, s := range syncs {
+			ids = append(ids, s.Id)
+		}
+		expectSet(t, ids, "chan1")
+	})
+}
@@ -0,0 +1,265 @@
+package videotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+	videosync "github.com/core-go/video/sync"
+	"github.com/core-go/video/test"
+	"github.com/core-go/video/youtube"
+)
+
+// YoutubeFixtures is the path of the fixtures the fake YouTube server serves, from the directory of a backend package.
+var YoutubeFixtures = "../test/testdata/youtube.json"
+
+const fixtureChannel = "UCfake000000000000000001"
+
+var fixtureVideos = []string{"vid00000001", "vid00000002", "vid00000003", "vid00000004", "vid00000005", "vid00000006", "vid00000007"}
+
+func loadFixtures(t *testing.T) *test.Fixtures {
+	fixtures, err := test.LoadFixtures(YoutubeFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fixtures
+}
+
+func newFakeClient(t *testing.T, fixtures *test.Fixtures) (*test.FakeYoutubeServer, *youtube.YoutubeSyncClient) {
+	fake := test.NewFakeYoutubeServer(fixtures)
+	server := fake.Start()
+	t.Cleanup(server.Close)
+	client := test.NewFakeSyncClient(server)
+	client.Backoff = time.Millisecond
+	return fake, client
+}
+
+// memoryCheckpoints stands in for the checkpoints of a Backend that has none.
+type memoryCheckpoints struct {
+	mutex       sync.Mutex
+	checkpoints map[string]video.SyncCheckpoint
+}
+
+func (r *memoryCheckpoints) GetCheckpoint(ctx context.Context, id string) (*video.SyncCheckpoint, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if checkpoint, ok := r.checkpoints[id]; ok {
+		return &checkpoint, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryCheckpoints) SaveCheckpoint(ctx context.Context, checkpoint video.SyncCheckpoint) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.checkpoints[checkpoint.Id] = checkpoint
+	return 1, nil
+}
+
+func (r *memoryCheckpoints) DeleteCheckpoint(ctx context.Context, id string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.checkpoints, id)
+	return 1, nil
+}
+
+// savedCheckpoints calls onSave after each checkpoint is saved.
+type savedCheckpoints struct {
+	video.SyncCheckpointRepository
+	onSave func()
+}
+
+func (r savedCheckpoints) SaveCheckpoint(ctx context.Context, checkpoint video.SyncCheckpoint) (int, error) {
+	res, err := r.SyncCheckpointRepository.SaveCheckpoint(ctx, checkpoint)
+	if err == nil {
+		r.onSave()
+	}
+	return res, err
+}
+
+// slowRepository saves videos late, so the uploads and the playlists of a channel synced at the same time both find
+// the videos they have in common new.
+type slowRepository struct {
+	video.SyncRepository
+}
+
+func (r slowRepository) SaveVideos(ctx context.Context, videos []video.Video) (int, error) {
+	time.Sleep(20 * time.Millisecond)
+	return r.SyncRepository.SaveVideos(ctx, videos)
+}
+
+func testSyncChannel(t *testing.T, factory BackendFactory) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		endpoint string
+		status   int
+		reason   string
+		err      func(err error) bool
+	}{
+		{name: "synced"},
+		{name: "backend error retried", endpoint: "playlistItems", status: http.StatusServiceUnavailable, reason: "backendError"},
+		{name: "quota exceeded", endpoint: "channels", status: http.StatusForbidden, reason: "quotaExceeded", err: youtube.IsQuotaExceeded},
+		{name: "not found", endpoint: "channels", status: http.StatusNotFound, reason: "notFound", err: func(err error) bool { return errors.Is(err, youtube.ErrNotFound) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := factory(t)
+			fake, client := newFakeClient(t, loadFixtures(t))
+			if len(tc.endpoint) > 0 {
+				fake.Fail(tc.endpoint, tc.status, tc.reason, 1)
+			}
+			_, er1 := videosync.NewDefaultSyncService(client, b.Repository).SyncChannel(ctx, fixtureChannel)
+			if tc.err != nil {
+				if !tc.err(er1) {
+					t.Fatalf("err = %v", er1)
+				}
+				return
+			}
+			if er1 != nil {
+				t.Fatal(er1)
+			}
+			ids, er2 := b.Repository.GetVideoIds(ctx, fixtureVideos)
+			if er2 != nil {
+				t.Fatal(er2)
+			}
+			expectSet(t, ids, fixtureVideos...)
+			playlist, er3 := b.Service.GetPlaylist(ctx, "PLfake000000000000000001", nil)
+			if er3 != nil {
+				t.Fatal(er3)
+			}
+			if playlist == nil || playlist.Count == nil || *playlist.Count != 3 {
+				t.Errorf("playlist = %+v; want 3 videos", playlist)
+			}
+		})
+	}
+}
+
+// newPlaylistFixtures returns a playlist of n videos, more than a page of playlist items.
+func newPlaylistFixtures(playlistId string, n int) *test.Fixtures {
+	fixtures := &test.Fixtures{}
+	fixtures.Playlists = append(fixtures.Playlists, map[string]interface{}{
+		"id":             playlistId,
+		"snippet":        map[string]interface{}{"channelId": fixtureChannel, "title": "Long playlist", "publishedAt": "2024-01-01T00:00:00Z"},
+		"contentDetails": map[string]interface{}{"itemCount": n},
+	})
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("vid%08d", 100+i)
+		publishedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		fixtures.PlaylistItems = append(fixtures.PlaylistItems, map[string]interface{}{
+			"id": fmt.Sprintf("item%d", i),
+			"snippet": map[string]interface{}{"playlistId": playlistId, "position": i, "title": id, "publishedAt": publishedAt,
+				"channelId": fixtureChannel, "resourceId": map[string]interface{}{"kind": "youtube#video", "videoId": id}},
+			"contentDetails": map[string]interface{}{"videoId": id, "videoPublishedAt": publishedAt},
+		})
+		fixtures.Videos = append(fixtures.Videos, map[string]interface{}{
+			"id":             id,
+			"snippet":        map[string]interface{}{"channelId": fixtureChannel, "title": id, "publishedAt": publishedAt},
+			"contentDetails": map[string]interface{}{"duration": "PT1M"},
+		})
+	}
+	return fixtures
+}
+
+func testSyncPlaylistCheckpoint(t *testing.T, b Backend) {
+	ctx := context.Background()
+	fake, client := newFakeClient(t, newPlaylistFixtures("PLlong", 120))
+	var checkpoints video.SyncCheckpointRepository = &memoryCheckpoints{checkpoints: make(map[string]video.SyncCheckpoint)}
+	if b.Checkpoints != nil {
+		checkpoints = b.Checkpoints
+	}
+	// the page after the first checkpoint fails, with an error that is not retried
+	var once sync.Once
+	checkpoints = savedCheckpoints{SyncCheckpointRepository: checkpoints, onSave: func() {
+		once.Do(func() { fake.Fail("playlistItems", http.StatusBadRequest, "invalidParameter", 1) })
+	}}
+	service := videosync.NewDefaultSyncService(client, b.Repository, checkpoints)
+	if _, err := service.SyncPlaylist(ctx, "PLlong", nil); err == nil {
+		t.Fatal("first sync succeeded; want it to fail on the second page")
+	}
+	checkpoint, er0 := checkpoints.GetCheckpoint(ctx, "PLlong")
+	if er0 != nil {
+		t.Fatal(er0)
+	}
+	if checkpoint == nil || checkpoint.Count != 50 || len(checkpoint.Videos) != 50 {
+		t.Fatalf("checkpoint = %+v; want the first page", checkpoint)
+	}
+	if _, err := service.SyncPlaylist(ctx, "PLlong", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := fake.Requests("playlistItems"); n != 4 {
+		t.Errorf("requested %d pages of playlist items; want 4, the first page once", n)
+	}
+	if checkpoint, _ = checkpoints.GetCheckpoint(ctx, "PLlong"); checkpoint != nil {
+		t.Errorf("checkpoint = %+v; want it deleted", checkpoint)
+	}
+	ids := collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+		return b.Service.GetPlaylistVideos(ctx, "PLlong", "", 50, next, nil)
+	}))
+	if len(ids) != 120 {
+		t.Errorf("playlist has %d videos; want 120", len(ids))
+	}
+}
+
+func testSyncEvents(t *testing.T, b Backend) {
+	ctx := context.Background()
+	fixtures := loadFixtures(t)
+	_, client := newFakeClient(t, fixtures)
+	service := videosync.NewDefaultSyncService(client, slowRepository{b.Repository})
+	service.Statistics = b.Statistics
+	service.Videos = b.Service
+	publisher := videosync.NewChannelPublisher(100)
+	service.Events = publisher
+	received := func() map[string][]video.SyncEvent {
+		events := make(map[string][]video.SyncEvent)
+		for len(publisher.Events) > 0 {
+			event := <-publisher.Events
+			events[event.Type] = append(events[event.Type], event)
+		}
+		return events
+	}
+	if _, err := service.SyncChannel(ctx, fixtureChannel); err != nil {
+		t.Fatal(err)
+	}
+	events := received()
+	added := make(map[string]int)
+	for _, event := range events[video.VideoAdded] {
+		for _, id := range event.VideoIds {
+			added[id]++
+		}
+	}
+	for _, id := range fixtureVideos {
+		if added[id] != 1 {
+			t.Errorf("%s added %d times; want once", id, added[id])
+		}
+	}
+	if updated := events[video.VideoUpdated]; len(updated) > 0 {
+		t.Errorf("first sync updated %+v; want none", updated)
+	}
+	for _, v := range fixtures.Videos {
+		statistics := v["statistics"].(map[string]interface{})
+		statistics["viewCount"] = "99999"
+		if v["id"] == "vid00000001" {
+			v["snippet"].(map[string]interface{})["title"] = "Renamed video"
+		}
+	}
+	if _, err := service.SyncChannel(ctx, fixtureChannel); err != nil {
+		t.Fatal(err)
+	}
+	events = received()
+	if len(events[video.VideoAdded]) > 0 {
+		t.Errorf("second sync added %+v; want none", events[video.VideoAdded])
+	}
+	updated := events[video.VideoUpdated]
+	if len(updated) != 1 || len(updated[0].VideoIds) != 1 || updated[0].VideoIds[0] != "vid00000001" {
+		t.Fatalf("updated %+v; want vid00000001 only, the counts are not changes", updated)
+	}
+	if diff := updated[0].Diffs["vid00000001"]; len(diff) != 1 || diff[0] != "title" {
+		t.Errorf("diff = %v; want [title]", diff)
+	}
+}
@@ -0,0 +1,113 @@
+package videotest
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/core-go/video"
+)
+
+func testTombstones(t *testing.T, b Backend) {
+	ctx := context.Background()
+	data := NewDataset()
+	if err := Seed(ctx, b.Repository, data); err != nil {
+		t.Fatal(err)
+	}
+	// vid9 is not stored, and vid2 tombstoned again keeps the time it was first found gone
+	tombstones := []struct {
+		ids      []string
+		status   string
+		day      int
+		expected int
+	}{
+		{[]string{"vid2", "vid9"}, video.VideoDeleted, 40, 1},
+		{[]string{"vid2"}, video.VideoDeleted, 41, 0},
+		{[]string{"vid3"}, video.VideoPrivate, 41, 1},
+	}
+	for _, s := range tombstones {
+		res, err := b.Tombstones.TombstoneVideos(ctx, s.ids, s.status, *at(s.day))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != s.expected {
+			t.Errorf("tombstoned %d of %v; want %d", res, s.ids, s.expected)
+		}
+	}
+	channelVideos := func(ctx context.Context) []string {
+		return collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+			return b.Service.GetChannelVideos(ctx, "chan1", "", 2, next, nil)
+		}))
+	}
+	playlistVideos := func(ctx context.Context) []string {
+		return collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+			return b.Service.GetPlaylistVideos(ctx, "pl1", "", 2, next, nil)
+		}))
+	}
+	searched := func(ctx context.Context) []string {
+		ids := collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+			return b.Service.SearchVideos(ctx, video.ItemSM{Q: "gopher"}, 2, next, nil)
+		}))
+		sort.Strings(ids)
+		return ids
+	}
+	all := video.WithUnavailable(ctx)
+	tests := []struct {
+		name     string
+		list     func(ctx context.Context) []string
+		ctx      context.Context
+		expected []string
+	}{
+		{name: "channel videos", list: channelVideos, ctx: ctx, expected: []string{"vid5", "vid4", "vid1"}},
+		{name: "playlist videos", list: playlistVideos, ctx: ctx, expected: []string{"vid1"}},
+		{name: "searched videos", list: searched, ctx: ctx, expected: []string{"vid1", "vid4", "vid5"}},
+		{name: "channel videos with unavailable", list: channelVideos, ctx: all, expected: []string{"vid5", "vid4", "vid3", "vid2", "vid1"}},
+		{name: "playlist videos with unavailable", list: playlistVideos, ctx: all, expected: []string{"vid1", "vid2", "vid3"}},
+		{name: "searched videos with unavailable", list: searched, ctx: all, expected: []string{"vid1", "vid2", "vid3", "vid4", "vid5"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expectOrder(t, tc.list(tc.ctx), tc.expected...)
+		})
+	}
+	t.Run("tombstoned", func(t *testing.T) {
+		v, err := b.Service.GetVideo(ctx, "vid2", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == nil || v.Status != video.VideoDeleted || !sameTime(v.TombstonedAt, at(40)) {
+			t.Errorf("vid2 = %+v; want deleted on day 40", v)
+		}
+		// a tombstoned video is left out, so the sync fetches it again
+		ids, err := b.Repository.GetVideoIds(ctx, []string{"vid1", "vid2", "vid3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectOrder(t, ids, "vid1")
+	})
+	t.Run("restored", func(t *testing.T) {
+		if _, err := b.Repository.SaveVideos(ctx, []video.Video{data.Videos[2]}); err != nil {
+			t.Fatal(err)
+		}
+		v, err := b.Service.GetVideo(ctx, "vid3", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == nil || video.Tombstoned(*v) || v.TombstonedAt != nil {
+			t.Errorf("vid3 = %+v; want restored", v)
+		}
+		expectOrder(t, playlistVideos(ctx), "vid1", "vid3")
+	})
+	t.Run("purged", func(t *testing.T) {
+		for _, c := range []struct{ day, expected int }{{40, 0}, {41, 1}} {
+			res, err := b.Tombstones.PurgeVideos(ctx, video.VideoDeleted, *at(c.day))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != c.expected {
+				t.Errorf("purged %d videos tombstoned before day %d; want %d", res, c.day, c.expected)
+			}
+		}
+		expectOrder(t, channelVideos(all), "vid5", "vid4", "vid3", "vid1")
+	})
+}
@@ -0,0 +1,65 @@
+package videotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+)
+
+func testTrending(t *testing.T, b Backend) {
+	ctx := context.Background()
+	data := NewDataset()
+	now := time.Now().UTC().Truncate(time.Second)
+	published := now.Add(-3 * time.Hour)
+	data.Videos = append(data.Videos, video.Video{Id: "vid8", ChannelId: "chan2", ChannelTitle: "Cooking Channel", CategoryId: "26", Title: "pumpkin soup", Tags: []string{"soup"}, PublishedAt: &published})
+	if err := Seed(ctx, b.Repository, data); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := func(id string, hours int, views int64) video.VideoStatistics {
+		t := now.Add(-time.Duration(hours) * time.Hour)
+		return video.VideoStatistics{VideoId: id, Timestamp: &t, ViewCount: &views}
+	}
+	snapshots := []video.VideoStatistics{
+		snapshot("vid1", 30, 1000), snapshot("vid1", 20, 1100), snapshot("vid1", 2, 1500),
+		snapshot("vid2", 20, 100), snapshot("vid2", 2, 1000),
+		snapshot("vid3", 20, 5000), snapshot("vid3", 2, 5360),
+		snapshot("vid4", 40, 300), snapshot("vid4", 30, 330),
+		snapshot("vid6", 10, 100), snapshot("vid6", 2, 580),
+		snapshot("vid7", 2, 150),
+		snapshot("vid8", 1, 400),
+	}
+	if _, err := b.Statistics.SaveStatistics(ctx, snapshots); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		regionCode string
+		categoryId string
+		window     time.Duration
+		expected   []string
+	}{
+		// vid8 is measured from its publish time, vid7 has a single snapshot and vid4 none in the window
+		{name: "views gained per hour", window: 24 * time.Hour, expected: []string{"vid8", "vid6", "vid2", "vid1", "vid3"}},
+		{name: "category", categoryId: "27", window: 24 * time.Hour, expected: []string{"vid2", "vid1"}},
+		{name: "region", regionCode: "DE", window: 24 * time.Hour, expected: []string{"vid8", "vid6", "vid2", "vid1"}},
+		{name: "longer window", window: 48 * time.Hour, expected: []string{"vid8", "vid6", "vid2", "vid3", "vid1", "vid4"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expectOrder(t, collect(t, videoPages(func(next string) (*video.ListResultVideos, error) {
+				return b.Service.GetTrendingVideos(ctx, tc.regionCode, tc.categoryId, tc.window, 2, next, nil)
+			})), tc.expected...)
+		})
+	}
+	t.Run("fields", func(t *testing.T) {
+		res, err := b.Service.GetTrendingVideos(ctx, "", "", 24*time.Hour, 1, "", []string{"id", "title"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil || len(res.List) != 1 || res.List[0].Id != "vid8" || res.List[0].Title != "pumpkin soup" || len(res.List[0].ChannelId) > 0 {
+			t.Errorf("GetTrendingVideos(fields id, title) = %+v", res)
+		}
+	})
+}
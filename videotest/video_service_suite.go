@@ -0,0 +1,495 @@
+package videotest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/core-go/video"
+	"github.com/core-go/video/cursor"
+)
+
+// Factory returns an empty backend whose VideoService reads what its SyncRepository writes.
+type Factory func(t *testing.T) (video.VideoService, video.SyncRepository)
+
+// RunVideoServiceSuite checks the read methods of the VideoService on NewDataset, except GetCategories.
+func RunVideoServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+	service, repository := factory(t)
+	data := NewDataset()
+	if err := Seed(ctx, repository, data); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	videos := make(map[string]video.Video)
+	for _, v := range data.Videos {
+		videos[v.Id] = v
+	}
+
+	t.Run("GetChannel", func(t *testing.T) {
+		res, err := service.GetChannel(ctx, "chan1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil || res.Id != "chan1" || res.Title != data.Channels[0].Title || res.Uploads != data.Channels[0].Uploads || !sameTime(res.PublishedAt, data.Channels[0].PublishedAt) {
+			t.Errorf("GetChannel(chan1) = %+v", res)
+		} else if !sameCount(res.ViewCount, data.Channels[0].ViewCount) || !sameCount(res.SubscriberCount, data.Channels[0].SubscriberCount) || !sameCount(res.VideoCount, data.Channels[0].VideoCount) {
+			t.Errorf("GetChannel(chan1) statistics = %v, %v, %v", res.ViewCount, res.SubscriberCount, res.VideoCount)
+		}
+		missing, err := service.GetChannel(ctx, "unknown", nil)
+		if err != nil || missing != nil {
+			t.Errorf("GetChannel(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+	t.Run("GetChannels", func(t *testing.T) {
+		res, err := service.GetChannels(ctx, []string{"chan1", "chan2", "unknown"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil {
+			t.Fatal("GetChannels returned nil")
+		}
+		var ids []string
+		for _, c := range *res {
+			ids = append(ids, c.Id)
+		}
+		expectSet(t, ids, "chan1", "chan2")
+	})
+	t.Run("GetPlaylist", func(t *testing.T) {
+		res, err := service.GetPlaylist(ctx, "pl3", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil || res.Id != "pl3" || res.ChannelId != "chan2" || res.Title != data.Playlists[2].Title || !sameTime(res.PublishedAt, data.Playlists[2].PublishedAt) {
+			t.Errorf("GetPlaylist(pl3) = %+v", res)
+		}
+		missing, err := service.GetPlaylist(ctx, "unknown", nil)
+		if err != nil || missing != nil {
+			t.Errorf("GetPlaylist(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+	t.Run("GetPlaylists", func(t *testing.T) {
+		res, err := service.GetPlaylists(ctx, []string{"pl1", "pl3", "unknown"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil {
+			t.Fatal("GetPlaylists returned nil")
+		}
+		var ids []string
+		for _, p := range *res {
+			ids = append(ids, p.Id)
+		}
+		expectSet(t, ids, "pl1", "pl3")
+	})
+	t.Run("GetVideo", func(t *testing.T) {
+		res, err := service.GetVideo(ctx, "vid3", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := videos["vid3"]
+		if res == nil || res.Id != expected.Id || res.Title != expected.Title || res.ChannelId != expected.ChannelId || res.CategoryId != expected.CategoryId || res.Duration != expected.Duration || !sameTime(res.PublishedAt, expected.PublishedAt) {
+			t.Fatalf("GetVideo(vid3) = %+v", res)
+		}
+		if !sameCount(res.ViewCount, expected.ViewCount) || !sameCount(res.LikeCount, expected.LikeCount) || !sameCount(res.CommentCount, expected.CommentCount) {
+			t.Errorf("GetVideo(vid3) statistics = %v, %v, %v", res.ViewCount, res.LikeCount, res.CommentCount)
+		}
+		expectSet(t, res.Tags, expected.Tags...)
+		expectSet(t, res.BlockedRegions, expected.BlockedRegions...)
+		missing, err := service.GetVideo(ctx, "unknown", nil)
+		if err != nil || missing != nil {
+			t.Errorf("GetVideo(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+	t.Run("GetVideos", func(t *testing.T) {
+		res, err := service.GetVideos(ctx, []string{"vid1", "vid7", "unknown"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil {
+			t.Fatal("GetVideos returned nil")
+		}
+		expectSet(t, videoIds(*res), "vid1", "vid7")
+		empty, err := service.GetVideos(ctx, []string{}, nil)
+		if err != nil || empty == nil || len(*empty) != 0 {
+			t.Errorf("GetVideos([]) = %v, %v; want empty list", empty, err)
+		}
+	})
+	t.Run("GetChannelPlaylists", func(t *testing.T) {
+		ids := collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetChannelPlaylists(ctx, "chan1", 1, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return playlistIds(res.List), res.NextPageToken, nil
+		})
+		expectOrder(t, ids, "pl2", "pl1")
+		res, err := service.GetChannelPlaylists(ctx, "unknown", 10, "", nil)
+		if err != nil || res == nil || len(res.List) != 0 {
+			t.Errorf("GetChannelPlaylists(unknown) = %+v, %v; want empty list", res, err)
+		}
+	})
+	t.Run("GetChannelVideos", func(t *testing.T) {
+		ids := collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetChannelVideos(ctx, "chan1", "", 2, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		expectOrder(t, ids, "vid5", "vid4", "vid3", "vid2", "vid1")
+		res, err := service.GetChannelVideos(ctx, "unknown", "", 10, "", nil)
+		if err != nil || res == nil || len(res.List) != 0 {
+			t.Errorf("GetChannelVideos(unknown) = %+v, %v; want empty list", res, err)
+		}
+		if _, err := service.GetChannelVideos(ctx, "chan1", "", 2, "not a token", nil); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("GetChannelVideos(malformed token) = %v; want cursor.ErrInvalid", err)
+		}
+		first, err := service.GetChannelVideos(ctx, "chan1", "", 2, "", nil)
+		if err != nil || first == nil || len(first.NextPageToken) == 0 {
+			t.Fatalf("GetChannelVideos = %+v, %v", first, err)
+		}
+		token := []byte(first.NextPageToken)
+		token[0] ^= 1
+		if _, err := service.GetChannelVideos(ctx, "chan1", "", 2, string(token), nil); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("GetChannelVideos(tampered token) = %v; want cursor.ErrInvalid", err)
+		}
+		if _, err := service.SearchVideos(ctx, video.ItemSM{ChannelId: "chan1", Sort: "title"}, 2, first.NextPageToken, nil); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("SearchVideos(token of another sort) = %v; want cursor.ErrInvalid", err)
+		}
+	})
+	t.Run("GetChannelVideos by region", func(t *testing.T) {
+		region := func(regionCode string) []string {
+			return collect(t, func(next string) ([]string, string, error) {
+				res, err := service.GetChannelVideos(ctx, "chan1", regionCode, 2, next, nil)
+				if err != nil || res == nil {
+					return nil, "", err
+				}
+				return videoIds(res.List), res.NextPageToken, nil
+			})
+		}
+		// vid1 is allowed in DE, which wins over blocking it there, vid3 is blocked in DE and vid4 only allowed in VN
+		expectOrder(t, region("DE"), "vid5", "vid2", "vid1")
+		expectOrder(t, region("VN"), "vid5", "vid4", "vid3", "vid2")
+		expectOrder(t, region("FR"), "vid5", "vid3", "vid2")
+	})
+	t.Run("GetPlaylistVideos", func(t *testing.T) {
+		ids := collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetPlaylistVideos(ctx, "pl1", "", 2, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		expectOrder(t, ids, "vid1", "vid2", "vid3")
+		res, err := service.GetPlaylistVideos(ctx, "pl1", "DE", 10, "", nil)
+		if err != nil || res == nil {
+			t.Fatalf("GetPlaylistVideos(pl1, DE) = %+v, %v", res, err)
+		}
+		expectOrder(t, videoIds(res.List), "vid1", "vid2")
+		missing, err := service.GetPlaylistVideos(ctx, "unknown", "", 10, "", nil)
+		if err != nil || missing != nil {
+			t.Errorf("GetPlaylistVideos(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+	t.Run("SearchChannel", func(t *testing.T) {
+		res, err := service.SearchChannel(ctx, video.ChannelSM{Q: "cooking"}, 10, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, c := range res.List {
+			ids = append(ids, c.Id)
+		}
+		expectSet(t, ids, "chan2")
+	})
+	t.Run("SearchPlaylists", func(t *testing.T) {
+		res, err := service.SearchPlaylists(ctx, video.PlaylistSM{ChannelId: "chan1"}, 10, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectSet(t, playlistIds(res.List), "pl1", "pl2")
+		res, err = service.SearchPlaylists(ctx, video.PlaylistSM{Q: "soup"}, 10, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectSet(t, playlistIds(res.List), "pl3")
+	})
+	t.Run("SearchVideos", func(t *testing.T) {
+		cases := []struct {
+			sm       video.ItemSM
+			expected []string
+		}{
+			{video.ItemSM{Q: "soup"}, []string{"vid6", "vid7"}},
+			{video.ItemSM{ChannelId: "chan2"}, []string{"vid6", "vid7"}},
+			{video.ItemSM{ChannelId: "chan1", PublishedAfter: data.Videos[2].PublishedAt}, []string{"vid3", "vid4", "vid5"}},
+			{video.ItemSM{ChannelId: "chan1", PublishedBefore: data.Videos[1].PublishedAt}, []string{"vid1", "vid2"}},
+			{video.ItemSM{Duration: "short"}, []string{"vid1", "vid5"}},
+			{video.ItemSM{Duration: "medium"}, []string{"vid2", "vid4", "vid6", "vid7"}},
+			{video.ItemSM{Duration: "long"}, []string{"vid3"}},
+			{video.ItemSM{CategoryId: "26"}, []string{"vid6", "vid7"}},
+			{video.ItemSM{Caption: "closedCaption"}, []string{"vid1", "vid3", "vid6"}},
+			{video.ItemSM{ChannelId: "chan1", Caption: "none"}, []string{"vid2", "vid4", "vid5"}},
+			{video.ItemSM{Definition: "high"}, []string{"vid1", "vid3", "vid4"}},
+			{video.ItemSM{ChannelId: "chan1", Definition: "standard"}, []string{"vid2", "vid5"}},
+			{video.ItemSM{Dimension: "3d"}, []string{"vid3"}},
+			{video.ItemSM{EventType: "live"}, []string{"vid3"}},
+			{video.ItemSM{EventType: "upcoming"}, []string{"vid4"}},
+			{video.ItemSM{ChannelId: "chan1", EventType: "completed"}, []string{"vid1", "vid2", "vid5"}},
+			{video.ItemSM{License: "creativeCommon"}, []string{"vid1", "vid6"}},
+			{video.ItemSM{Embeddable: "true"}, []string{"vid1", "vid2", "vid4", "vid6"}},
+			{video.ItemSM{ChannelId: "chan1", SafeSearch: "strict"}, []string{"vid1", "vid2", "vid3", "vid5"}},
+			{video.ItemSM{TopicId: "/m/07c1v"}, []string{"vid1", "vid3"}},
+			{video.ItemSM{VideoType: "movie"}, []string{"vid5"}},
+			{video.ItemSM{VideoType: "episode"}, []string{"vid4"}},
+			// vid3 and vid5 declare no language
+			{video.ItemSM{ChannelId: "chan1", RelevanceLanguage: "en"}, []string{"vid1", "vid2", "vid3", "vid5"}},
+			{video.ItemSM{RelatedToVideoId: "vid1"}, []string{"vid2", "vid3"}},
+			{video.ItemSM{RelatedToVideoId: "vid5"}, nil},
+		}
+		for _, c := range cases {
+			ids := collect(t, func(next string) ([]string, string, error) {
+				res, err := service.SearchVideos(ctx, c.sm, 2, next, nil)
+				if err != nil || res == nil {
+					return nil, "", err
+				}
+				return videoIds(res.List), res.NextPageToken, nil
+			})
+			if !equalSet(ids, c.expected) {
+				t.Errorf("SearchVideos(%+v) = %v; want %v", c.sm, ids, c.expected)
+			}
+		}
+	})
+	t.Run("SearchVideos sort", func(t *testing.T) {
+		cases := []struct {
+			sort     string
+			expected []string
+		}{
+			{"title", []string{"vid3", "vid4", "vid5", "vid1", "vid2"}},
+			{"rating", []string{"vid3", "vid1", "vid2", "vid4", "vid5"}},
+			{"+date", []string{"vid1", "vid2", "vid3", "vid4", "vid5"}},
+			{"duration", []string{"vid3", "vid4", "vid2", "vid5", "vid1"}},
+			{"-viewCount,title", []string{"vid3", "vid1", "vid2", "vid4", "vid5"}},
+		}
+		for _, c := range cases {
+			ids := collect(t, func(next string) ([]string, string, error) {
+				res, err := service.SearchVideos(ctx, video.ItemSM{ChannelId: "chan1", Sort: c.sort}, 2, next, nil)
+				if err != nil || res == nil {
+					return nil, "", err
+				}
+				return videoIds(res.List), res.NextPageToken, nil
+			})
+			expectOrder(t, ids, c.expected...)
+		}
+		for _, sort := range []string{"bogus", "id", "title,title", "date,relevance"} {
+			if _, err := service.SearchVideos(ctx, video.ItemSM{Q: "gopher", Sort: sort}, 2, "", nil); !errors.Is(err, video.ErrInvalidSort) {
+				t.Errorf("SearchVideos(sort %q) error = %v; want video.ErrInvalidSort", sort, err)
+			}
+		}
+		if _, err := service.SearchVideos(ctx, video.ItemSM{}, 2, "", []string{"title", "title from video; --"}); !errors.Is(err, video.ErrInvalidField) {
+			t.Errorf("SearchVideos(bad field) error = %v; want video.ErrInvalidField", err)
+		}
+		if _, err := service.GetVideo(ctx, "vid1", []string{"bogus"}); !errors.Is(err, video.ErrInvalidField) {
+			t.Errorf("GetVideo(bad field) error = %v; want video.ErrInvalidField", err)
+		}
+		res, err := service.SearchVideos(ctx, video.ItemSM{Q: "gopher's"}, 2, "", nil)
+		if err != nil || res == nil || len(res.List) != 0 {
+			t.Errorf("SearchVideos(q with a quote) = %+v, %v; want empty list", res, err)
+		}
+	})
+	t.Run("Search", func(t *testing.T) {
+		search := func(t *testing.T, sm video.ItemSM) []string {
+			return collect(t, func(next string) ([]string, string, error) {
+				res, err := service.Search(ctx, sm, 2, next, nil)
+				if err != nil || res == nil {
+					return nil, "", err
+				}
+				if len(res.List) > 2 {
+					t.Errorf("Search(%+v) returned %d results; want at most 2", sm, len(res.List))
+				}
+				for _, r := range res.List {
+					if (r.Kind == video.KindChannel) != (r.Channel != nil) || (r.Kind == video.KindPlaylist) != (r.Playlist != nil) || (r.Kind == video.KindVideo) != (r.Video != nil) {
+						t.Errorf("Search(%+v) result %+v does not hold its kind", sm, r)
+					}
+				}
+				return searchIds(res.List), res.NextPageToken, nil
+			})
+		}
+		expectOrder(t, search(t, video.ItemSM{}), "vid7", "vid6", "vid5", "vid4", "vid3", "vid2", "vid1", "pl3", "pl2", "pl1", "chan2", "chan1")
+		expectOrder(t, search(t, video.ItemSM{Sort: "title"}), "chan2", "pl2", "pl1", "chan1", "vid3", "vid4", "vid5", "vid1", "vid2", "vid7", "pl3", "vid6")
+		expectSet(t, search(t, video.ItemSM{Q: "soup"}), "pl3", "vid6", "vid7")
+		expectOrder(t, search(t, video.ItemSM{Kind: "playlist,channel"}), "pl3", "pl2", "pl1", "chan2", "chan1")
+		expectOrder(t, search(t, video.ItemSM{ChannelId: "chan2"}), "vid7", "vid6", "pl3", "chan2")
+		// duration is a filter only videos have
+		expectOrder(t, search(t, video.ItemSM{Duration: "long"}), "vid3")
+		expectOrder(t, search(t, video.ItemSM{Kind: "channel", Duration: "long"}))
+		if _, err := service.Search(ctx, video.ItemSM{Kind: "movie"}, 2, "", nil); !errors.Is(err, video.ErrInvalidKind) {
+			t.Errorf("Search(kind movie) error = %v; want video.ErrInvalidKind", err)
+		}
+		if _, err := service.Search(ctx, video.ItemSM{Sort: "viewCount"}, 2, "", nil); !errors.Is(err, video.ErrInvalidSort) {
+			t.Errorf("Search(sort viewCount) error = %v; want video.ErrInvalidSort", err)
+		}
+		res, err := service.Search(ctx, video.ItemSM{Q: "soup", Kind: "playlist"}, 2, "", []string{"title", "duration"})
+		if err != nil || res == nil || len(res.List) != 1 || res.List[0].Playlist == nil || res.List[0].Playlist.Title != "soups" {
+			t.Errorf("Search(soup playlists) = %+v, %v; want pl3 with its title", res, err)
+		}
+	})
+	t.Run("GetRelatedVideos", func(t *testing.T) {
+		ids := collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetRelatedVideos(ctx, "vid1", 1, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		// by the signals each shares with vid1, then the newer first
+		expectOrder(t, ids, "vid2", "vid3", "vid5", "vid4")
+		// vid5 has no tags, so the videos of its channel sharing a title term are related, the newest first
+		res, err := service.GetRelatedVideos(ctx, "vid5", 10, "", []string{"title"})
+		if err != nil || res == nil {
+			t.Fatalf("GetRelatedVideos(vid5) = %+v, %v", res, err)
+		}
+		expectOrder(t, videoIds(res.List), "vid4", "vid3", "vid2", "vid1")
+		if len(res.List) > 0 && (res.List[0].Title != "gopher generics" || res.List[0].Tags != nil) {
+			t.Errorf("GetRelatedVideos(vid5) first = %+v; want vid4 with only its title", res.List[0])
+		}
+		if _, err := service.GetRelatedVideos(ctx, "vid1", 1, "bogus", nil); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("GetRelatedVideos(bogus token) error = %v; want cursor.ErrInvalid", err)
+		}
+		missing, err := service.GetRelatedVideos(ctx, "unknown", 10, "", nil)
+		if err != nil || missing != nil {
+			t.Errorf("GetRelatedVideos(unknown) = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+	t.Run("GetPopularVideos", func(t *testing.T) {
+		ids := collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetPopularVideos(ctx, "", "26", 1, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		expectOrder(t, ids, "vid6", "vid7")
+		ids = collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetPopularVideos(ctx, "", "", 3, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		// Most viewed first; vid5 has no statistics and comes last.
+		expectOrder(t, ids, "vid3", "vid1", "vid2", "vid6", "vid4", "vid7", "vid5")
+		ids = collect(t, func(next string) ([]string, string, error) {
+			res, err := service.GetPopularVideos(ctx, "DE", "", 3, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})
+		expectOrder(t, ids, "vid1", "vid2", "vid6", "vid7", "vid5")
+	})
+	// Runs last because it writes a video.
+	t.Run("GetChannelVideos while a sync adds videos", func(t *testing.T) {
+		first, err := service.GetChannelVideos(ctx, "chan1", "", 2, "", nil)
+		if err != nil || first == nil {
+			t.Fatalf("GetChannelVideos = %+v, %v", first, err)
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		if _, err := repository.SaveVideos(ctx, []video.Video{{Id: "vid9", ChannelId: "chan1", ChannelTitle: "Gopher Channel", CategoryId: "28", Title: "newest upload", PublishedAt: &now}}); err != nil {
+			t.Fatalf("save video: %v", err)
+		}
+		ids := videoIds(first.List)
+		ids = append(ids, collect(t, func(next string) ([]string, string, error) {
+			if len(next) == 0 {
+				next = first.NextPageToken
+			}
+			res, err := service.GetChannelVideos(ctx, "chan1", "", 2, next, nil)
+			if err != nil || res == nil {
+				return nil, "", err
+			}
+			return videoIds(res.List), res.NextPageToken, nil
+		})...)
+		expectOrder(t, ids, "vid5", "vid4", "vid3", "vid2", "vid1")
+	})
+}
+
+func collect(t *testing.T, page func(next string) ([]string, string, error)) []string {
+	t.Helper()
+	var ids []string
+	next := ""
+	for i := 0; i < 100; i++ {
+		list, token, err := page(next)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		ids = append(ids, list...)
+		if len(token) == 0 || len(list) == 0 {
+			return ids
+		}
+		next = token
+	}
+	t.Fatal("paging did not terminate")
+	return nil
+}
+
+func expectOrder(t *testing.T, actual []string, expected ...string) {
+	t.Helper()
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("got %v; want %v", actual, expected)
+	}
+}
+
+func expectSet(t *testing.T, actual []string, expected ...string) {
+	t.Helper()
+	if !equalSet(actual, expected) {
+		t.Errorf("got %v; want %v in any order", actual, expected)
+	}
+}
+
+func equalSet(actual []string, expected []string) bool {
+	a := append([]string{}, actual...)
+	b := append([]string{}, expected...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+func sameTime(t1 *time.Time, t2 *time.Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(*t2)
+}
+
+func sameCount(c1 *int64, c2 *int64) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	}
+	return *c1 == *c2
+}
+
+func videoIds(videos []video.Video) []string {
+	ids := make([]string, 0, len(videos))
+	for _, v := range videos {
+		ids = append(ids, v.Id)
+	}
+	return ids
+}
+
+func searchIds(results []video.SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Id()
+	}
+	return ids
+}
+
+func playlistIds(playlists []video.Playlist) []string {
+	ids := make([]string, 0, len(playlists))
+	for _, p := range playlists {
+		ids = append(ids, p.Id)
+	}
+	return ids
+}
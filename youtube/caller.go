@@ -0,0 +1,213 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseUrl = "https://www.googleapis.com/youtube/v3"
+	DefaultRetries = 3
+	DefaultBackoff = 500 * time.Millisecond
+)
+
+// Caller sends requests to the YouTube Data API. Client and BaseUrl can be replaced, for example to point at a local server in tests.
+type Caller struct {
+	Key     string
+	Keys    *KeyPool
+	Quota   *QuotaLedger
+	Client  *http.Client
+	BaseUrl string
+	Retries int
+	Backoff time.Duration
+}
+
+func (c *Caller) Get(ctx context.Context, endpoint string, query url.Values, result interface{}) error {
+	attempts := 1
+	if c.Keys != nil && c.Keys.Size() > 1 {
+		attempts = c.Keys.Size()
+	}
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key, er0 := c.acquire(endpoint)
+		if er0 != nil {
+			return er0
+		}
+		body, statusCode, er1 := c.send(ctx, endpoint, query, key)
+		if er1 != nil {
+			return er1
+		}
+		if statusCode >= 200 && statusCode < 300 {
+			return json.Unmarshal(body, result)
+		}
+		var e ErrorResponse
+		json.Unmarshal(body, &e)
+		apiErr := newApiError(endpoint, statusCode, e.Error)
+		if reason := benchReason(e.Error); c.Keys != nil && len(reason) > 0 {
+			c.Keys.Bench(key, reason)
+			c.Keys.Refund(key, endpoint)
+			if c.Quota != nil {
+				c.Quota.Refund(endpoint)
+			}
+			lastErr = apiErr
+			continue
+		}
+		if isQuotaReason(apiErr.Reason) {
+			return &ErrQuotaExceeded{Endpoint: endpoint, Cost: QuotaCosts[endpoint], ResetAt: NextPacificMidnight(time.Now())}
+		}
+		return apiErr
+	}
+	if lastErr != nil {
+		if e, ok := lastErr.(*ApiError); ok && isQuotaReason(e.Reason) {
+			return &ErrQuotaExceeded{Endpoint: endpoint, Cost: QuotaCosts[endpoint], ResetAt: NextPacificMidnight(time.Now())}
+		}
+	}
+	return lastErr
+}
+
+// send performs the request, retrying 5xx, 429 and rate limit responses with jittered exponential backoff.
+// The first attempt is charged by acquire, each retry is charged again as YouTube counts it.
+func (c *Caller) send(ctx context.Context, endpoint string, query url.Values, key string) ([]byte, int, error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("key", key)
+	baseUrl := c.BaseUrl
+	if len(baseUrl) == 0 {
+		baseUrl = DefaultBaseUrl
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retries := c.Retries
+	if retries <= 0 {
+		retries = DefaultRetries
+	}
+	u := fmt.Sprintf("%s/%s?%s", strings.TrimSuffix(baseUrl, "/"), endpoint, q.Encode())
+	var body []byte
+	var statusCode int
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, 0, ctx.Err()
+			case <-timer.C:
+			}
+			if er0 := c.charge(endpoint, key); er0 != nil {
+				return nil, 0, er0
+			}
+		}
+		body, statusCode, err = get(ctx, client, u)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, 0, ctx.Err()
+			}
+			continue
+		}
+		if statusCode >= 200 && statusCode < 300 {
+			return body, statusCode, nil
+		}
+		var e ErrorResponse
+		json.Unmarshal(body, &e)
+		reason := ""
+		if len(e.Error.Errors) > 0 {
+			reason = e.Error.Errors[0].Reason
+		}
+		if !isRetryable(statusCode, reason) {
+			return body, statusCode, nil
+		}
+	}
+	return body, statusCode, err
+}
+
+func (c *Caller) backoff(attempt int) time.Duration {
+	base := c.Backoff
+	if base <= 0 {
+		base = DefaultBackoff
+	}
+	d := base * time.Duration(1<<uint(attempt-1))
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+func get(ctx context.Context, client *http.Client, u string) ([]byte, int, error) {
+	req, er0 := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if er0 != nil {
+		return nil, 0, er0
+	}
+	resp, er1 := client.Do(req)
+	if er1 != nil {
+		return nil, 0, er1
+	}
+	defer resp.Body.Close()
+	body, er2 := ioutil.ReadAll(resp.Body)
+	if er2 != nil {
+		return nil, 0, er2
+	}
+	return body, resp.StatusCode, nil
+}
+
+// acquire charges the shared quota only once a key is obtained, so a call rejected for lack of a key costs nothing.
+func (c *Caller) acquire(endpoint string) (string, error) {
+	key := c.Key
+	if c.Keys != nil {
+		k, err := c.Keys.Acquire(endpoint)
+		if err != nil {
+			return "", err
+		}
+		key = k
+	}
+	if c.Quota != nil {
+		if err := c.Quota.Charge(endpoint); err != nil {
+			if c.Keys != nil {
+				c.Keys.Refund(key, endpoint)
+			}
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+func (c *Caller) charge(endpoint string, key string) error {
+	if c.Keys != nil {
+		if err := c.Keys.Charge(key, endpoint); err != nil {
+			return err
+		}
+	}
+	if c.Quota != nil {
+		if err := c.Quota.Charge(endpoint); err != nil {
+			if c.Keys != nil {
+				c.Keys.Refund(key, endpoint)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func benchReason(e ErrorBody) string {
+	for _, v := range e.Errors {
+		switch v.Reason {
+		case "quotaExceeded", "dailyLimitExceeded", "keyInvalid", "keyExpired":
+			return v.Reason
+		}
+	}
+	if strings.Contains(e.Message, "API key not valid") || strings.Contains(e.Message, "API key expired") {
+		return "keyInvalid"
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallerQuota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") == "exhausted" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"message":"quota","errors":[{"reason":"quotaExceeded"}]}}`))
+			return
+		}
+		if r.URL.Query().Get("key") == "unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"backend error","errors":[{"reason":"backendError"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+	tests := []struct {
+		name   string
+		keys   []string
+		budget int
+		err    func(err error) bool
+		used   int
+		units  []int
+	}{
+		{name: "no keys", err: func(err error) bool { return errors.Is(err, ErrNoKeys) }},
+		{name: "key", keys: []string{"key1"}, budget: 10, used: 1, units: []int{1}},
+		{name: "rotated", keys: []string{"exhausted", "key2"}, budget: 10, used: 1, units: []int{0, 1}},
+		{name: "retried", keys: []string{"unavailable"}, budget: 10, err: func(err error) bool { return err != nil }, used: 2, units: []int{2}},
+		{name: "shared quota exceeded", keys: []string{"key1"}, err: IsQuotaExceeded, units: []int{0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			quota := NewQuotaLedger(10)
+			quota.Budget = tc.budget
+			caller := Caller{Keys: NewKeyPool(10, tc.keys...), Quota: quota, BaseUrl: server.URL, Retries: 1, Backoff: time.Millisecond}
+			var res map[string]interface{}
+			err := caller.Get(context.Background(), "videos", nil, &res)
+			if tc.err == nil && err != nil || tc.err != nil && !tc.err(err) {
+				t.Fatalf("err = %v", err)
+			}
+			if quota.Used() != tc.used {
+				t.Errorf("shared quota used %d; want %d", quota.Used(), tc.used)
+			}
+			for i, s := range caller.Keys.Stats() {
+				if s.Units != tc.units[i] {
+					t.Errorf("key %d used %d units; want %d", i, s.Units, tc.units[i])
+				}
+			}
+		})
+	}
+}
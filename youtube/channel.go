@@ -15,11 +15,12 @@ type PageInfo struct {
 }
 
 type ItemsChannel struct {
-	Kind           string          `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind,omitempty"`
-	Etag           string          `mapstructure:"etag" json:"etag,omitempty" gorm:"column:etag" bson:"etag,omitempty" dynamodbav:"etag,omitempty" firestore:"etag,omitempty"`
-	Id             string          `mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
-	Snippet        *SnippetChannel `mapstructure:"snippet" json:"snippet,omitempty" gorm:"column:snippet" bson:"snippet,omitempty" dynamodbav:"snippet,omitempty" firestore:"snippet,omitempty"`
-	ContentDetails *ContentDetails `mapstructure:"contentDetails" json:"contentDetails,omitempty" gorm:"column:contentDetails" bson:"contentDetails,omitempty" dynamodbav:"contentDetails,omitempty" firestore:"contentDetails,omitempty"`
+	Kind           string             `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind,omitempty"`
+	Etag           string             `mapstructure:"etag" json:"etag,omitempty" gorm:"column:etag" bson:"etag,omitempty" dynamodbav:"etag,omitempty" firestore:"etag,omitempty"`
+	Id             string             `mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Snippet        *SnippetChannel    `mapstructure:"snippet" json:"snippet,omitempty" gorm:"column:snippet" bson:"snippet,omitempty" dynamodbav:"snippet,omitempty" firestore:"snippet,omitempty"`
+	ContentDetails *ContentDetails    `mapstructure:"contentDetails" json:"contentDetails,omitempty" gorm:"column:contentDetails" bson:"contentDetails,omitempty" dynamodbav:"contentDetails,omitempty" firestore:"contentDetails,omitempty"`
+	Statistics     *StatisticsChannel `mapstructure:"statistics" json:"statistics,omitempty" gorm:"column:statistics" bson:"statistics,omitempty" dynamodbav:"statistics,omitempty" firestore:"statistics,omitempty"`
 }
 
 type SnippetChannel struct {
@@ -58,3 +59,10 @@ type RelatedPlaylists struct {
 	Favorites string `mapstructure:"favorites" json:"favorites,omitempty" gorm:"column:favorites" bson:"favorites,omitempty" dynamodbav:"favorites,omitempty" firestore:"favorites,omitempty"`
 	Uploads   string `mapstructure:"uploads" json:"uploads,omitempty" gorm:"column:uploads" bson:"uploads,omitempty" dynamodbav:"uploads,omitempty" firestore:"uploads,omitempty"`
 }
+
+type StatisticsChannel struct {
+	ViewCount             string `mapstructure:"viewCount" json:"viewCount,omitempty" gorm:"column:viewCount" bson:"viewCount,omitempty" dynamodbav:"viewCount,omitempty" firestore:"viewCount,omitempty"`
+	SubscriberCount       string `mapstructure:"subscriberCount" json:"subscriberCount,omitempty" gorm:"column:subscriberCount" bson:"subscriberCount,omitempty" dynamodbav:"subscriberCount,omitempty" firestore:"subscriberCount,omitempty"`
+	HiddenSubscriberCount bool   `mapstructure:"hiddenSubscriberCount" json:"hiddenSubscriberCount,omitempty" gorm:"column:hiddenSubscriberCount" bson:"hiddenSubscriberCount,omitempty" dynamodbav:"hiddenSubscriberCount,omitempty" firestore:"hiddenSubscriberCount,omitempty"`
+	VideoCount            string `mapstructure:"videoCount" json:"videoCount,omitempty" gorm:"column:videoCount" bson:"videoCount,omitempty" dynamodbav:"videoCount,omitempty" firestore:"videoCount,omitempty"`
+}
@@ -1,31 +1,43 @@
 package youtube
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"math"
-	"net/http"
+	"context"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/core-go/video"
 )
 
 type YoutubeSyncClient struct {
-	Key string
+	Caller
 }
 
-func NewYoutubeSyncClient(key string) *YoutubeSyncClient {
-	return &YoutubeSyncClient{Key: key}
+func NewYoutubeSyncClient(key string, options ...*QuotaLedger) *YoutubeSyncClient {
+	var quota *QuotaLedger
+	if len(options) > 0 {
+		quota = options[0]
+	}
+	return &YoutubeSyncClient{Caller{Key: key, Quota: quota}}
 }
 
-func (y *YoutubeSyncClient) GetChannel(id string) (*Channel, error) {
-	url := fmt.Sprintf(`https://www.googleapis.com/youtube/v3/channels?key=%s&id=%s&part=snippet,contentDetails`, y.Key, id)
-	result, err := convertChannel(url)
+func NewYoutubeSyncClientWithPool(keys *KeyPool, options ...*QuotaLedger) *YoutubeSyncClient {
+	var quota *QuotaLedger
+	if len(options) > 0 {
+		quota = options[0]
+	}
+	return &YoutubeSyncClient{Caller{Keys: keys, Quota: quota}}
+}
+
+func (y *YoutubeSyncClient) GetChannel(ctx context.Context, id string) (*Channel, error) {
+	result, err := y.getChannels(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if len(*result) == 0 {
+		return nil, notFound("channels", id)
+	}
 	var channel Channel
 	for _, v := range *result {
 		channel = v
@@ -33,126 +45,132 @@ func (y *YoutubeSyncClient) GetChannel(id string) (*Channel, error) {
 	return &channel, err
 }
 
-func (y *YoutubeSyncClient) GetChannels(ids []string) (*[]Channel, error) {
-	url := fmt.Sprintf(`https://www.googleapis.com/youtube/v3/channels?key=%s&id=%s&part=snippet,contentDetails`, y.Key, strings.Join(ids, ","))
-	result, err := convertChannel(url)
+func (y *YoutubeSyncClient) GetChannels(ctx context.Context, ids []string) (*[]Channel, error) {
+	return y.getChannels(ctx, strings.Join(ids, ","))
+}
+
+func (y *YoutubeSyncClient) getChannels(ctx context.Context, ids string) (*[]Channel, error) {
+	query := url.Values{}
+	query.Set("id", ids)
+	query.Set("part", "snippet,contentDetails,statistics")
+	var summary ChannelTubeResponse
+	err := y.Get(ctx, "channels", query, &summary)
 	if err != nil {
 		return nil, err
 	}
-	return result, err
+	return convertChannel(summary), nil
 }
 
-func (y *YoutubeSyncClient) GetPlaylist(id string) (*Playlist, error) {
-	url := fmt.Sprintf(`https://youtube.googleapis.com/youtube/v3/playlists?key=%s&id=%s&part=snippet,contentDetails`, y.Key, id)
-	result, err := convertPlaylist(url)
+func (y *YoutubeSyncClient) GetPlaylist(ctx context.Context, id string) (*Playlist, error) {
+	result, err := y.getPlaylists(ctx, id, "", 0, "")
 	if err != nil {
 		return nil, err
 	}
+	if len(result.List) == 0 {
+		return nil, notFound("playlists", id)
+	}
 	return &result.List[0], err
 }
 
-func (y *YoutubeSyncClient) GetPlaylists(ids []string) (*[]Playlist, error) {
-	url := fmt.Sprintf(`https://youtube.googleapis.com/youtube/v3/playlists?key=%s&id=%s&part=snippet,contentDetails`, y.Key, strings.Join(ids, ","))
-	result, err := convertPlaylist(url)
+func (y *YoutubeSyncClient) GetPlaylists(ctx context.Context, ids []string) (*[]Playlist, error) {
+	result, err := y.getPlaylists(ctx, strings.Join(ids, ","), "", 0, "")
 	if err != nil {
 		return nil, err
 	}
 	return &result.List, err
 }
 
-func (y *YoutubeSyncClient) GetChannelPlaylists(channelId string, max int16, nextPageToken string) (*ListResultPlaylist, error) {
+func (y *YoutubeSyncClient) GetChannelPlaylists(ctx context.Context, channelId string, max int16, nextPageToken string) (*ListResultPlaylist, error) {
 	var maxResults int16
-	var next string
 	if max > 0 {
 		maxResults = max
 	} else {
 		maxResults = 50
 	}
+	return y.getPlaylists(ctx, "", channelId, maxResults, nextPageToken)
+}
+
+func (y *YoutubeSyncClient) getPlaylists(ctx context.Context, ids string, channelId string, maxResults int16, nextPageToken string) (*ListResultPlaylist, error) {
+	query := url.Values{}
+	if len(ids) > 0 {
+		query.Set("id", ids)
+	}
+	if len(channelId) > 0 {
+		query.Set("channelId", channelId)
+		query.Set("maxResults", strconv.Itoa(int(maxResults)))
+	}
 	if nextPageToken != "" {
-		next = fmt.Sprintf(`&pageToken=%s`, nextPageToken)
-	} else {
-		next = ""
+		query.Set("pageToken", nextPageToken)
 	}
-	url := fmt.Sprintf(`https://youtube.googleapis.com/youtube/v3/playlists?key=%s&channelId=%s&maxResults=%d%s&part=snippet,contentDetails`, y.Key, channelId, maxResults, next)
-	result, err := convertPlaylist(url)
+	query.Set("part", "snippet,contentDetails")
+	var summary PlaylistTubeResponse
+	err := y.Get(ctx, "playlists", query, &summary)
 	if err != nil {
 		return nil, err
 	}
-	return result, err
+	return convertPlaylist(summary), nil
 }
 
-func (y *YoutubeSyncClient) GetPlaylistVideos(playlistId string, max int16, nextPageToken string) (*ListResultPlaylistVideo, error) {
+func (y *YoutubeSyncClient) GetPlaylistVideos(ctx context.Context, playlistId string, max int16, nextPageToken string) (*ListResultPlaylistVideo, error) {
 	var maxResults int16
-	var next string
 	if max > 0 {
 		maxResults = max
 	} else {
 		maxResults = 50
 	}
+	query := url.Values{}
+	query.Set("playlistId", playlistId)
+	query.Set("maxResults", strconv.Itoa(int(maxResults)))
 	if nextPageToken != "" {
-		next = fmt.Sprintf(`&pageToken=%s`, nextPageToken)
-	} else {
-		next = ""
+		query.Set("pageToken", nextPageToken)
 	}
-	url := fmt.Sprintf(`https://youtube.googleapis.com/youtube/v3/playlistItems?key=%s&playlistId=%s&maxResults=%d%s&part=snippet,contentDetails`, y.Key, playlistId, maxResults, next)
-	result, err := convertPlaylistVideo(url)
+	query.Set("part", "snippet,contentDetails,status")
+	var summary PlaylistVideoTubeResponse
+	err := y.Get(ctx, "playlistItems", query, &summary)
 	if err != nil {
 		return nil, err
 	}
-	return result, err
+	return convertPlaylistVideo(summary), nil
 }
 
-func (y *YoutubeSyncClient) GetVideos(ids []string) (*ListResultVideos, error) {
+func (y *YoutubeSyncClient) GetVideos(ctx context.Context, ids []string) (*ListResultVideos, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
-	url := fmt.Sprintf(`https://www.googleapis.com/youtube/v3/videos?key=%s&part=snippet,contentDetails&id=%s`, y.Key, strings.Join(ids, ","))
-	result, err := convertVideos(url)
+	query := url.Values{}
+	query.Set("part", "snippet,contentDetails,statistics,status,topicDetails,liveStreamingDetails")
+	query.Set("id", strings.Join(ids, ","))
+	var summary VideoTubeResponse
+	err := y.Get(ctx, "videos", query, &summary)
 	if err != nil {
 		return nil, err
 	}
-	return result, err
+	return convertVideos(summary)
 }
 
-func (y *YoutubeSyncClient) GetSubscriptions(channelId string, mine string, max int, nextPageToken string) (*ListResultChannel, error) {
+func (y *YoutubeSyncClient) GetSubscriptions(ctx context.Context, channelId string, mine string, max int, nextPageToken string) (*ListResultChannel, error) {
 	var maxResult int
-	var pageToken string
-	var mineStr string
-	var channel string
 	if max > 0 {
 		maxResult = max
 	} else {
 		maxResult = 50
 	}
-	if len(nextPageToken) > 0 {
-		pageToken = fmt.Sprintf(`&pageToken=%s`, nextPageToken)
-	} else {
-		pageToken = ""
-	}
+	query := url.Values{}
 	if len(mine) > 0 {
-		mineStr = fmt.Sprintf(`&mine=%s`, mine)
-	} else {
-		mineStr = ""
+		query.Set("mine", mine)
 	}
 	if len(channelId) > 0 {
-		channel = fmt.Sprintf(`&channelId=%s`, channelId)
-	} else {
-		channel = ""
+		query.Set("channelId", channelId)
 	}
-	url := fmt.Sprintf(`https://youtube.googleapis.com/youtube/v3/subscriptions?key=%s%s%s&maxResults=%d%s&part=snippet`, y.Key, mineStr, channel, maxResult, pageToken)
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
+	query.Set("maxResults", strconv.Itoa(maxResult))
+	if len(nextPageToken) > 0 {
+		query.Set("pageToken", nextPageToken)
 	}
+	query.Set("part", "snippet")
 	var summary SubcriptionTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
+	err := y.Get(ctx, "subscriptions", query, &summary)
+	if err != nil {
+		return nil, err
 	}
 	var channels ListResultChannel
 	channels.NextPageToken = summary.NextPageToken
@@ -170,21 +188,7 @@ func (y *YoutubeSyncClient) GetSubscriptions(channelId string, mine string, max
 	return &channels, nil
 }
 
-func convertChannel(url string) (*[]Channel, error) {
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
-	}
-	var summary ChannelTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
-	}
+func convertChannel(summary ChannelTubeResponse) *[]Channel {
 	channel := make([]Channel, len(summary.Items))
 	for i, v := range summary.Items {
 		channel[i].Id = v.Id
@@ -201,25 +205,18 @@ func convertChannel(url string) (*[]Channel, error) {
 		channel[i].Uploads = v.ContentDetails.RelatedPlaylists.Uploads
 		channel[i].Likes = v.ContentDetails.RelatedPlaylists.Likes
 		channel[i].Favorites = v.ContentDetails.RelatedPlaylists.Favorites
+		if v.Statistics != nil {
+			channel[i].ViewCount = parseCount(v.Statistics.ViewCount)
+			if !v.Statistics.HiddenSubscriberCount {
+				channel[i].SubscriberCount = parseCount(v.Statistics.SubscriberCount)
+			}
+			channel[i].VideoCount = parseCount(v.Statistics.VideoCount)
+		}
 	}
-	return &channel, nil
+	return &channel
 }
 
-func convertPlaylist(url string) (*ListResultPlaylist, error) {
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
-	}
-	var summary PlaylistTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
-	}
+func convertPlaylist(summary PlaylistTubeResponse) *ListResultPlaylist {
 	listResultPlaylist := ListResultPlaylist{}
 	listResultPlaylist.Total = summary.PageInfo.TotalResults
 	listResultPlaylist.Limit = summary.PageInfo.ResultsPerPage
@@ -244,24 +241,10 @@ func convertPlaylist(url string) (*ListResultPlaylist, error) {
 		playlist.MaxresThumbnail = &v.Snippet.Thumbnails.Maxres.Url
 		listResultPlaylist.List = append(listResultPlaylist.List, playlist)
 	}
-	return &listResultPlaylist, nil
+	return &listResultPlaylist
 }
 
-func convertPlaylistVideo(url string) (*ListResultPlaylistVideo, error) {
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
-	}
-	var summary PlaylistVideoTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
-	}
+func convertPlaylistVideo(summary PlaylistVideoTubeResponse) *ListResultPlaylistVideo {
 	listResultPlaylistVideo := ListResultPlaylistVideo{}
 	listResultPlaylistVideo.Total = summary.PageInfo.TotalResults
 	listResultPlaylistVideo.Limit = summary.PageInfo.ResultsPerPage
@@ -285,26 +268,15 @@ func convertPlaylistVideo(url string) (*ListResultPlaylistVideo, error) {
 		playlistVideo.HighThumbnail = &v.Snippet.Thumbnails.High.Url
 		playlistVideo.StandardThumbnail = &v.Snippet.Thumbnails.Standard.Url
 		playlistVideo.MaxresThumbnail = &v.Snippet.Thumbnails.Maxres.Url
+		if v.Status != nil {
+			playlistVideo.PrivacyStatus = v.Status.PrivacyStatus
+		}
 		listResultPlaylistVideo.List = append(listResultPlaylistVideo.List, playlistVideo)
 	}
-	return &listResultPlaylistVideo, nil
+	return &listResultPlaylistVideo
 }
 
-func convertVideos(url string) (*ListResultVideos, error) {
-	resp, er0 := http.Get(url)
-	if er0 != nil {
-		return nil, er0
-	}
-	var summary VideoTubeResponse
-	body, er1 := ioutil.ReadAll(resp.Body)
-	if er1 != nil {
-		return nil, er1
-	}
-	defer resp.Body.Close()
-	er2 := json.Unmarshal(body, &summary)
-	if er2 != nil {
-		return nil, er2
-	}
+func convertVideos(summary VideoTubeResponse) (*ListResultVideos, error) {
 	listResultVideos := ListResultVideos{}
 	listResultVideos.Total = summary.PageInfo.TotalResults
 	listResultVideos.Limit = summary.PageInfo.ResultsPerPage
@@ -324,11 +296,10 @@ func convertVideos(url string) (*ListResultVideos, error) {
 		video.LiveBroadcastContent = v.Snippet.LiveBroadcastContent
 		video.DefaultLanguage = v.Snippet.DefaultLanguage
 		video.DefaultAudioLanguage = v.Snippet.DefaultAudioLanguage
-		duration, err := calculateDuration(v.ContentDetails.Duration)
-		if err != nil {
-			return nil, err
+		// a duration YouTube gets wrong is left 0 rather than losing the page
+		if duration, err := ParseDuration(v.ContentDetails.Duration); err == nil {
+			video.Duration = int64(duration / time.Second)
 		}
-		video.Duration = int64(duration)
 		video.Dimension = v.ContentDetails.Dimension
 		if v.ContentDetails.Definition == "hd" {
 			video.Definition = 5
@@ -337,6 +308,7 @@ func convertVideos(url string) (*ListResultVideos, error) {
 		}
 		video.Caption = v.ContentDetails.Caption
 		video.LicensedContent = &v.ContentDetails.LicensedContent
+		video.YtRating = v.ContentDetails.ContentRating.YtRating
 		if v.ContentDetails.Projection == "rectangular" {
 			video.Projection = ""
 		} else {
@@ -352,85 +324,57 @@ func convertVideos(url string) (*ListResultVideos, error) {
 		if len(v.ContentDetails.RegionRestriction.Blocked) > 0 {
 			video.BlockedRegions = v.ContentDetails.RegionRestriction.Blocked
 		}
+		if v.Status != nil {
+			video.License = v.Status.License
+			embeddable := v.Status.Embeddable
+			video.Embeddable = &embeddable
+			if v.Status.PrivacyStatus == "private" {
+				video.Status = VideoPrivate
+			}
+		}
+		if v.TopicDetails != nil {
+			video.TopicIds = topicIds(v.TopicDetails)
+		}
+		if d := v.LiveStreamingDetails; d != nil {
+			video.ScheduledStartTime = d.ScheduledStartTime
+			video.ScheduledEndTime = d.ScheduledEndTime
+			video.ActualStartTime = d.ActualStartTime
+			video.ActualEndTime = d.ActualEndTime
+			video.LiveStatus = LiveStatus(video.LiveBroadcastContent, d.ActualStartTime, d.ActualEndTime)
+		} else {
+			video.LiveStatus = LiveStatus(video.LiveBroadcastContent, nil, nil)
+		}
+		if v.Statistics != nil {
+			video.ViewCount = parseCount(v.Statistics.ViewCount)
+			video.LikeCount = parseCount(v.Statistics.LikeCount)
+			video.CommentCount = parseCount(v.Statistics.CommentCount)
+		}
 		listResultVideos.List = append(listResultVideos.List, video)
 	}
 	return &listResultVideos, nil
 }
 
-func calculateDuration(d string) (float64, error) {
-	if d == "" {
-		return 0, nil
-	}
-	k := strings.Split(d, "M")
-	if len(k) < 2 {
-		g := strings.Split(d, "H")
-		if len(g) < 2 {
-			var a0 string
-			if len(d) > 0 {
-				a0 = d[2 : len(d)-1]
-			}
-			a1, er0 := strconv.ParseFloat(a0, 32)
-			if er0 != nil {
-				return -1, er0
-			}
-			if strings.Contains(d, "S") {
-				return a1, nil
-			} else {
-				return a1 * 3600, nil
-			}
-		} else {
-			var a0 string
-			if len(d) > 0 {
-				a0 = d[2 : len(d)-3]
-			}
-			a3, er0 := strconv.ParseFloat(a0[0:len(a0)-1], 32)
-			if er0 != nil {
-				return -1, er0
-			}
-			return a3 * 3600, nil
+// topicIds merges the relevant topics with the main ones, which YouTube still returns for some videos.
+func topicIds(details *TopicDetailsVideo) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range append(details.RelevantTopicIds, details.TopicIds...) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
 	}
-	var a string
-	if k[1] != "" {
-		a = k[1][0 : len(k[1])-1]
-	}
-	if len(a) == 0 {
-		a = "0"
-	}
+	return ids
+}
 
-	x := strings.Split(k[0], "H")
-	var b string
-	if len(x) == 1 && len(k[0]) > 0 {
-		b = k[0][2:len(k[0])]
-	} else {
-		b = x[1]
+// parseCount returns nil when YouTube omits a count, for example when the owner hides likes or disables comments.
+func parseCount(s string) *int64 {
+	if len(s) == 0 {
+		return nil
 	}
-	a1, er0 := strconv.ParseFloat(a, 32)
-	if er0 != nil {
-		return -1, er0
-	}
-	a2, er1 := strconv.ParseFloat(b, 32)
-	if er1 != nil {
-		return -1, er1
-	}
-	if !math.IsNaN(a1) && !math.IsNaN(a2) {
-		if len(x) == 1 {
-			return a2*60 + a1, nil
-		} else {
-			var c string
-			if len(x[0]) > 0 {
-				c = x[0][2:len(x[0])]
-			}
-			c1, er2 := strconv.ParseFloat(c, 32)
-			if er2 != nil {
-				return -1, er2
-			}
-			if !math.IsNaN(c1) {
-				return c1*3600 + a2*60 + a1, nil
-			} else {
-				return 0, nil
-			}
-		}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil
 	}
-	return 0, nil
+	return &n
 }
@@ -0,0 +1,108 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDuration = errors.New("youtube: invalid ISO 8601 duration")
+
+const day = 24 * time.Hour
+
+// A year and a month count as 365 and 30 days.
+var (
+	dateUnits = []durationUnit{{'Y', 365 * day}, {'M', 30 * day}, {'W', 7 * day}, {'D', day}}
+	timeUnits = []durationUnit{{'H', time.Hour}, {'M', time.Minute}, {'S', time.Second}}
+)
+
+type durationUnit struct {
+	designator byte
+	length     time.Duration
+}
+
+// ParseDuration parses an ISO 8601 duration such as "PT1H2M3S". An empty string is 0.
+func ParseDuration(s string) (time.Duration, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	if s[0] != 'P' || len(s) == 1 {
+		return 0, invalidDuration(s)
+	}
+	date, clock := s[1:], ""
+	if i := strings.IndexByte(date, 'T'); i >= 0 {
+		date, clock = date[:i], date[i+1:]
+		if len(clock) == 0 {
+			return 0, invalidDuration(s)
+		}
+	}
+	var total time.Duration
+	fraction := false
+	for _, part := range []struct {
+		text  string
+		units []durationUnit
+	}{{date, dateUnits}, {clock, timeUnits}} {
+		text, u := part.text, 0
+		for len(text) > 0 {
+			if fraction {
+				return 0, invalidDuration(s)
+			}
+			i := strings.IndexFunc(text, func(r rune) bool {
+				return (r < '0' || r > '9') && r != '.' && r != ','
+			})
+			if i <= 0 {
+				return 0, invalidDuration(s)
+			}
+			for u < len(part.units) && part.units[u].designator != text[i] {
+				u++
+			}
+			if u == len(part.units) {
+				return 0, invalidDuration(s)
+			}
+			d, frac, err := component(text[:i], part.units[u].length)
+			if err != nil || d > math.MaxInt64-total {
+				return 0, invalidDuration(s)
+			}
+			total += d
+			fraction = frac
+			text = text[i+1:]
+			u++
+		}
+	}
+	return total, nil
+}
+
+func component(number string, unit time.Duration) (time.Duration, bool, error) {
+	number = strings.Replace(number, ",", ".", 1)
+	whole, frac := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		whole, frac = number[:i], number[i+1:]
+		if len(whole) == 0 || len(frac) == 0 {
+			return 0, false, ErrInvalidDuration
+		}
+	}
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || n > int64(math.MaxInt64/unit) {
+		return 0, false, ErrInvalidDuration
+	}
+	d := time.Duration(n) * unit
+	if len(frac) == 0 {
+		return d, false, nil
+	}
+	f, err := strconv.ParseFloat("0."+frac, 64)
+	if err != nil {
+		return 0, false, ErrInvalidDuration
+	}
+	extra := time.Duration(math.Round(f * float64(unit)))
+	if d > math.MaxInt64-extra {
+		return 0, false, ErrInvalidDuration
+	}
+	return d + extra, true, nil
+}
+
+func invalidDuration(s string) error {
+	return fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+}
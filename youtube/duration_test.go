@@ -0,0 +1,89 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		input    string
+		expected time.Duration
+		invalid  bool
+	}{
+		{input: "", expected: 0},
+		{input: "P0D", expected: 0},
+		{input: "PT0S", expected: 0},
+		{input: "PT45S", expected: 45 * time.Second},
+		{input: "PT10M", expected: 10 * time.Minute},
+		{input: "PT4M13S", expected: 4*time.Minute + 13*time.Second},
+		{input: "PT2H", expected: 2 * time.Hour},
+		{input: "PT2H0M0S", expected: 2 * time.Hour},
+		{input: "PT1H5S", expected: time.Hour + 5*time.Second},
+		{input: "PT1H2M3S", expected: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: "PT24H0M1S", expected: day + time.Second},
+		{input: "PT90M", expected: 90 * time.Minute},
+		{input: "PT3600S", expected: time.Hour},
+		{input: "P1D", expected: day},
+		{input: "P1DT2H", expected: day + 2*time.Hour},
+		{input: "P1DT1S", expected: day + time.Second},
+		{input: "P2DT3H4M5S", expected: 2*day + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{input: "P2W", expected: 14 * day},
+		{input: "P1W1D", expected: 8 * day},
+		{input: "P1M", expected: 30 * day},
+		{input: "PT1M", expected: time.Minute},
+		{input: "P1MT1M", expected: 30*day + time.Minute},
+		{input: "P1Y", expected: 365 * day},
+		{input: "P1Y2M3DT4H5M6S", expected: 365*day + 60*day + 3*day + 4*time.Hour + 5*time.Minute + 6*time.Second},
+		{input: "PT0.5S", expected: 500 * time.Millisecond},
+		{input: "PT1,5S", expected: 1500 * time.Millisecond},
+		{input: "PT1.25M", expected: 75 * time.Second},
+		{input: "PT1H0.5M", expected: time.Hour + 30*time.Second},
+		{input: "P0.5D", expected: 12 * time.Hour},
+		{input: "PT0.000000001S", expected: time.Nanosecond},
+		{input: "P", invalid: true},
+		{input: "PT", invalid: true},
+		{input: "P1DT", invalid: true},
+		{input: "T1H", invalid: true},
+		{input: "1H", invalid: true},
+		{input: "pt1h", invalid: true},
+		{input: "PT1H ", invalid: true},
+		{input: " PT1H", invalid: true},
+		{input: "PT-1S", invalid: true},
+		{input: "-PT1S", invalid: true},
+		{input: "PT1S2M", invalid: true},
+		{input: "PT1M1M", invalid: true},
+		{input: "P1D1Y", invalid: true},
+		{input: "PTH", invalid: true},
+		{input: "PT1", invalid: true},
+		{input: "PT1X", invalid: true},
+		{input: "P1H", invalid: true},
+		{input: "PT1D", invalid: true},
+		{input: "PT1.5M2S", invalid: true},
+		{input: "PT.5S", invalid: true},
+		{input: "PT5.S", invalid: true},
+		{input: "PT1.2.3S", invalid: true},
+		{input: "PT1TS", invalid: true},
+		{input: "P1DT1HT1M", invalid: true},
+		{input: "PT9999999999999999999S", invalid: true},
+		{input: "P999999Y", invalid: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := ParseDuration(tc.input)
+			if tc.invalid {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNotFound   = errors.New("youtube: not found")
+	ErrForbidden  = errors.New("youtube: forbidden")
+	ErrBadRequest = errors.New("youtube: bad request")
+	ErrBackend    = errors.New("youtube: backend error")
+)
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Status  string      `json:"status,omitempty"`
+	Errors  []ErrorItem `json:"errors,omitempty"`
+}
+
+type ErrorItem struct {
+	Domain  string `json:"domain,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type ApiError struct {
+	Endpoint   string
+	StatusCode int
+	Reason     string
+	Message    string
+}
+
+func (e *ApiError) Error() string {
+	if len(e.Reason) > 0 {
+		return fmt.Sprintf("youtube: %s returned %d (%s): %s", e.Endpoint, e.StatusCode, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("youtube: %s returned %d: %s", e.Endpoint, e.StatusCode, e.Message)
+}
+
+func (e *ApiError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusUnauthorized:
+		return ErrForbidden
+	case e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests:
+		return ErrBackend
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+func newApiError(endpoint string, statusCode int, body ErrorBody) *ApiError {
+	e := &ApiError{Endpoint: endpoint, StatusCode: statusCode, Message: body.Message}
+	if len(body.Errors) > 0 {
+		e.Reason = body.Errors[0].Reason
+	}
+	if len(e.Message) == 0 {
+		e.Message = http.StatusText(statusCode)
+	}
+	return e
+}
+
+func notFound(endpoint string, id string) *ApiError {
+	return &ApiError{Endpoint: endpoint, StatusCode: http.StatusNotFound, Reason: "notFound", Message: fmt.Sprintf("%s not found", id)}
+}
+
+func isQuotaReason(reason string) bool {
+	return reason == "quotaExceeded" || reason == "dailyLimitExceeded"
+}
+
+func isRetryable(statusCode int, reason string) bool {
+	if statusCode >= 500 || statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return reason == "rateLimitExceeded" || reason == "userRateLimitExceeded"
+}
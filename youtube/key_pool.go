@@ -0,0 +1,155 @@
+package youtube
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrNoKeys = errors.New("youtube: key pool has no keys")
+
+type KeyStats struct {
+	Key          string     `json:"key,omitempty"`
+	Calls        int        `json:"calls"`
+	Units        int        `json:"units"`
+	Errors       int        `json:"errors"`
+	Benched      bool       `json:"benched"`
+	BenchedUntil *time.Time `json:"benchedUntil,omitempty"`
+	Reason       string     `json:"reason,omitempty"`
+}
+
+type pooledKey struct {
+	key          string
+	quota        *QuotaLedger
+	calls        int
+	errors       int
+	benchedUntil time.Time
+	reason       string
+}
+
+type KeyPool struct {
+	Now   func() time.Time
+	mutex sync.Mutex
+	keys  []*pooledKey
+	next  int
+}
+
+// NewKeyPool gives each key a ledger of budget units, reset by the clock of the pool, so a Now set later applies to them.
+func NewKeyPool(budget int, keys ...string) *KeyPool {
+	pool := &KeyPool{Now: time.Now}
+	now := func() time.Time { return pool.Now() }
+	for _, key := range keys {
+		quota := NewQuotaLedger(budget)
+		quota.Now = now
+		pool.keys = append(pool.keys, &pooledKey{key: key, quota: quota})
+	}
+	return pool
+}
+
+func (p *KeyPool) Size() int {
+	return len(p.keys)
+}
+
+// Acquire picks the next key that is not benched and still has budget for the endpoint, charging its cost to that key.
+func (p *KeyPool) Acquire(endpoint string) (string, error) {
+	if len(p.keys) == 0 {
+		return "", ErrNoKeys
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := p.Now()
+	var resetAt time.Time
+	var lastErr error
+	for i := 0; i < len(p.keys); i++ {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if now.Before(k.benchedUntil) {
+			if resetAt.IsZero() || k.benchedUntil.Before(resetAt) {
+				resetAt = k.benchedUntil
+			}
+			continue
+		}
+		err := k.quota.Charge(endpoint)
+		if err != nil {
+			k.benchedUntil = k.quota.ResetAt()
+			k.reason = "quotaExceeded"
+			lastErr = err
+			if resetAt.IsZero() || k.benchedUntil.Before(resetAt) {
+				resetAt = k.benchedUntil
+			}
+			continue
+		}
+		k.calls++
+		p.next = (p.next + i + 1) % len(p.keys)
+		return k.key, nil
+	}
+	if lastErr != nil && len(p.keys) == 1 {
+		return "", lastErr
+	}
+	return "", &ErrQuotaExceeded{Endpoint: endpoint, Cost: QuotaCosts[endpoint], ResetAt: resetAt}
+}
+
+// Charge charges one more call to key, for a retry of a call it was acquired for.
+func (p *KeyPool) Charge(key string, endpoint string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, k := range p.keys {
+		if k.key == key {
+			if err := k.quota.Charge(endpoint); err != nil {
+				k.benchedUntil = k.quota.ResetAt()
+				k.reason = "quotaExceeded"
+				return err
+			}
+			k.calls++
+		}
+	}
+	return nil
+}
+
+func (p *KeyPool) Refund(key string, endpoint string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, k := range p.keys {
+		if k.key == key {
+			k.calls--
+			k.quota.Refund(endpoint)
+		}
+	}
+}
+
+// Bench takes a key out of rotation until the next quota reset.
+func (p *KeyPool) Bench(key string, reason string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, k := range p.keys {
+		if k.key == key {
+			k.errors++
+			k.benchedUntil = NextPacificMidnight(p.Now())
+			k.reason = reason
+		}
+	}
+}
+
+func (p *KeyPool) Stats() []KeyStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := p.Now()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{Key: mask(k.key), Calls: k.calls, Units: k.quota.Used(), Errors: k.errors}
+		if now.Before(k.benchedUntil) {
+			until := k.benchedUntil
+			s.Benched = true
+			s.BenchedUntil = &until
+			s.Reason = k.reason
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+func mask(key string) string {
+	if len(key) <= 6 {
+		return "***"
+	}
+	return key[:4] + "***" + key[len(key)-2:]
+}
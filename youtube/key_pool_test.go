@@ -0,0 +1,29 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyPoolReset(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, pacific)
+	pool := NewKeyPool(1, "key1")
+	pool.Now = func() time.Time { return now }
+	if _, err := pool.Acquire("videos"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := pool.Acquire("videos")
+	if !IsQuotaExceeded(err) {
+		t.Fatalf("err = %v; want the quota exceeded", err)
+	}
+	if resetAt := err.(*ErrQuotaExceeded).ResetAt; !resetAt.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, pacific)) {
+		t.Errorf("reset at %v; want the next Pacific midnight of the pool clock", resetAt)
+	}
+	now = now.Add(13 * time.Hour)
+	if _, err := pool.Acquire("videos"); err != nil {
+		t.Errorf("err = %v after the reset", err)
+	}
+	if stats := pool.Stats(); stats[0].Units != 1 || stats[0].Benched {
+		t.Errorf("stats = %+v; want 1 unit since the reset", stats[0])
+	}
+}
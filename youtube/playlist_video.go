@@ -16,6 +16,7 @@ type ItemsPlaylistVideo struct {
 	Id             string                       `mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
 	Snippet        *SnippetPlaylistVideo        `mapstructure:"snippet" json:"snippet,omitempty" gorm:"column:snippet;primary_key" bson:"snippet,omitempty" dynamodbav:"snippet,omitempty" firestore:"snippet,omitempty"`
 	ContentDetails *ContentDetailsPlaylistVideo `mapstructure:"contentDetails" json:"contentDetails,omitempty" gorm:"column:contentDetails;primary_key" bson:"contentDetails,omitempty" dynamodbav:"contentDetails,omitempty" firestore:"contentDetails,omitempty"`
+	Status         *StatusPlaylistVideo         `mapstructure:"status" json:"status,omitempty" gorm:"column:status;primary_key" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
 }
 
 type SnippetPlaylistVideo struct {
@@ -42,3 +43,8 @@ type ContentDetailsPlaylistVideo struct {
 	VideoId          string    `mapstructure:"videoId" json:"videoId,omitempty" gorm:"column:videoId;primary_key" bson:"videoId,omitempty" dynamodbav:"videoId,omitempty" firestore:"videoId,omitempty"`
 	VideoPublishedAt time.Time `mapstructure:"videoPublishedAt" json:"videoPublishedAt,omitempty" gorm:"column:videoPublishedAt;primary_key" bson:"videoPublishedAt,omitempty" dynamodbav:"videoPublishedAt,omitempty" firestore:"videoPublishedAt,omitempty"`
 }
+
+// StatusPlaylistVideo is the privacy of a playlist item: "privacyStatusUnspecified" for a deleted video.
+type StatusPlaylistVideo struct {
+	PrivacyStatus string `mapstructure:"privacyStatus" json:"privacyStatus,omitempty" gorm:"column:privacyStatus;primary_key" bson:"privacyStatus,omitempty" dynamodbav:"privacyStatus,omitempty" firestore:"privacyStatus,omitempty"`
+}
@@ -0,0 +1,143 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const DefaultDailyQuota = 10000
+
+var QuotaCosts = map[string]int{
+	"channels":        1,
+	"playlists":       1,
+	"playlistItems":   1,
+	"videos":          1,
+	"subscriptions":   1,
+	"videoCategories": 1,
+	"search":          100,
+}
+
+type ErrQuotaExceeded struct {
+	Endpoint string
+	Cost     int
+	Used     int
+	Budget   int
+	ResetAt  time.Time
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("youtube: quota exceeded calling %s (cost %d, used %d of %d), resets at %s", e.Endpoint, e.Cost, e.Used, e.Budget, e.ResetAt.Format(time.RFC3339))
+}
+
+func IsQuotaExceeded(err error) bool {
+	var e *ErrQuotaExceeded
+	return errors.As(err, &e)
+}
+
+type QuotaLedger struct {
+	Budget  int
+	Costs   map[string]int
+	Now     func() time.Time
+	mutex   sync.Mutex
+	used    int
+	usage   map[string]int
+	resetAt time.Time
+}
+
+func NewQuotaLedger(budget int) *QuotaLedger {
+	if budget <= 0 {
+		budget = DefaultDailyQuota
+	}
+	return &QuotaLedger{Budget: budget, Costs: QuotaCosts, Now: time.Now, usage: make(map[string]int)}
+}
+
+func (q *QuotaLedger) Charge(endpoint string) error {
+	cost, ok := q.Costs[endpoint]
+	if !ok {
+		cost = 1
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	if q.used+cost > q.Budget {
+		return &ErrQuotaExceeded{Endpoint: endpoint, Cost: cost, Used: q.used, Budget: q.Budget, ResetAt: q.resetAt}
+	}
+	q.used = q.used + cost
+	q.usage[endpoint] = q.usage[endpoint] + cost
+	return nil
+}
+
+// Refund gives back the cost of a call charged but not counted by YouTube.
+func (q *QuotaLedger) Refund(endpoint string) {
+	cost, ok := q.Costs[endpoint]
+	if !ok {
+		cost = 1
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	if cost > q.usage[endpoint] {
+		cost = q.usage[endpoint]
+	}
+	q.used = q.used - cost
+	q.usage[endpoint] = q.usage[endpoint] - cost
+}
+
+func (q *QuotaLedger) Used() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	return q.used
+}
+
+func (q *QuotaLedger) Remaining() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	return q.Budget - q.used
+}
+
+func (q *QuotaLedger) Usage() map[string]int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	usage := make(map[string]int, len(q.usage))
+	for k, v := range q.usage {
+		usage[k] = v
+	}
+	return usage
+}
+
+func (q *QuotaLedger) ResetAt() time.Time {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reset()
+	return q.resetAt
+}
+
+func (q *QuotaLedger) reset() {
+	now := q.Now()
+	if q.resetAt.IsZero() || !now.Before(q.resetAt) {
+		q.used = 0
+		q.usage = make(map[string]int)
+		q.resetAt = NextPacificMidnight(now)
+	}
+}
+
+var pacific = loadPacific()
+
+func loadPacific() *time.Location {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return time.FixedZone("PST", -8*60*60)
+	}
+	return location
+}
+
+// NextPacificMidnight returns the next quota reset, which YouTube does at midnight Pacific Time.
+func NextPacificMidnight(t time.Time) time.Time {
+	p := t.In(pacific)
+	return time.Date(p.Year(), p.Month(), p.Day()+1, 0, 0, 0, 0, pacific)
+}
@@ -11,11 +11,15 @@ type VideoTubeResponse struct {
 }
 
 type ItemsVideo struct {
-	Kind           string               `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind,omitempty"`
-	Etag           string               `mapstructure:"etag" json:"etag,omitempty" gorm:"column:etag" bson:"etag,omitempty" dynamodbav:"etag,omitempty" firestore:"etag,omitempty"`
-	Id             string               `mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
-	Snippet        *SnippetVideo        `mapstructure:"snippet" json:"snippet,omitempty" gorm:"column:snippet" bson:"snippet,omitempty" dynamodbav:"snippet,omitempty" firestore:"snippet,omitempty"`
-	ContentDetails *ContentDetailsVideo `mapstructure:"contentDetails" json:"contentDetails,omitempty" gorm:"column:contentDetails" bson:"contentDetails,omitempty" dynamodbav:"contentDetails,omitempty" firestore:"contentDetails,omitempty"`
+	Kind                 string                `mapstructure:"kind" json:"kind,omitempty" gorm:"column:kind" bson:"kind,omitempty" dynamodbav:"kind,omitempty" firestore:"kind,omitempty"`
+	Etag                 string                `mapstructure:"etag" json:"etag,omitempty" gorm:"column:etag" bson:"etag,omitempty" dynamodbav:"etag,omitempty" firestore:"etag,omitempty"`
+	Id                   string                `mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Snippet              *SnippetVideo         `mapstructure:"snippet" json:"snippet,omitempty" gorm:"column:snippet" bson:"snippet,omitempty" dynamodbav:"snippet,omitempty" firestore:"snippet,omitempty"`
+	ContentDetails       *ContentDetailsVideo  `mapstructure:"contentDetails" json:"contentDetails,omitempty" gorm:"column:contentDetails" bson:"contentDetails,omitempty" dynamodbav:"contentDetails,omitempty" firestore:"contentDetails,omitempty"`
+	Statistics           *StatisticsVideo      `mapstructure:"statistics" json:"statistics,omitempty" gorm:"column:statistics" bson:"statistics,omitempty" dynamodbav:"statistics,omitempty" firestore:"statistics,omitempty"`
+	Status               *StatusVideo          `mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
+	TopicDetails         *TopicDetailsVideo    `mapstructure:"topicDetails" json:"topicDetails,omitempty" gorm:"column:topicDetails" bson:"topicDetails,omitempty" dynamodbav:"topicDetails,omitempty" firestore:"topicDetails,omitempty"`
+	LiveStreamingDetails *LiveStreamingDetails `mapstructure:"liveStreamingDetails" json:"liveStreamingDetails,omitempty" gorm:"column:liveStreamingDetails" bson:"liveStreamingDetails,omitempty" dynamodbav:"liveStreamingDetails,omitempty" firestore:"liveStreamingDetails,omitempty"`
 }
 
 type SnippetVideo struct {
@@ -44,12 +48,42 @@ type ContentDetailsVideo struct {
 	Definition        string            `mapstructure:"definition" json:"definition,omitempty" gorm:"column:definition" bson:"definition,omitempty" dynamodbav:"definition,omitempty" firestore:"definition,omitempty"`
 	Caption           string            `mapstructure:"caption" json:"caption,omitempty" gorm:"column:caption" bson:"caption,omitempty" dynamodbav:"caption,omitempty" firestore:"caption,omitempty"`
 	LicensedContent   bool              `mapstructure:"licensedContent" json:"licensedContent,omitempty" gorm:"column:licensedContent" bson:"licensedContent,omitempty" dynamodbav:"licensedContent,omitempty" firestore:"licensedContent,omitempty"`
-	ContentRating     interface{}       `mapstructure:"contentRating" json:"contentRating,omitempty" gorm:"column:contentRating" bson:"contentRating,omitempty" dynamodbav:"contentRating,omitempty" firestore:"contentRating,omitempty"`
+	ContentRating     ContentRating     `mapstructure:"contentRating" json:"contentRating,omitempty" gorm:"column:contentRating" bson:"contentRating,omitempty" dynamodbav:"contentRating,omitempty" firestore:"contentRating,omitempty"`
 	Projection        string            `mapstructure:"projection" json:"projection,omitempty" gorm:"column:projection" bson:"projection,omitempty" dynamodbav:"projection,omitempty" firestore:"projection,omitempty"`
 	RegionRestriction RegionRestriction `mapstructure:"regionRestriction" json:"regionRestriction,omitempty" gorm:"column:regionRestriction" bson:"regionRestriction,omitempty" dynamodbav:"regionRestriction,omitempty" firestore:"regionRestriction,omitempty"`
 }
 
+type ContentRating struct {
+	YtRating string `mapstructure:"ytRating" json:"ytRating,omitempty" gorm:"column:ytRating" bson:"ytRating,omitempty" dynamodbav:"ytRating,omitempty" firestore:"ytRating,omitempty"`
+}
+
 type RegionRestriction struct {
 	Allow   []string `mapstructure:"allow" json:"allow,omitempty" gorm:"column:allow" bson:"allow,omitempty" dynamodbav:"allow,omitempty" firestore:"allow,omitempty"`
 	Blocked []string `mapstructure:"blocked" json:"blocked,omitempty" gorm:"column:blocked" bson:"blocked,omitempty" dynamodbav:"blocked,omitempty" firestore:"blocked,omitempty"`
 }
+
+type StatisticsVideo struct {
+	ViewCount    string `mapstructure:"viewCount" json:"viewCount,omitempty" gorm:"column:viewCount" bson:"viewCount,omitempty" dynamodbav:"viewCount,omitempty" firestore:"viewCount,omitempty"`
+	LikeCount    string `mapstructure:"likeCount" json:"likeCount,omitempty" gorm:"column:likeCount" bson:"likeCount,omitempty" dynamodbav:"likeCount,omitempty" firestore:"likeCount,omitempty"`
+	CommentCount string `mapstructure:"commentCount" json:"commentCount,omitempty" gorm:"column:commentCount" bson:"commentCount,omitempty" dynamodbav:"commentCount,omitempty" firestore:"commentCount,omitempty"`
+}
+
+type StatusVideo struct {
+	License       string `mapstructure:"license" json:"license,omitempty" gorm:"column:license" bson:"license,omitempty" dynamodbav:"license,omitempty" firestore:"license,omitempty"`
+	Embeddable    bool   `mapstructure:"embeddable" json:"embeddable,omitempty" gorm:"column:embeddable" bson:"embeddable,omitempty" dynamodbav:"embeddable,omitempty" firestore:"embeddable,omitempty"`
+	PrivacyStatus string `mapstructure:"privacyStatus" json:"privacyStatus,omitempty" gorm:"column:privacyStatus" bson:"privacyStatus,omitempty" dynamodbav:"privacyStatus,omitempty" firestore:"privacyStatus,omitempty"`
+}
+
+type TopicDetailsVideo struct {
+	TopicIds         []string `mapstructure:"topicIds" json:"topicIds,omitempty" gorm:"column:topicIds" bson:"topicIds,omitempty" dynamodbav:"topicIds,omitempty" firestore:"topicIds,omitempty"`
+	RelevantTopicIds []string `mapstructure:"relevantTopicIds" json:"relevantTopicIds,omitempty" gorm:"column:relevantTopicIds" bson:"relevantTopicIds,omitempty" dynamodbav:"relevantTopicIds,omitempty" firestore:"relevantTopicIds,omitempty"`
+}
+
+// LiveStreamingDetails is returned only for videos that are, were or will be live streams.
+type LiveStreamingDetails struct {
+	ScheduledStartTime *time.Time `mapstructure:"scheduledStartTime" json:"scheduledStartTime,omitempty" gorm:"column:scheduledStartTime" bson:"scheduledStartTime,omitempty" dynamodbav:"scheduledStartTime,omitempty" firestore:"scheduledStartTime,omitempty"`
+	ScheduledEndTime   *time.Time `mapstructure:"scheduledEndTime" json:"scheduledEndTime,omitempty" gorm:"column:scheduledEndTime" bson:"scheduledEndTime,omitempty" dynamodbav:"scheduledEndTime,omitempty" firestore:"scheduledEndTime,omitempty"`
+	ActualStartTime    *time.Time `mapstructure:"actualStartTime" json:"actualStartTime,omitempty" gorm:"column:actualStartTime" bson:"actualStartTime,omitempty" dynamodbav:"actualStartTime,omitempty" firestore:"actualStartTime,omitempty"`
+	ActualEndTime      *time.Time `mapstructure:"actualEndTime" json:"actualEndTime,omitempty" gorm:"column:actualEndTime" bson:"actualEndTime,omitempty" dynamodbav:"actualEndTime,omitempty" firestore:"actualEndTime,omitempty"`
+	ConcurrentViewers  string     `mapstructure:"concurrentViewers" json:"concurrentViewers,omitempty" gorm:"column:concurrentViewers" bson:"concurrentViewers,omitempty" dynamodbav:"concurrentViewers,omitempty" firestore:"concurrentViewers,omitempty"`
+}